@@ -1,22 +1,43 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/changefeed"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
 	"github.com/rixtrayker/ticketing-system/internal/db"
+	"github.com/rixtrayker/ticketing-system/internal/events"
 	"github.com/rixtrayker/ticketing-system/internal/graph"
 	"github.com/rixtrayker/ticketing-system/internal/graph/generated"
+	"github.com/rixtrayker/ticketing-system/internal/mailin"
+	"github.com/rixtrayker/ticketing-system/internal/notify"
+	"github.com/rixtrayker/ticketing-system/internal/outbox"
 	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"github.com/rixtrayker/ticketing-system/internal/scan"
+	"github.com/rixtrayker/ticketing-system/internal/scheduler"
 	"github.com/rixtrayker/ticketing-system/internal/service"
+	"github.com/rixtrayker/ticketing-system/internal/webhook"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 const (
@@ -26,6 +47,18 @@ const (
 	writeTimeout         = 15 * time.Second
 	idleTimeout          = 60 * time.Second
 	readHeaderTimeout    = 5 * time.Second
+	defaultAccessTTL     = 15 * time.Minute
+	defaultRefreshTTL    = 7 * 24 * time.Hour
+	developmentJWTSecret = "development-secret"
+	defaultScanInterval  = time.Minute
+	defaultLeadTime      = 24 * time.Hour
+	defaultOutboxPoll    = 5 * time.Second
+	defaultWebhookPoll   = 5 * time.Second
+	wsKeepAliveInterval  = 10 * time.Second
+	defaultSMTPPort      = 587
+	defaultMailFrom      = "Ticketing System <noreply@localhost>"
+	defaultMaildirPoll   = 30 * time.Second
+	defaultAssetRules    = "CRITICAL=OUT_OF_SERVICE,HIGH=MAINTENANCE_NEEDED"
 )
 
 // HealthResponse represents the health check response
@@ -44,6 +77,18 @@ func main() {
 	config := getConfig()
 	logger.Printf("Starting server on port %s", config.Port)
 
+	if config.JWTSecret == "" {
+		if config.Environment != "development" {
+			logger.Fatal("JWT_SECRET must be set outside development")
+		}
+		logger.Println("JWT_SECRET not set, using insecure development secret")
+		config.JWTSecret = developmentJWTSecret
+	}
+	assetCoupling, err := service.ParseAssetCouplingRules(config.AssetStatusRules)
+	if err != nil {
+		logger.Fatalf("Invalid ASSET_STATUS_RULES: %v", err)
+	}
+
 	// Initialize database connection
 	dbConfig := db.NewConfig()
 	if err := db.Connect(dbConfig); err != nil {
@@ -61,28 +106,162 @@ func main() {
 	ticketRepo := repository.NewTicketRepository(db.DB)
 	userRepo := repository.NewUserRepository(db.DB)
 	assetRepo := repository.NewAssetRepository(db.DB)
+	maintenanceScheduleRepo := repository.NewMaintenanceScheduleRepository(db.DB)
+	ticketEventRepo := repository.NewTicketEventRepository(db.DB)
+	commentRepo := repository.NewCommentRepository(db.DB)
+	searchRepo := repository.NewSearchRepository(db.DB)
+	maintenanceRecordRepo := repository.NewMaintenanceRecordRepository(db.DB)
+	partRepo := repository.NewPartRepository(db.DB)
+	stockMovementRepo := repository.NewStockMovementRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	inboundEmailRepo := repository.NewInboundEmailRepository(db.DB)
+	slaPolicyRepo := repository.NewSLAPolicyRepository(db.DB)
+	businessCalendarRepo := repository.NewBusinessCalendarRepository(db.DB)
+	escalationRuleRepo := repository.NewEscalationRuleRepository(db.DB)
+	locationRepo := repository.NewLocationRepository(db.DB)
+	assetStatusChangeRepo := repository.NewAssetStatusChangeRepository(db.DB)
+	assetTypeSchemaRepo := repository.NewAssetTypeSchemaRepository(db.DB)
+	transactor := repository.NewTransactor(db.DB)
+
+	// In-process event bus feeding GraphQL subscriptions
+	eventBus := events.NewBus()
 
 	// Initialize services
-	ticketService := service.NewTicketService(ticketRepo, userRepo, assetRepo)
+	tokenManager := auth.NewTokenManager(config.JWTSecret, config.AccessTokenTTL, config.RefreshTokenTTL)
+	authService := service.NewAuthService(userRepo, tokenManager)
+	ticketService := service.NewTicketService(transactor, ticketRepo, ticketEventRepo, userRepo, assetRepo, slaPolicyRepo, businessCalendarRepo, assetStatusChangeRepo, assetCoupling, outboxRepo, eventBus)
+	userService := service.NewUserService(userRepo)
+	commentService := service.NewCommentService(transactor, commentRepo, ticketRepo, ticketEventRepo, userRepo, assetRepo, slaPolicyRepo, businessCalendarRepo, outboxRepo, eventBus)
+	searchService := service.NewSearchService(searchRepo)
+	maintenanceService := service.NewMaintenanceService(transactor, clock.System(), config.MaintenanceLeadTime,
+		maintenanceScheduleRepo, maintenanceRecordRepo, ticketRepo, ticketEventRepo, assetRepo, partRepo, stockMovementRepo, slaPolicyRepo, businessCalendarRepo, assetStatusChangeRepo, assetCoupling, outboxRepo, eventBus)
+	assetService := service.NewAssetService(transactor, clock.System(), assetRepo, locationRepo, assetStatusChangeRepo,
+		ticketRepo, maintenanceScheduleRepo, maintenanceRecordRepo, assetTypeSchemaRepo, outboxRepo, eventBus)
+	webhookService := service.NewWebhookService(clock.System(), webhookRepo, webhookDeliveryRepo)
+	slaService := service.NewSLAService(slaPolicyRepo)
+	calendarService := service.NewCalendarService(transactor, businessCalendarRepo)
+	locationService := service.NewLocationService(locationRepo)
+	assetTypeSchemaService := service.NewAssetTypeSchemaService(assetTypeSchemaRepo)
+	escalationService := service.NewEscalationService(transactor, clock.System(), escalationRuleRepo, ticketRepo, ticketEventRepo,
+		commentRepo, userRepo, assetRepo, slaPolicyRepo, businessCalendarRepo, assetStatusChangeRepo, assetCoupling, outboxRepo, eventBus)
+	inventoryService := service.NewInventoryService(transactor, clock.System(), partRepo, stockMovementRepo, ticketRepo, ticketEventRepo, assetRepo, slaPolicyRepo, businessCalendarRepo, outboxRepo, eventBus)
+
+	// Create the first administrator so the API can be used at all
+	if config.AdminEmail != "" && config.AdminPassword != "" {
+		created, err := authService.BootstrapAdmin(config.AdminEmail, config.AdminPassword)
+		if err != nil {
+			logger.Fatalf("Failed to bootstrap admin user: %v", err)
+		}
+		if created {
+			logger.Printf("Created initial admin user %s", config.AdminEmail)
+		}
+	}
+
+	// Start the maintenance scheduler in the background
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go scheduler.New(maintenanceService, config.MaintenanceScanInterval, logger).Run(backgroundCtx)
+	logger.Printf("Maintenance scheduler running every %s", config.MaintenanceScanInterval)
+	go scheduler.NewEscalator(escalationService, config.EscalationScanInterval, logger).Run(backgroundCtx)
+	logger.Printf("Escalation rules evaluated every %s", config.EscalationScanInterval)
+
+	// Follow writes made by other instances so their subscribers are notified too
+	changeFeed := changefeed.New(dbConfig.DSN(), dbConfig.ApplicationName, logger)
+	go changeFeed.Run(backgroundCtx)
+	go changefeed.RelayTicketEvents(backgroundCtx, changeFeed, eventBus)
+	logger.Printf("Change feed listening on %s as %s", changefeed.Channel, dbConfig.ApplicationName)
+
+	// Send webhook deliveries queued from outbox events
+	webhookSender := webhook.NewSender(webhookRepo, webhookDeliveryRepo, clock.System(), config.WebhookPollInterval, logger)
+	go webhookSender.Run(backgroundCtx)
+
+	// Deliver outbox events, waking as soon as any instance records new ones
+	dispatcher := outbox.NewDispatcher(outboxRepo, clock.System(), config.OutboxPollInterval, logger)
+	dispatcher.Register("webhooks", webhook.NewHandler(webhookRepo, webhookDeliveryRepo, webhookSender))
+	mailSender, err := newMailSender(config, logger)
+	if err != nil {
+		logger.Fatalf("Failed to configure email: %v", err)
+	}
+	dispatcher.Register("notifications", notify.NewNotifier(mailSender, clock.System(), ticketRepo, userRepo, maintenanceScheduleRepo, escalationRuleRepo, notificationRepo))
+	go dispatcher.Run(backgroundCtx, changeFeed.Subscribe(backgroundCtx, changefeed.ForTables(changefeed.TableOutboxEvents)))
+	logger.Printf("Outbox dispatcher polling every %s", config.OutboxPollInterval)
+
+	// Open tickets from inbound email and thread replies onto them
+	mailGateway := mailin.NewGateway(userRepo, inboundEmailRepo, ticketService, commentService, logger)
+	if config.InboundMaildir != "" {
+		go mailin.NewMaildir(config.InboundMaildir, mailGateway, config.InboundMaildirPoll, logger).Run(backgroundCtx)
+		logger.Printf("Reading inbound email from %s every %s", config.InboundMaildir, config.InboundMaildirPoll)
+	}
+	if config.InboundSMTPAddr != "" {
+		smtpServer := mailin.NewSMTPServer(config.InboundSMTPAddr, config.InboundSMTPDomain, mailGateway, logger)
+		go func() {
+			if err := smtpServer.ListenAndServe(backgroundCtx); err != nil {
+				logger.Fatalf("Inbound SMTP listener failed: %v", err)
+			}
+		}()
+		logger.Printf("Receiving inbound email over SMTP on %s", config.InboundSMTPAddr)
+	}
 
 	// Create GraphQL resolver with dependencies
 	resolver := &graph.Resolver{
-		DB:            db.DB,
-		TicketService: ticketService,
+		DB:                      db.DB,
+		AuthService:             authService,
+		TicketService:           ticketService,
+		UserService:             userService,
+		CommentService:          commentService,
+		SearchService:           searchService,
+		MaintenanceService:      maintenanceService,
+		InventoryService:        inventoryService,
+		AssetService:            assetService,
+		WebhookService:          webhookService,
+		SLAService:              slaService,
+		CalendarService:         calendarService,
+		LocationService:         locationService,
+		AssetTypeSchemaService:  assetTypeSchemaService,
+		EscalationService:       escalationService,
+		Events:                  eventBus,
+		AssetRepo:               assetRepo,
+		MaintenanceScheduleRepo: maintenanceScheduleRepo,
 	}
 
 	// Create GraphQL server with configuration
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
 		Resolvers: resolver,
 		Directives: generated.DirectiveRoot{
-			// Add any custom directives here
+			HasRole: graph.HasRole,
 		},
 		Complexity: generated.ComplexityRoot{
 			// Add any custom complexity functions here
 		},
 	}))
 
-	// GraphQL server is ready (no additional middleware needed)
+	// Subscriptions are served over WebSocket; clients authenticate with an
+	// Authorization entry in the connection_init payload
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: wsKeepAliveInterval,
+		InitFunc:              websocketInit(authService),
+		Upgrader: websocket.Upgrader{
+			// Origins are not restricted, matching corsMiddleware
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
+	// Reject anonymous access to everything but login/refreshToken
+	srv.AroundRootFields(graph.RequireAuthentication)
 
 	// Create HTTP server
 	mux := http.NewServeMux()
@@ -98,7 +277,12 @@ func main() {
 		mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
 		logger.Println("GraphQL Playground enabled at /")
 	}
-	mux.Handle("/query", corsMiddleware(recoveryMiddleware(loggingMiddleware(graphqlMiddleware(logger)(srv), logger))))
+	mux.Handle("/query", corsMiddleware(recoveryMiddleware(loggingMiddleware(authMiddleware(authService, logger)(graphqlMiddleware(logger)(srv)), logger))))
+
+	// Asset QR code images, label sheets and the scan landing route
+	scanHandler := corsMiddleware(recoveryMiddleware(loggingMiddleware(authMiddleware(authService, logger)(scan.NewHandler(assetService, config.PublicURL, logger)), logger)))
+	mux.Handle("/assets/", scanHandler)
+	mux.Handle("/scan/", scanHandler)
 
 	// Configure HTTP server with production settings
 	server := &http.Server{
@@ -132,6 +316,7 @@ func main() {
 	// Block until we receive a signal
 	<-quit
 	logger.Println("Shutting down server...")
+	stopBackground()
 
 	// Create a context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
@@ -157,17 +342,77 @@ func main() {
 
 // Config holds application configuration
 type Config struct {
-	Port        string
-	Environment string
-	Version     string
+	Port            string
+	PublicURL       string
+	Environment     string
+	Version         string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	AdminEmail      string
+	AdminPassword   string
+
+	MaintenanceScanInterval time.Duration
+	MaintenanceLeadTime     time.Duration
+	EscalationScanInterval  time.Duration
+	OutboxPollInterval      time.Duration
+	WebhookPollInterval     time.Duration
+
+	// AssetStatusRules maps ticket priorities to the asset status their open
+	// tickets imply, as PRIORITY=STATUS pairs, or "off"
+	AssetStatusRules string
+
+	// Email notifications are sent through SMTPHost when set; otherwise they
+	// are written to MailDir, or logged when that is empty too
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+	MailDir      string
+
+	// Inbound email is read from InboundMaildir and/or received by an SMTP
+	// listener on InboundSMTPAddr
+	InboundMaildir     string
+	InboundMaildirPoll time.Duration
+	InboundSMTPAddr    string
+	InboundSMTPDomain  string
 }
 
 // getConfig returns application configuration from environment variables
 func getConfig() *Config {
+	port := getEnv("PORT", defaultPort)
 	return &Config{
-		Port:        getEnv("PORT", defaultPort),
+		Port:        port,
+		PublicURL:   getEnv("PUBLIC_URL", "http://localhost:"+port),
 		Environment: getEnv("ENVIRONMENT", "development"),
 		Version:     getEnv("VERSION", "1.0.0"),
+
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", defaultAccessTTL),
+		RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", defaultRefreshTTL),
+		AdminEmail:      os.Getenv("ADMIN_EMAIL"),
+		AdminPassword:   os.Getenv("ADMIN_PASSWORD"),
+
+		MaintenanceScanInterval: getEnvDuration("MAINTENANCE_SCAN_INTERVAL", defaultScanInterval),
+		MaintenanceLeadTime:     getEnvDuration("MAINTENANCE_LEAD_TIME", defaultLeadTime),
+		EscalationScanInterval:  getEnvDuration("ESCALATION_SCAN_INTERVAL", defaultScanInterval),
+		OutboxPollInterval:      getEnvDuration("OUTBOX_POLL_INTERVAL", defaultOutboxPoll),
+		WebhookPollInterval:     getEnvDuration("WEBHOOK_POLL_INTERVAL", defaultWebhookPoll),
+
+		AssetStatusRules: getEnv("ASSET_STATUS_RULES", defaultAssetRules),
+
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnvInt("SMTP_PORT", defaultSMTPPort),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		MailFrom:     getEnv("MAIL_FROM", defaultMailFrom),
+		MailDir:      os.Getenv("MAIL_DIR"),
+
+		InboundMaildir:     os.Getenv("INBOUND_MAILDIR"),
+		InboundMaildirPoll: getEnvDuration("INBOUND_MAILDIR_POLL_INTERVAL", defaultMaildirPoll),
+		InboundSMTPAddr:    os.Getenv("INBOUND_SMTP_ADDR"),
+		InboundSMTPDomain:  getEnv("INBOUND_SMTP_DOMAIN", "localhost"),
 	}
 }
 
@@ -179,6 +424,47 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvDuration parses a duration environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt parses an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// newMailSender returns the email sender selected by the configuration
+func newMailSender(config *Config, logger *log.Logger) (notify.Sender, error) {
+	switch {
+	case config.SMTPHost != "":
+		logger.Printf("Sending email through %s:%d", config.SMTPHost, config.SMTPPort)
+		return notify.NewSMTPSender(notify.SMTPConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.MailFrom,
+		})
+	case config.MailDir != "":
+		logger.Printf("Writing email to %s", config.MailDir)
+		return notify.NewFileSender(config.MailDir, config.MailFrom)
+	default:
+		logger.Println("SMTP_HOST not set, logging email instead of sending it")
+		return notify.NewLogSender(logger), nil
+	}
+}
+
 // healthCheckHandler returns a health check endpoint
 func healthCheckHandler(logger *log.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -288,6 +574,16 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Hijack lets WebSocket upgrades take over the wrapped connection
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	rw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
 // recoveryMiddleware recovers from panics
 func recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -332,6 +628,52 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// authMiddleware resolves a bearer token into the authenticated user and stores
+// it in the request context. Requests without a valid token continue anonymously
+// and are rejected by the GraphQL layer where authentication is required.
+func authMiddleware(authService service.AuthService, logger *log.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := authService.Authenticate(token)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidToken) {
+					logger.Printf("Error authenticating request: %v", err)
+					http.Error(w, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// websocketInit authenticates a WebSocket connection from the Authorization
+// entry of its connection_init payload. Connections without one keep the user
+// resolved from the upgrade request, if any.
+func websocketInit(authService service.AuthService) transport.WebsocketInitFunc {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		header := payload.Authorization()
+		if header == "" {
+			return ctx, nil, nil
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+		user, err := authService.Authenticate(token)
+		if err != nil {
+			return ctx, nil, err
+		}
+		return auth.WithUser(ctx, user), nil, nil
+	}
+}
+
 // graphqlMiddleware adds GraphQL-specific middleware
 func graphqlMiddleware(logger *log.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
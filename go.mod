@@ -6,8 +6,12 @@ toolchain go1.24.4
 
 require (
 	github.com/99designs/gqlgen v0.17.75
+	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.6.0
+	github.com/gorilla/websocket v1.5.0
+	github.com/jackc/pgx/v5 v5.5.5
 	github.com/vektah/gqlparser/v2 v2.5.28
+	golang.org/x/crypto v0.22.0
 	gorm.io/datatypes v1.2.5
 	gorm.io/driver/postgres v1.5.6
 	gorm.io/gorm v1.25.11
@@ -18,16 +22,13 @@ require (
 	github.com/agnivade/levenshtein v1.2.1 // indirect
 	github.com/go-sql-driver/mysql v1.8.1 // indirect
 	github.com/go-viper/mapstructure/v2 v2.2.1 // indirect
-	github.com/gorilla/websocket v1.5.0 // indirect
 	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20231201235250-de7065d80cb9 // indirect
-	github.com/jackc/pgx/v5 v5.5.5 // indirect
 	github.com/jackc/puddle/v2 v2.2.1 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
 	github.com/sosodev/duration v1.3.1 // indirect
-	golang.org/x/crypto v0.22.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
 	gorm.io/driver/mysql v1.5.6 // indirect
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+type contextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user stored in ctx, if any
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(contextKey{}).(*models.User)
+	return user, ok && user != nil
+}
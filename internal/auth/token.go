@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// Token types carried in the "typ" claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+var (
+	// ErrInvalidToken is returned when a token cannot be parsed or verified
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Claims are the JWT claims issued for an authenticated user
+type Claims struct {
+	Role      models.UserRole `json:"role"`
+	TokenType string          `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the subject of the token as a UUID
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// TokenPair is an access token and the refresh token used to renew it
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// TokenManager issues and verifies HMAC signed JWTs
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager creates a token manager signing with the given secret
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+	return &TokenManager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Issue creates a new access and refresh token pair for the user
+func (m *TokenManager) Issue(user *models.User) (*TokenPair, error) {
+	now := time.Now()
+	accessExpiry := now.Add(m.accessTTL)
+
+	access, err := m.sign(user, TokenTypeAccess, now, accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := m.sign(user, TokenTypeRefresh, now, now.Add(m.refreshTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresAt:    accessExpiry,
+	}, nil
+}
+
+// ParseAccessToken verifies an access token and returns its claims
+func (m *TokenManager) ParseAccessToken(token string) (*Claims, error) {
+	return m.parse(token, TokenTypeAccess)
+}
+
+// ParseRefreshToken verifies a refresh token and returns its claims
+func (m *TokenManager) ParseRefreshToken(token string) (*Claims, error) {
+	return m.parse(token, TokenTypeRefresh)
+}
+
+func (m *TokenManager) sign(user *models.User, tokenType string, issuedAt, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		Role:      user.Role,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID.String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %w", tokenType, err)
+	}
+	return signed, nil
+}
+
+func (m *TokenManager) parse(token, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.TokenType != tokenType {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
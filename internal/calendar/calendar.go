@@ -0,0 +1,217 @@
+// Package calendar does time arithmetic in working time: the working hours of
+// each weekday in a time zone, minus holidays. A nil *Calendar is always
+// working, so callers without a calendar get plain wall-clock arithmetic.
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// maxDays bounds how far ahead the calendar searches for working time
+const maxDays = 4 * 366
+
+// Period is a span of working time within a day, as offsets from midnight.
+// End may be 24h to work until midnight.
+type Period struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Date is a calendar day
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the day t falls on in its location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{y, m, d}
+}
+
+// Calendar holds working hours and holidays
+type Calendar struct {
+	location *time.Location
+	week     [7][]Period
+	holidays map[Date]bool
+}
+
+// New creates a Calendar in location with the given working periods per
+// weekday. There must be some working time in the week.
+func New(location *time.Location, week map[time.Weekday][]Period, holidays []Date) (*Calendar, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	c := &Calendar{location: location, holidays: make(map[Date]bool, len(holidays))}
+	working := false
+	for day, periods := range week {
+		if day < time.Sunday || day > time.Saturday {
+			return nil, fmt.Errorf("invalid weekday %d", day)
+		}
+		sorted := append([]Period(nil), periods...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+		for i, p := range sorted {
+			if p.Start < 0 || p.End > 24*time.Hour || p.Start >= p.End {
+				return nil, fmt.Errorf("invalid working period on %s", day)
+			}
+			if i > 0 && p.Start < sorted[i-1].End {
+				return nil, fmt.Errorf("overlapping working periods on %s", day)
+			}
+		}
+		c.week[day] = sorted
+		working = working || len(sorted) > 0
+	}
+	if !working {
+		return nil, errors.New("a calendar needs working hours on at least one weekday")
+	}
+	for _, d := range holidays {
+		c.holidays[d] = true
+	}
+	return c, nil
+}
+
+// Location returns the time zone of the calendar
+func (c *Calendar) Location() *time.Location {
+	if c == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// IsWorking reports whether t falls in working time
+func (c *Calendar) IsWorking(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	t = t.In(c.location)
+	for _, span := range c.spans(DateOf(t)) {
+		if !t.Before(span.start) && t.Before(span.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns t if it falls in working time, or else the start of the next
+// working period
+func (c *Calendar) Next(t time.Time) time.Time {
+	return c.Add(t, 0)
+}
+
+// Add returns the time d of working time after t. Non-working time between
+// them is skipped; a zero d moves t forward to working time.
+func (c *Calendar) Add(t time.Time, d time.Duration) time.Time {
+	if c == nil {
+		return t.Add(d)
+	}
+	cur := t.In(c.location)
+	day := DateOf(cur)
+	for i := 0; i < maxDays; i++ {
+		for _, span := range c.spans(day) {
+			if !span.end.After(cur) {
+				continue
+			}
+			from := latest(cur, span.start)
+			if available := span.end.Sub(from); d < available {
+				return from.Add(d).In(t.Location())
+			} else if d == available {
+				return span.end.In(t.Location())
+			} else {
+				d -= available
+			}
+		}
+		day = day.next(c.location)
+	}
+	// Unreachable for valid calendars, which work every week
+	return t.Add(d)
+}
+
+// Between returns the working time from from until to, or zero when to is
+// not after from
+func (c *Calendar) Between(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+	if c == nil {
+		return to.Sub(from)
+	}
+	from, to = from.In(c.location), to.In(c.location)
+	var total time.Duration
+	for day := DateOf(from); !day.start(c.location).After(to); day = day.next(c.location) {
+		for _, span := range c.spans(day) {
+			start, end := latest(from, span.start), earliest(to, span.end)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+// span is a working period on a particular day
+type span struct {
+	start, end time.Time
+}
+
+// spans returns the working periods of a day, none on holidays
+func (c *Calendar) spans(day Date) []span {
+	if c.holidays[day] {
+		return nil
+	}
+	start := day.start(c.location)
+	periods := c.week[start.Weekday()]
+	spans := make([]span, len(periods))
+	for i, p := range periods {
+		spans[i] = span{start: day.at(p.Start, c.location), end: day.at(p.End, c.location)}
+	}
+	return spans
+}
+
+// start returns midnight at the beginning of the day
+func (d Date) start(location *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, location)
+}
+
+// at returns the wall-clock time offset into the day, so working hours keep
+// their local time across daylight saving changes
+func (d Date) at(offset time.Duration, location *time.Location) time.Time {
+	hours := int(offset / time.Hour)
+	minutes := int(offset % time.Hour / time.Minute)
+	return time.Date(d.Year, d.Month, d.Day, hours, minutes, 0, 0, location)
+}
+
+func (d Date) next(location *time.Location) Date {
+	return DateOf(time.Date(d.Year, d.Month, d.Day+1, 0, 0, 0, 0, location))
+}
+
+// String formats the date as YYYY-MM-DD
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// ParseDate parses a YYYY-MM-DD date
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", s)
+	}
+	return DateOf(t), nil
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,203 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location
+}
+
+// office works 09:00-17:00 on weekdays in London; 2026-12-25 and 2026-12-28
+// are holidays
+func office(t *testing.T) *Calendar {
+	t.Helper()
+	day := []Period{{9 * time.Hour, 17 * time.Hour}}
+	c, err := New(mustLoad(t, "Europe/London"), map[time.Weekday][]Period{
+		time.Monday: day, time.Tuesday: day, time.Wednesday: day, time.Thursday: day, time.Friday: day,
+	}, []Date{{2026, time.December, 25}, {2026, time.December, 28}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// nights works 22:00-06:00 every night in London, written as the end of one
+// day and the start of the next
+func nights(t *testing.T) *Calendar {
+	t.Helper()
+	night := []Period{{0, 6 * time.Hour}, {22 * time.Hour, 24 * time.Hour}}
+	week := make(map[time.Weekday][]Period)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		week[day] = night
+	}
+	c, err := New(mustLoad(t, "Europe/London"), week, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]map[time.Weekday][]Period{
+		"no working time":  {time.Monday: nil},
+		"empty period":     {time.Monday: {{9 * time.Hour, 9 * time.Hour}}},
+		"past midnight":    {time.Monday: {{22 * time.Hour, 30 * time.Hour}}},
+		"overlapping":      {time.Monday: {{13 * time.Hour, 17 * time.Hour}, {9 * time.Hour, 14 * time.Hour}}},
+		"invalid weekday":  {time.Weekday(7): {{9 * time.Hour, 17 * time.Hour}}},
+		"negative offsets": {time.Monday: {{-time.Hour, time.Hour}}},
+	}
+	for name, week := range tests {
+		if _, err := New(nil, week, nil); err == nil {
+			t.Errorf("%s: created a calendar, want an error", name)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	london := mustLoad(t, "Europe/London")
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, london)
+	}
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		from     time.Time
+		d        time.Duration
+		want     time.Time
+	}{
+		{"within a day", office(t), at(time.March, 11, 10, 0), 3 * time.Hour, at(time.March, 11, 13, 0)},
+		{"to the end of a period", office(t), at(time.March, 13, 16, 0), time.Hour, at(time.March, 13, 17, 0)},
+		{"over a weekend", office(t), at(time.March, 13, 15, 0), 3 * time.Hour, at(time.March, 16, 10, 0)},
+		{"from a weekend", office(t), at(time.March, 14, 12, 0), time.Hour, at(time.March, 16, 10, 0)},
+		{"over holidays", office(t), at(time.December, 24, 16, 0), 2 * time.Hour, at(time.December, 29, 10, 0)},
+		{"over several days", office(t), at(time.March, 11, 9, 0), 20 * time.Hour, at(time.March, 13, 13, 0)},
+		{"overnight", nights(t), at(time.January, 13, 23, 0), 2 * time.Hour, at(time.January, 14, 1, 0)},
+		{"to the next night", nights(t), at(time.January, 14, 5, 0), 2 * time.Hour, at(time.January, 14, 23, 0)},
+		// the clocks go forward at 01:00 on 29 March, so that night is an
+		// hour shorter
+		{"overnight into summer time", nights(t), at(time.March, 28, 22, 0), 7 * time.Hour, at(time.March, 29, 6, 0)},
+		{"past the short night", nights(t), at(time.March, 28, 22, 0), 7*time.Hour + 30*time.Minute, at(time.March, 29, 22, 30)},
+		// the clocks go back at 02:00 on 25 October, so that night is an
+		// hour longer
+		{"overnight into winter time", nights(t), at(time.October, 24, 22, 0), 9 * time.Hour, at(time.October, 25, 6, 0)},
+		{"office hours across the spring change", office(t), at(time.March, 27, 16, 0), 2 * time.Hour, at(time.March, 30, 10, 0)},
+		{"office hours across the autumn change", office(t), at(time.October, 23, 16, 0), 2 * time.Hour, at(time.October, 26, 10, 0)},
+		{"no calendar", nil, at(time.March, 14, 12, 0), time.Hour, at(time.March, 14, 13, 0)},
+	}
+	for _, tc := range tests {
+		if got := tc.calendar.Add(tc.from, tc.d); !got.Equal(tc.want) {
+			t.Errorf("%s: Add(%s, %s) = %s, want %s", tc.name, tc.from, tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestAddKeepsLocation(t *testing.T) {
+	from := time.Date(2026, time.March, 13, 15, 0, 0, 0, time.UTC)
+	if got := office(t).Add(from, 3*time.Hour); got.Location() != time.UTC {
+		t.Errorf("Add returned a time in %s, want UTC", got.Location())
+	}
+}
+
+func TestBetween(t *testing.T) {
+	london := mustLoad(t, "Europe/London")
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, london)
+	}
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a day", office(t), at(time.March, 11, 10, 0), at(time.March, 11, 13, 0), 3 * time.Hour},
+		{"outside working hours", office(t), at(time.March, 11, 18, 0), at(time.March, 12, 8, 0), 0},
+		{"over a weekend", office(t), at(time.March, 13, 15, 0), at(time.March, 16, 10, 0), 3 * time.Hour},
+		{"within a weekend", office(t), at(time.March, 14, 9, 0), at(time.March, 15, 17, 0), 0},
+		{"over holidays", office(t), at(time.December, 24, 16, 0), at(time.December, 29, 10, 0), 2 * time.Hour},
+		{"a week", office(t), at(time.March, 9, 0, 0), at(time.March, 16, 0, 0), 40 * time.Hour},
+		{"overnight", nights(t), at(time.January, 13, 5, 0), at(time.January, 14, 1, 0), 4 * time.Hour},
+		{"the spring night", nights(t), at(time.March, 28, 22, 0), at(time.March, 29, 6, 0), 7 * time.Hour},
+		{"the autumn night", nights(t), at(time.October, 24, 22, 0), at(time.October, 25, 6, 0), 9 * time.Hour},
+		{"reversed", office(t), at(time.March, 11, 13, 0), at(time.March, 11, 10, 0), 0},
+		{"no calendar", nil, at(time.March, 14, 12, 0), at(time.March, 14, 13, 0), time.Hour},
+	}
+	for _, tc := range tests {
+		if got := tc.calendar.Between(tc.from, tc.to); got != tc.want {
+			t.Errorf("%s: Between(%s, %s) = %s, want %s", tc.name, tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	london := mustLoad(t, "Europe/London")
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, london)
+	}
+	tests := []struct {
+		name     string
+		calendar *Calendar
+		t        time.Time
+		want     time.Time
+	}{
+		{"working", office(t), at(time.March, 11, 12, 0), at(time.March, 11, 12, 0)},
+		{"start of a period", office(t), at(time.March, 11, 9, 0), at(time.March, 11, 9, 0)},
+		{"before hours", office(t), at(time.March, 11, 7, 30), at(time.March, 11, 9, 0)},
+		{"end of a period", office(t), at(time.March, 13, 17, 0), at(time.March, 16, 9, 0)},
+		{"weekend", office(t), at(time.March, 14, 12, 0), at(time.March, 16, 9, 0)},
+		{"holiday", office(t), at(time.December, 25, 10, 0), at(time.December, 29, 9, 0)},
+		{"between nights", nights(t), at(time.January, 13, 12, 0), at(time.January, 13, 22, 0)},
+		{"at midnight", nights(t), at(time.January, 14, 0, 0), at(time.January, 14, 0, 0)},
+		{"spring change", nights(t), time.Date(2026, time.March, 29, 1, 0, 0, 0, time.UTC), time.Date(2026, time.March, 29, 1, 0, 0, 0, time.UTC)},
+		{"autumn change", nights(t), time.Date(2026, time.October, 25, 1, 30, 0, 0, time.UTC), time.Date(2026, time.October, 25, 1, 30, 0, 0, time.UTC)},
+		{"after the short night", nights(t), at(time.March, 29, 6, 0), at(time.March, 29, 22, 0)},
+		{"no calendar", nil, at(time.March, 14, 12, 0), at(time.March, 14, 12, 0)},
+	}
+	for _, tc := range tests {
+		if got := tc.calendar.Next(tc.t); !got.Equal(tc.want) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tc.name, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestIsWorking(t *testing.T) {
+	london := mustLoad(t, "Europe/London")
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(2026, time.March, 11, 9, 0, 0, 0, london), true},
+		{time.Date(2026, time.March, 11, 17, 0, 0, 0, london), false},
+		{time.Date(2026, time.March, 14, 12, 0, 0, 0, london), false},
+		{time.Date(2026, time.December, 28, 12, 0, 0, 0, london), false},
+		// 08:30 UTC is 09:30 in London in summer
+		{time.Date(2026, time.July, 1, 8, 30, 0, 0, time.UTC), true},
+		{time.Date(2026, time.January, 7, 8, 30, 0, 0, time.UTC), false},
+	}
+	c := office(t)
+	for _, tc := range tests {
+		if got := c.IsWorking(tc.t); got != tc.want {
+			t.Errorf("IsWorking(%s) = %v, want %v", tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	d, err := ParseDate("2026-12-25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != (Date{2026, time.December, 25}) || d.String() != "2026-12-25" {
+		t.Errorf("ParseDate(2026-12-25) = %v", d)
+	}
+	if _, err := ParseDate("25/12/2026"); err == nil {
+		t.Error("parsed 25/12/2026, want an error")
+	}
+}
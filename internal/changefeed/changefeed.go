@@ -0,0 +1,84 @@
+// Package changefeed delivers row changes committed by any server instance.
+// Database triggers announce inserts, updates and deletes with NOTIFY on the
+// row_changes channel; every instance LISTENs and fans the changes out to
+// in-process subscribers.
+package changefeed
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Channel is the PostgreSQL notification channel the triggers publish to
+const Channel = "row_changes"
+
+// Tables announcing their changes on the feed
+const (
+	TableTickets              = "tickets"
+	TableComments             = "comments"
+	TableAssets               = "assets"
+	TableMaintenanceSchedules = "maintenance_schedules"
+	TableOutboxEvents         = "outbox_events"
+)
+
+// Op is the kind of change made to a row
+type Op string
+
+const (
+	OpInsert Op = "INSERT"
+	OpUpdate Op = "UPDATE"
+	OpDelete Op = "DELETE"
+	// OpResync is delivered after the feed reconnects. Changes made while it
+	// was disconnected are lost, so subscribers should drop cached state.
+	OpResync Op = "RESYNC"
+)
+
+// Change identifies a changed row. It carries IDs only; consumers refetch
+// the current state of the records they need.
+type Change struct {
+	Table string
+	Op    Op
+	ID    uuid.UUID
+	// Refs holds the row's reference columns named by its trigger
+	Refs map[string]*uuid.UUID
+	// Changed lists the reference columns whose value an UPDATE changed
+	Changed []string
+	// Remote is true when the change was written by another instance
+	Remote bool
+}
+
+// Ref returns the value of a reference column, or nil when it is not set
+func (c Change) Ref(column string) *uuid.UUID {
+	return c.Refs[column]
+}
+
+// RefChanged reports whether an UPDATE changed a reference column
+func (c Change) RefChanged(column string) bool {
+	for _, changed := range c.Changed {
+		if changed == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscriber delivers changes to in-process consumers
+type Subscriber interface {
+	Subscribe(ctx context.Context, match func(Change) bool) <-chan Change
+}
+
+// ForTables matches changes to the given tables, plus resyncs
+func ForTables(tables ...string) func(Change) bool {
+	return func(c Change) bool {
+		if c.Op == OpResync {
+			return true
+		}
+		for _, table := range tables {
+			if c.Table == table {
+				return true
+			}
+		}
+		return false
+	}
+}
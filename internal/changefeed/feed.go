@@ -0,0 +1,175 @@
+package changefeed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// subscriberBuffer is the number of changes a subscriber may fall behind
+	// before further changes to it are dropped
+	subscriberBuffer = 256
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Feed listens for row changes on a dedicated connection and fans them out
+// to subscribers
+type Feed struct {
+	dsn    string
+	origin string
+	logger *log.Logger
+
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch    chan Change
+	match func(Change) bool
+}
+
+// payload is the JSON document sent by the notify_row_change trigger
+type payload struct {
+	Table   string                `json:"table"`
+	Op      Op                    `json:"op"`
+	ID      uuid.UUID             `json:"id"`
+	Refs    map[string]*uuid.UUID `json:"refs"`
+	Changed []string              `json:"changed"`
+	Origin  string                `json:"origin"`
+}
+
+// New creates a Feed connecting with dsn. origin is the application_name this
+// instance's database connections use; changes written under any other name
+// are marked Remote.
+func New(dsn, origin string, logger *log.Logger) *Feed {
+	return &Feed{
+		dsn:         dsn,
+		origin:      origin,
+		logger:      logger,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Run listens for changes until ctx is done, reconnecting with exponential
+// backoff whenever the connection is lost
+func (f *Feed) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	connected := false
+	for {
+		err := f.listen(ctx, func() {
+			// Anything written while disconnected was missed
+			if connected {
+				f.deliver(Change{Op: OpResync})
+			}
+			connected = true
+			delay = minReconnectDelay
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		f.logger.Printf("Change feed: %v, reconnecting in %s", err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// listen runs a single LISTEN session, calling ready once it is established
+func (f *Feed) listen(ctx context.Context, ready func()) error {
+	conn, err := pgx.Connect(ctx, f.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	ready()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("connection lost: %w", err)
+		}
+		change, err := f.decode(notification.Payload)
+		if err != nil {
+			f.logger.Printf("Change feed: skipping notification: %v", err)
+			continue
+		}
+		f.deliver(change)
+	}
+}
+
+// decode parses a trigger payload into a Change
+func (f *Feed) decode(raw string) (Change, error) {
+	var p payload
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return Change{}, fmt.Errorf("invalid payload: %w", err)
+	}
+	if p.Table == "" || p.ID == uuid.Nil {
+		return Change{}, fmt.Errorf("payload without table or id: %s", raw)
+	}
+	return Change{
+		Table:   p.Table,
+		Op:      p.Op,
+		ID:      p.ID,
+		Refs:    p.Refs,
+		Changed: p.Changed,
+		Remote:  p.Origin != f.origin,
+	}, nil
+}
+
+// deliver hands a change to every matching subscriber without blocking. A
+// subscriber that is not keeping up misses the changes that do not fit in
+// its buffer.
+func (f *Feed) deliver(change Change) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for sub := range f.subscribers {
+		if sub.match != nil && !sub.match(change) {
+			continue
+		}
+		select {
+		case sub.ch <- change:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the changes accepted by match (all
+// changes when match is nil). The channel is closed once ctx is done.
+func (f *Feed) Subscribe(ctx context.Context, match func(Change) bool) <-chan Change {
+	sub := &subscriber{
+		ch:    make(chan Change, subscriberBuffer),
+		match: match,
+	}
+	f.mu.Lock()
+	f.subscribers[sub] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		delete(f.subscribers, sub)
+		close(sub.ch)
+		f.mu.Unlock()
+	}()
+	return sub.ch
+}
@@ -0,0 +1,46 @@
+package changefeed
+
+import (
+	"context"
+
+	"github.com/rixtrayker/ticketing-system/internal/events"
+)
+
+// RelayTicketEvents republishes ticket and comment changes written by other
+// instances as events, so subscriptions served here see every write. Local
+// changes are skipped because the services publish those themselves. It runs
+// until ctx is done.
+func RelayTicketEvents(ctx context.Context, feed Subscriber, publisher events.Publisher) {
+	for change := range feed.Subscribe(ctx, remoteTicketChange) {
+		publisher.Publish(ticketEvents(change)...)
+	}
+}
+
+func remoteTicketChange(c Change) bool {
+	return c.Remote && (c.Table == TableTickets || c.Table == TableComments)
+}
+
+// ticketEvents translates a row change into the events the services publish
+// for the same write
+func ticketEvents(c Change) []events.Event {
+	switch {
+	case c.Table == TableTickets && c.Op == OpInsert:
+		published := []events.Event{{Type: events.TicketCreated, TicketID: &c.ID}}
+		if assignee := c.Ref("assigned_to_id"); assignee != nil {
+			published = append(published, events.Event{Type: events.TicketAssigned, TicketID: &c.ID, AssigneeID: assignee})
+		}
+		return published
+	case c.Table == TableTickets && c.Op == OpUpdate:
+		published := []events.Event{{Type: events.TicketUpdated, TicketID: &c.ID}}
+		if assignee := c.Ref("assigned_to_id"); assignee != nil && c.RefChanged("assigned_to_id") {
+			published = append(published, events.Event{Type: events.TicketAssigned, TicketID: &c.ID, AssigneeID: assignee})
+		}
+		return published
+	case c.Table == TableComments && c.Op == OpInsert:
+		if ticketID := c.Ref("ticket_id"); ticketID != nil {
+			commentID := c.ID
+			return []events.Event{{Type: events.CommentAdded, TicketID: ticketID, CommentID: &commentID}}
+		}
+	}
+	return nil
+}
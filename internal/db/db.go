@@ -14,6 +14,9 @@ import (
 
 var DB *gorm.DB
 
+// maxApplicationName is the longest application_name PostgreSQL keeps
+const maxApplicationName = 63
+
 // Config holds the database configuration
 type Config struct {
 	Host     string
@@ -22,24 +25,47 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// ApplicationName identifies this server instance's connections. The
+	// change feed uses it to tell local writes from those of other instances.
+	ApplicationName string
 }
 
 // NewConfig creates a new database configuration from environment variables
 func NewConfig() *Config {
 	return &Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "postgres"),
-		DBName:   getEnv("DB_NAME", "ticketing_system"),
-		SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+		Host:            getEnv("DB_HOST", "localhost"),
+		Port:            getEnv("DB_PORT", "5432"),
+		User:            getEnv("DB_USER", "postgres"),
+		Password:        getEnv("DB_PASSWORD", "postgres"),
+		DBName:          getEnv("DB_NAME", "ticketing_system"),
+		SSLMode:         getEnv("DB_SSL_MODE", "disable"),
+		ApplicationName: getEnv("DB_APPLICATION_NAME", instanceName()),
+	}
+}
+
+// DSN returns the connection string for the configuration
+func (c *Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s application_name=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode, c.ApplicationName)
+}
+
+// instanceName returns an application name unique to this process. PostgreSQL
+// truncates application names to 63 bytes, so longer names are cut here too.
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	name := fmt.Sprintf("ticketing-system-%s-%d", host, os.Getpid())
+	if len(name) > maxApplicationName {
+		name = name[:maxApplicationName]
 	}
+	return name
 }
 
 // Connect establishes a connection to the database
 func Connect(config *Config) error {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+	dsn := config.DSN()
 
 	// Configure GORM logger
 	newLogger := logger.New(
@@ -78,6 +104,7 @@ func Connect(config *Config) error {
 func AutoMigrate() error {
 	return DB.AutoMigrate(
 		&models.User{},
+		&models.Location{},
 		&models.Asset{},
 		&models.Ticket{},
 		&models.MaintenanceSchedule{},
@@ -85,6 +112,20 @@ func AutoMigrate() error {
 		&models.Part{},
 		&models.PartUsage{},
 		&models.Comment{},
+		&models.CommentRevision{},
+		&models.TicketEvent{},
+		&models.StockMovement{},
+		&models.OutboxEvent{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.Notification{},
+		&models.InboundEmail{},
+		&models.SLAPolicy{},
+		&models.BusinessCalendar{},
+		&models.EscalationRule{},
+		&models.TicketEscalation{},
+		&models.AssetStatusChange{},
+		&models.AssetTypeSchema{},
 	)
 }
 
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is the number of events a subscriber may fall behind
+// before further events to it are dropped
+const subscriberBuffer = 64
+
+// Bus is an in-process Publisher that fans events out to subscribers
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch    chan Event
+	match func(Event) bool
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish delivers events to every matching subscriber without blocking. A
+// subscriber that is not keeping up misses the events that do not fit in its
+// buffer.
+func (b *Bus) Publish(events ...Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, event := range events {
+		for sub := range b.subscribers {
+			if sub.match != nil && !sub.match(event) {
+				continue
+			}
+			select {
+			case sub.ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events accepted by match (all
+// events when match is nil). The channel is closed once ctx is done.
+func (b *Bus) Subscribe(ctx context.Context, match func(Event) bool) <-chan Event {
+	sub := &subscriber{
+		ch:    make(chan Event, subscriberBuffer),
+		match: match,
+	}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, sub)
+		close(sub.ch)
+		b.mu.Unlock()
+	}()
+	return sub.ch
+}
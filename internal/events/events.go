@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies what happened
+type Type string
+
+const (
+	TicketCreated       Type = "ticket.created"
+	TicketUpdated       Type = "ticket.updated"
+	TicketAssigned      Type = "ticket.assigned"
+	TicketStatusChanged Type = "ticket.status_changed"
+	CommentAdded        Type = "comment.added"
+	AssetStatusChanged  Type = "asset.status_changed"
+	PartLowStock        Type = "part.low_stock"
+	MaintenanceOverdue  Type = "maintenance.overdue"
+	TicketEscalated     Type = "ticket.escalated"
+)
+
+// Public lists the event types external systems may subscribe to
+var Public = []Type{TicketCreated, TicketStatusChanged, AssetStatusChanged, PartLowStock}
+
+// IsPublic reports whether external systems may subscribe to t
+func (t Type) IsPublic() bool {
+	for _, public := range Public {
+		if t == public {
+			return true
+		}
+	}
+	return false
+}
+
+// Event describes a committed change. It carries IDs only, plus the old and
+// new value of a status change; consumers load the current state of the
+// records they need.
+type Event struct {
+	Type           Type       `json:"type"`
+	TicketID       *uuid.UUID `json:"ticketId,omitempty"`
+	CommentID      *uuid.UUID `json:"commentId,omitempty"`
+	AssigneeID     *uuid.UUID `json:"assigneeId,omitempty"`
+	AssetID        *uuid.UUID `json:"assetId,omitempty"`
+	PartID         *uuid.UUID `json:"partId,omitempty"`
+	ScheduleID     *uuid.UUID `json:"scheduleId,omitempty"`
+	RuleID         *uuid.UUID `json:"ruleId,omitempty"`
+	PreviousStatus string     `json:"previousStatus,omitempty"`
+	Status         string     `json:"status,omitempty"`
+}
+
+// Publisher receives events after the writes they describe have committed
+type Publisher interface {
+	Publish(events ...Event)
+}
+
+// Subscriber delivers published events to in-process consumers
+type Subscriber interface {
+	Subscribe(ctx context.Context, match func(Event) bool) <-chan Event
+}
+
+// Discard is a Publisher that drops every event
+var Discard Publisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(...Event) {}
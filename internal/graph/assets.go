@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/graph/model"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// toAssetStatusCause converts the GraphQL status cause into the service one
+func toAssetStatusCause(ctx context.Context, input *model.AssetStatusCauseInput) (*service.AssetStatusCause, error) {
+	if input == nil {
+		return nil, nil
+	}
+	ticketID, err := parseOptionalID(ctx, input.Ticket)
+	if err != nil {
+		return nil, err
+	}
+	recordID, err := parseOptionalID(ctx, input.MaintenanceRecord)
+	if err != nil {
+		return nil, err
+	}
+	cause := &service.AssetStatusCause{TicketID: ticketID, MaintenanceRecordID: recordID}
+	if input.Reason != nil {
+		cause.Reason = *input.Reason
+	}
+	return cause, nil
+}
+
+// wholeMinutes rounds a duration to the nearest minute
+func wholeMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// publicRootFields can be resolved without an authenticated user
+var publicRootFields = map[string]bool{
+	"login":        true,
+	"refreshToken": true,
+	"__schema":     true,
+	"__type":       true,
+	"__typename":   true,
+}
+
+// RequireAuthentication is a root field middleware that rejects every
+// query and mutation except login/refreshToken when no user is in context
+func RequireAuthentication(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	field := graphql.GetRootFieldContext(ctx)
+	if field == nil || publicRootFields[field.Field.Name] {
+		return next(ctx)
+	}
+	if _, ok := auth.UserFromContext(ctx); !ok {
+		graphql.AddError(ctx, unauthenticatedError(ctx))
+		return graphql.Null
+	}
+	return next(ctx)
+}
+
+// currentUser returns the authenticated caller
+func currentUser(ctx context.Context) (*models.User, error) {
+	user, ok := auth.UserFromContext(ctx)
+	if !ok {
+		return nil, unauthenticatedError(ctx)
+	}
+	return user, nil
+}
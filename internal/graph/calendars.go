@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"github.com/rixtrayker/ticketing-system/internal/graph/model"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// values dereferences the elements of a GraphQL list, keeping a nil list nil
+// so update inputs can tell an omitted list from an empty one
+func values[T any](list []*T) []T {
+	if list == nil {
+		return nil
+	}
+	converted := make([]T, len(list))
+	for i, v := range list {
+		converted[i] = *v
+	}
+	return converted
+}
+
+// pointers converts a stored list into the list of pointers GraphQL returns
+func pointers[T any](list []T) []*T {
+	converted := make([]*T, len(list))
+	for i := range list {
+		converted[i] = &list[i]
+	}
+	return converted
+}
+
+// toCreateBusinessCalendarInput converts the GraphQL input into the service input
+func toCreateBusinessCalendarInput(input model.CreateBusinessCalendarInput) *service.CreateBusinessCalendarInput {
+	converted := &service.CreateBusinessCalendarInput{
+		Name:         input.Name,
+		TimeZone:     input.TimeZone,
+		WorkingHours: values(input.WorkingHours),
+		Holidays:     values(input.Holidays),
+		Locations:    input.Locations,
+	}
+	if input.IsDefault != nil {
+		converted.IsDefault = *input.IsDefault
+	}
+	return converted
+}
+
+// toUpdateBusinessCalendarInput converts the GraphQL input into the service input
+func toUpdateBusinessCalendarInput(input model.UpdateBusinessCalendarInput) *service.UpdateBusinessCalendarInput {
+	return &service.UpdateBusinessCalendarInput{
+		Name:         input.Name,
+		TimeZone:     input.TimeZone,
+		WorkingHours: values(input.WorkingHours),
+		Holidays:     values(input.Holidays),
+		Locations:    input.Locations,
+		IsDefault:    input.IsDefault,
+	}
+}
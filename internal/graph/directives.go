@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// HasRole implements the @hasRole directive, only resolving the field when the
+// authenticated user has one of the listed roles
+func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, roles []models.UserRole) (interface{}, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !service.HasRole(user, roles...) {
+		return nil, forbiddenError(ctx)
+	}
+	return next(ctx)
+}
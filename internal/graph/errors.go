@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+)
+
+// Error codes returned in the "code" extension of GraphQL errors
+const (
+	codeUnauthenticated = "UNAUTHENTICATED"
+	codeUnauthorized    = "UNAUTHORIZED"
+	codeNotFound        = "NOT_FOUND"
+	codeValidationError = "VALIDATION_ERROR"
+	codeInvalidStatus   = "INVALID_STATUS_TRANSITION"
+)
+
+// newError builds a GraphQL error carrying the given code extension
+func newError(ctx context.Context, code, message string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Path:    graphql.GetPath(ctx),
+		Message: message,
+		Extensions: map[string]interface{}{
+			"code": code,
+		},
+	}
+}
+
+// unauthenticatedError reports a request made without valid credentials
+func unauthenticatedError(ctx context.Context) *gqlerror.Error {
+	return newError(ctx, codeUnauthenticated, "authentication required")
+}
+
+// forbiddenError reports a caller lacking permission for an operation
+func forbiddenError(ctx context.Context) *gqlerror.Error {
+	return newError(ctx, codeUnauthorized, service.ErrForbidden.Error())
+}
+
+// validationError reports invalid input
+func validationError(ctx context.Context, message string) *gqlerror.Error {
+	return newError(ctx, codeValidationError, message)
+}
+
+// invalidIDError reports an ID argument that is not a valid UUID
+func invalidIDError(ctx context.Context, id string) *gqlerror.Error {
+	return validationError(ctx, fmt.Sprintf("invalid id %q", id))
+}
+
+// parseID converts a GraphQL ID into a UUID, returning a validation error when malformed
+func parseID(ctx context.Context, id string) (uuid.UUID, error) {
+	parsed, err := stringToUUID(id)
+	if err != nil {
+		return uuid.Nil, invalidIDError(ctx, id)
+	}
+	return parsed, nil
+}
+
+// parseOptionalID converts an optional GraphQL ID into a UUID pointer
+func parseOptionalID(ctx context.Context, id *string) (*uuid.UUID, error) {
+	if id == nil {
+		return nil, nil
+	}
+	parsed, err := parseID(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// toGraphQLError maps repository and service errors onto GraphQL errors.
+// resource and id describe the record the resolver was looking up.
+func toGraphQLError(ctx context.Context, err error, resource, id string) error {
+	var notFound *service.NotFoundError
+	var invalid *service.ValidationError
+	var transition *service.InvalidTransitionError
+	switch {
+	case errors.As(err, &transition):
+		gqlErr := newError(ctx, codeInvalidStatus, transition.Error())
+		gqlErr.Extensions["from"] = transition.From
+		gqlErr.Extensions["to"] = transition.To
+		return gqlErr
+	case errors.As(err, &notFound):
+		return newError(ctx, codeNotFound, notFound.Error())
+	case errors.As(err, &invalid):
+		return validationError(ctx, invalid.Error())
+	case errors.Is(err, repository.ErrInvalidCursor), errors.Is(err, repository.ErrUnsupportedSort):
+		return validationError(ctx, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return forbiddenError(ctx)
+	case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidToken):
+		return newError(ctx, codeUnauthenticated, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return newError(ctx, codeNotFound, fmt.Sprintf("%s %s not found", resource, id))
+	}
+	return err
+}
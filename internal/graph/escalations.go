@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/rixtrayker/ticketing-system/internal/graph/model"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// toCreateEscalationRuleInput converts the GraphQL input into the service input
+func toCreateEscalationRuleInput(ctx context.Context, input model.CreateEscalationRuleInput) (*service.CreateEscalationRuleInput, error) {
+	escalateToID, err := parseOptionalID(ctx, input.EscalateTo)
+	if err != nil {
+		return nil, err
+	}
+	converted := &service.CreateEscalationRuleInput{
+		Name:         input.Name,
+		Condition:    input.Condition,
+		Priority:     input.Priority,
+		Active:       input.Active,
+		EscalateToID: escalateToID,
+	}
+	if input.AfterMinutes != nil {
+		converted.AfterMinutes = *input.AfterMinutes
+	}
+	if input.BumpPriority != nil {
+		converted.BumpPriority = *input.BumpPriority
+	}
+	if input.Comment != nil {
+		converted.Comment = *input.Comment
+	}
+	if input.Notify != nil {
+		converted.Notify = *input.Notify
+	}
+	return converted, nil
+}
+
+// toUpdateEscalationRuleInput converts the GraphQL input into the service input
+func toUpdateEscalationRuleInput(ctx context.Context, input model.UpdateEscalationRuleInput) (*service.UpdateEscalationRuleInput, error) {
+	escalateToID, err := parseOptionalID(ctx, input.EscalateTo)
+	if err != nil {
+		return nil, err
+	}
+	return &service.UpdateEscalationRuleInput{
+		Name:         input.Name,
+		AfterMinutes: input.AfterMinutes,
+		Active:       input.Active,
+		BumpPriority: input.BumpPriority,
+		EscalateToID: escalateToID,
+		Comment:      input.Comment,
+		Notify:       input.Notify,
+	}, nil
+}
@@ -0,0 +1,365 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+type AddCommentInput struct {
+	Ticket   string `json:"ticket"`
+	Content  string `json:"content"`
+	Internal *bool  `json:"internal,omitempty"`
+}
+
+type AssetConnection struct {
+	Edges      []*AssetEdge     `json:"edges"`
+	PageInfo   *models.PageInfo `json:"pageInfo"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type AssetEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *models.Asset `json:"node"`
+}
+
+type AssetStatusCauseInput struct {
+	Reason            *string `json:"reason,omitempty"`
+	Ticket            *string `json:"ticket,omitempty"`
+	MaintenanceRecord *string `json:"maintenanceRecord,omitempty"`
+}
+
+type AuthPayload struct {
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refreshToken"`
+	ExpiresAt    time.Time    `json:"expiresAt"`
+	User         *models.User `json:"user"`
+}
+
+type CreateAssetInput struct {
+	Name         string           `json:"name"`
+	Type         models.AssetType `json:"type"`
+	Location     *string          `json:"location,omitempty"`
+	Place        *string          `json:"place,omitempty"`
+	Parent       *string          `json:"parent,omitempty"`
+	PurchaseDate time.Time        `json:"purchaseDate"`
+	Metadata     models.JSONB     `json:"metadata,omitempty"`
+}
+
+type CreateBusinessCalendarInput struct {
+	Name         string                  `json:"name"`
+	TimeZone     string                  `json:"timeZone"`
+	WorkingHours []*models.WorkingPeriod `json:"workingHours"`
+	Holidays     []*models.Holiday       `json:"holidays,omitempty"`
+	Locations    []string                `json:"locations,omitempty"`
+	IsDefault    *bool                   `json:"isDefault,omitempty"`
+}
+
+type CreateEscalationRuleInput struct {
+	Name         string                     `json:"name"`
+	Condition    models.EscalationCondition `json:"condition"`
+	Priority     *models.TicketPriority     `json:"priority,omitempty"`
+	AfterMinutes *int                       `json:"afterMinutes,omitempty"`
+	Active       *bool                      `json:"active,omitempty"`
+	BumpPriority *bool                      `json:"bumpPriority,omitempty"`
+	EscalateTo   *string                    `json:"escalateTo,omitempty"`
+	Comment      *string                    `json:"comment,omitempty"`
+	Notify       *bool                      `json:"notify,omitempty"`
+}
+
+type CreateLocationInput struct {
+	Name   string              `json:"name"`
+	Kind   models.LocationKind `json:"kind"`
+	Parent *string             `json:"parent,omitempty"`
+}
+
+type CreateMaintenanceScheduleInput struct {
+	Asset      string                      `json:"asset"`
+	Frequency  models.MaintenanceFrequency `json:"frequency"`
+	AssignedTo string                      `json:"assignedTo"`
+	Notes      *string                     `json:"notes,omitempty"`
+}
+
+type CreatePartInput struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Quantity        int    `json:"quantity"`
+	MinimumQuantity int    `json:"minimumQuantity"`
+	Location        string `json:"location"`
+}
+
+type CreateSLAPolicyInput struct {
+	Name              string                `json:"name"`
+	Priority          models.TicketPriority `json:"priority"`
+	AssetType         *models.AssetType     `json:"assetType,omitempty"`
+	ResponseMinutes   int                   `json:"responseMinutes"`
+	ResolutionMinutes int                   `json:"resolutionMinutes"`
+}
+
+type CreateTicketInput struct {
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	Priority    models.TicketPriority `json:"priority"`
+	AssignedTo  *string               `json:"assignedTo,omitempty"`
+	Asset       *string               `json:"asset,omitempty"`
+}
+
+type CreateUserInput struct {
+	Email    string          `json:"email"`
+	Name     string          `json:"name"`
+	Role     models.UserRole `json:"role"`
+	Password string          `json:"password"`
+}
+
+type CreateWebhookInput struct {
+	URL         string             `json:"url"`
+	Secret      string             `json:"secret"`
+	Description *string            `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"eventTypes"`
+	Active      *bool              `json:"active,omitempty"`
+}
+
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type MaintenanceScheduleConnection struct {
+	Edges      []*MaintenanceScheduleEdge `json:"edges"`
+	PageInfo   *models.PageInfo           `json:"pageInfo"`
+	TotalCount int                        `json:"totalCount"`
+}
+
+type MaintenanceScheduleEdge struct {
+	Cursor string                      `json:"cursor"`
+	Node   *models.MaintenanceSchedule `json:"node"`
+}
+
+type Mutation struct {
+}
+
+type NotificationPreferencesInput struct {
+	OnAssignment         *bool `json:"onAssignment,omitempty"`
+	OnResolution         *bool `json:"onResolution,omitempty"`
+	OnMaintenanceOverdue *bool `json:"onMaintenanceOverdue,omitempty"`
+	OnEscalation         *bool `json:"onEscalation,omitempty"`
+}
+
+type PartConnection struct {
+	Edges      []*PartEdge      `json:"edges"`
+	PageInfo   *models.PageInfo `json:"pageInfo"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type PartEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *models.Part `json:"node"`
+}
+
+type PartUsageInput struct {
+	Part     string `json:"part"`
+	Quantity int    `json:"quantity"`
+}
+
+type Query struct {
+}
+
+type RecordMaintenanceInput struct {
+	Asset       string                 `json:"asset"`
+	Type        models.MaintenanceType `json:"type"`
+	PerformedAt *time.Time             `json:"performedAt,omitempty"`
+	Notes       *string                `json:"notes,omitempty"`
+	Parts       []*PartUsageInput      `json:"parts,omitempty"`
+}
+
+type Subscription struct {
+}
+
+type TicketConnection struct {
+	Edges      []*TicketEdge    `json:"edges"`
+	PageInfo   *models.PageInfo `json:"pageInfo"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type TicketEdge struct {
+	Cursor string         `json:"cursor"`
+	Node   *models.Ticket `json:"node"`
+}
+
+type TicketSLA struct {
+	Policy             *models.SLAPolicy `json:"policy"`
+	ResponseDueAt      *time.Time        `json:"responseDueAt,omitempty"`
+	ResolutionDueAt    *time.Time        `json:"resolutionDueAt,omitempty"`
+	RespondedAt        *time.Time        `json:"respondedAt,omitempty"`
+	Paused             bool              `json:"paused"`
+	ResponseBreached   bool              `json:"responseBreached"`
+	ResolutionBreached bool              `json:"resolutionBreached"`
+	Breached           bool              `json:"breached"`
+	AtRisk             bool              `json:"atRisk"`
+}
+
+type UpdateAssetInput struct {
+	Name        *string                `json:"name,omitempty"`
+	Type        *models.AssetType      `json:"type,omitempty"`
+	Status      *models.AssetStatus    `json:"status,omitempty"`
+	Location    *string                `json:"location,omitempty"`
+	Place       *string                `json:"place,omitempty"`
+	Parent      *string                `json:"parent,omitempty"`
+	Metadata    models.JSONB           `json:"metadata,omitempty"`
+	StatusCause *AssetStatusCauseInput `json:"statusCause,omitempty"`
+}
+
+type UpdateBusinessCalendarInput struct {
+	Name         *string                 `json:"name,omitempty"`
+	TimeZone     *string                 `json:"timeZone,omitempty"`
+	WorkingHours []*models.WorkingPeriod `json:"workingHours,omitempty"`
+	Holidays     []*models.Holiday       `json:"holidays,omitempty"`
+	Locations    []string                `json:"locations,omitempty"`
+	IsDefault    *bool                   `json:"isDefault,omitempty"`
+}
+
+type UpdateEscalationRuleInput struct {
+	Name         *string `json:"name,omitempty"`
+	AfterMinutes *int    `json:"afterMinutes,omitempty"`
+	Active       *bool   `json:"active,omitempty"`
+	BumpPriority *bool   `json:"bumpPriority,omitempty"`
+	EscalateTo   *string `json:"escalateTo,omitempty"`
+	Comment      *string `json:"comment,omitempty"`
+	Notify       *bool   `json:"notify,omitempty"`
+}
+
+type UpdateLocationInput struct {
+	Name   *string `json:"name,omitempty"`
+	Parent *string `json:"parent,omitempty"`
+}
+
+type UpdateMaintenanceScheduleInput struct {
+	Frequency  *models.MaintenanceFrequency `json:"frequency,omitempty"`
+	AssignedTo *string                      `json:"assignedTo,omitempty"`
+	Status     *models.MaintenanceStatus    `json:"status,omitempty"`
+	Notes      *string                      `json:"notes,omitempty"`
+}
+
+type UpdatePartInput struct {
+	Name            *string `json:"name,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	MinimumQuantity *int    `json:"minimumQuantity,omitempty"`
+	Location        *string `json:"location,omitempty"`
+}
+
+type UpdateSLAPolicyInput struct {
+	Name              *string `json:"name,omitempty"`
+	ResponseMinutes   *int    `json:"responseMinutes,omitempty"`
+	ResolutionMinutes *int    `json:"resolutionMinutes,omitempty"`
+}
+
+type UpdateTicketInput struct {
+	Title       *string                `json:"title,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Status      *models.TicketStatus   `json:"status,omitempty"`
+	Priority    *models.TicketPriority `json:"priority,omitempty"`
+	AssignedTo  *string                `json:"assignedTo,omitempty"`
+	Asset       *string                `json:"asset,omitempty"`
+}
+
+type UpdateUserInput struct {
+	Email    *string          `json:"email,omitempty"`
+	Name     *string          `json:"name,omitempty"`
+	Role     *models.UserRole `json:"role,omitempty"`
+	Password *string          `json:"password,omitempty"`
+}
+
+type UpdateWebhookInput struct {
+	URL         *string            `json:"url,omitempty"`
+	Secret      *string            `json:"secret,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"eventTypes,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge      `json:"edges"`
+	PageInfo   *models.PageInfo `json:"pageInfo"`
+	TotalCount int              `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *models.User `json:"node"`
+}
+
+type WebhookDeliveryConnection struct {
+	Edges      []*WebhookDeliveryEdge `json:"edges"`
+	PageInfo   *models.PageInfo       `json:"pageInfo"`
+	TotalCount int                    `json:"totalCount"`
+}
+
+type WebhookDeliveryEdge struct {
+	Cursor string                  `json:"cursor"`
+	Node   *models.WebhookDelivery `json:"node"`
+}
+
+type WebhookEventType string
+
+const (
+	WebhookEventTypeTicketCreated       WebhookEventType = "TICKET_CREATED"
+	WebhookEventTypeTicketStatusChanged WebhookEventType = "TICKET_STATUS_CHANGED"
+	WebhookEventTypeAssetStatusChanged  WebhookEventType = "ASSET_STATUS_CHANGED"
+	WebhookEventTypePartLowStock        WebhookEventType = "PART_LOW_STOCK"
+)
+
+var AllWebhookEventType = []WebhookEventType{
+	WebhookEventTypeTicketCreated,
+	WebhookEventTypeTicketStatusChanged,
+	WebhookEventTypeAssetStatusChanged,
+	WebhookEventTypePartLowStock,
+}
+
+func (e WebhookEventType) IsValid() bool {
+	switch e {
+	case WebhookEventTypeTicketCreated, WebhookEventTypeTicketStatusChanged, WebhookEventTypeAssetStatusChanged, WebhookEventTypePartLowStock:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEventType) String() string {
+	return string(e)
+}
+
+func (e *WebhookEventType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEventType", str)
+	}
+	return nil
+}
+
+func (e WebhookEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEventType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEventType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// pageArgs collects the pagination arguments of a list field
+func pageArgs(first *int, after *string, orderBy *models.OrderBy) *models.PageArgs {
+	return &models.PageArgs{First: first, After: after, OrderBy: orderBy}
+}
+
+// toEdges builds the edges of a connection from a page of rows
+func toEdges[T, E any](page *models.Page[T], edge func(cursor string, node T) E) []E {
+	edges := make([]E, len(page.Items))
+	for i, item := range page.Items {
+		edges[i] = edge(page.Cursors[i], item)
+	}
+	return edges
+}
@@ -1,7 +1,13 @@
 package graph
 
 import (
+	"context"
+
 	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/graph/model"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
 	"github.com/rixtrayker/ticketing-system/internal/service"
 	"gorm.io/gorm"
 )
@@ -11,8 +17,24 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	DB            *gorm.DB
-	TicketService service.TicketService
+	DB                      *gorm.DB
+	AuthService             service.AuthService
+	TicketService           service.TicketService
+	UserService             service.UserService
+	CommentService          service.CommentService
+	SearchService           service.SearchService
+	MaintenanceService      service.MaintenanceService
+	InventoryService        service.InventoryService
+	AssetService            service.AssetService
+	WebhookService          service.WebhookService
+	SLAService              service.SLAService
+	CalendarService         service.CalendarService
+	LocationService         service.LocationService
+	AssetTypeSchemaService  service.AssetTypeSchemaService
+	EscalationService       service.EscalationService
+	Events                  events.Subscriber
+	AssetRepo               repository.AssetRepository
+	MaintenanceScheduleRepo repository.MaintenanceScheduleRepository
 }
 
 // Helper function to convert string ID to UUID
@@ -23,4 +45,31 @@ func stringToUUID(id string) (uuid.UUID, error) {
 // Helper function to convert UUID to string
 func uuidToString(id uuid.UUID) string {
 	return id.String()
-} 
\ No newline at end of file
+}
+
+// Helper function to convert an auth result into the GraphQL payload
+func toAuthPayload(result *service.AuthResult) *model.AuthPayload {
+	return &model.AuthPayload{
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+		ExpiresAt:    result.Tokens.ExpiresAt,
+		User:         result.User,
+	}
+}
+
+// Helper function to move a ticket through the status workflow on behalf of the caller
+func (r *Resolver) transitionTicket(ctx context.Context, id string, status models.TicketStatus) (*models.Ticket, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ticketID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := r.TicketService.TransitionTicket(user, ticketID, status)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", id)
+	}
+	return ticket, nil
+}
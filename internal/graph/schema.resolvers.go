@@ -6,189 +6,1721 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"strings"
+	"time"
 
 	"github.com/rixtrayker/ticketing-system/internal/graph/generated"
 	"github.com/rixtrayker/ticketing-system/internal/graph/model"
 	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/service"
 )
 
 // ID is the resolver for the id field.
 func (r *assetResolver) ID(ctx context.Context, obj *models.Asset) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// MaintenanceHistory is the resolver for the maintenanceHistory field.
+func (r *assetResolver) MaintenanceHistory(ctx context.Context, obj *models.Asset) ([]*models.MaintenanceRecord, error) {
+	return r.MaintenanceService.GetAssetHistory(obj.ID)
+}
+
+// Calendar is the resolver for the calendar field.
+func (r *assetResolver) Calendar(ctx context.Context, obj *models.Asset) (*models.BusinessCalendar, error) {
+	calendar, err := r.CalendarService.GetCalendarForLocation(obj.Location)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "business calendar", "")
+	}
+	return calendar, nil
+}
+
+// Place is the resolver for the place field.
+func (r *assetResolver) Place(ctx context.Context, obj *models.Asset) (*models.Location, error) {
+	if obj.LocationID == nil {
+		return nil, nil
+	}
+	location, err := r.LocationService.GetLocation(*obj.LocationID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "location", obj.LocationID.String())
+	}
+	return location, nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *assetResolver) Parent(ctx context.Context, obj *models.Asset) (*models.Asset, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	parent, err := r.AssetService.GetAsset(*obj.ParentID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", obj.ParentID.String())
+	}
+	return parent, nil
+}
+
+// Children is the resolver for the children field.
+func (r *assetResolver) Children(ctx context.Context, obj *models.Asset) ([]*models.Asset, error) {
+	children, err := r.AssetService.GetAssetChildren(obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "assets", "")
+	}
+	return children, nil
+}
+
+// Descendants is the resolver for the descendants field.
+func (r *assetResolver) Descendants(ctx context.Context, obj *models.Asset) ([]*models.Asset, error) {
+	descendants, err := r.AssetService.GetAssetDescendants(obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "assets", "")
+	}
+	return descendants, nil
+}
+
+// StatusHistory is the resolver for the statusHistory field.
+func (r *assetResolver) StatusHistory(ctx context.Context, obj *models.Asset) ([]*models.AssetStatusChange, error) {
+	changes, err := r.AssetService.GetAssetStatusHistory(obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset status history", uuidToString(obj.ID))
+	}
+	return changes, nil
+}
+
+// Availability is the resolver for the availability field.
+func (r *assetResolver) Availability(ctx context.Context, obj *models.Asset, from time.Time, to time.Time) (*models.AssetAvailability, error) {
+	availability, err := r.AssetService.GetAssetAvailability(obj.ID, from, to)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", uuidToString(obj.ID))
+	}
+	return availability, nil
+}
+
+// TrackedMinutes is the resolver for the trackedMinutes field.
+func (r *assetAvailabilityResolver) TrackedMinutes(ctx context.Context, obj *models.AssetAvailability) (int, error) {
+	return wholeMinutes(obj.Tracked), nil
+}
+
+// DowntimeMinutes is the resolver for the downtimeMinutes field.
+func (r *assetAvailabilityResolver) DowntimeMinutes(ctx context.Context, obj *models.AssetAvailability) (int, error) {
+	return wholeMinutes(obj.Downtime), nil
+}
+
+// ID is the resolver for the id field.
+func (r *assetStatusChangeResolver) ID(ctx context.Context, obj *models.AssetStatusChange) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// Ticket is the resolver for the ticket field.
+func (r *assetStatusChangeResolver) Ticket(ctx context.Context, obj *models.AssetStatusChange) (*models.Ticket, error) {
+	if obj.TicketID == nil {
+		return nil, nil
+	}
+	ticket, err := r.TicketService.GetTicket(*obj.TicketID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", obj.TicketID.String())
+	}
+	return ticket, nil
+}
+
+// MaintenanceRecord is the resolver for the maintenanceRecord field.
+func (r *assetStatusChangeResolver) MaintenanceRecord(ctx context.Context, obj *models.AssetStatusChange) (*models.MaintenanceRecord, error) {
+	if obj.MaintenanceRecordID == nil {
+		return nil, nil
+	}
+	record, err := r.MaintenanceService.GetRecord(*obj.MaintenanceRecordID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance record", obj.MaintenanceRecordID.String())
+	}
+	return record, nil
+}
+
+// ID is the resolver for the id field.
+func (r *businessCalendarResolver) ID(ctx context.Context, obj *models.BusinessCalendar) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// WorkingHours is the resolver for the workingHours field.
+func (r *businessCalendarResolver) WorkingHours(ctx context.Context, obj *models.BusinessCalendar) ([]*models.WorkingPeriod, error) {
+	return pointers(obj.WorkingHours), nil
+}
+
+// Holidays is the resolver for the holidays field.
+func (r *businessCalendarResolver) Holidays(ctx context.Context, obj *models.BusinessCalendar) ([]*models.Holiday, error) {
+	return pointers(obj.Holidays), nil
+}
+
+// Locations is the resolver for the locations field.
+func (r *businessCalendarResolver) Locations(ctx context.Context, obj *models.BusinessCalendar) ([]string, error) {
+	return obj.Locations, nil
 }
 
 // ID is the resolver for the id field.
 func (r *commentResolver) ID(ctx context.Context, obj *models.Comment) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// Ticket is the resolver for the ticket field.
+func (r *commentResolver) Ticket(ctx context.Context, obj *models.Comment) (*models.Ticket, error) {
+	ticket, err := r.TicketService.GetTicket(obj.TicketID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", uuidToString(obj.TicketID))
+	}
+	return ticket, nil
+}
+
+// ID is the resolver for the id field.
+func (r *commentRevisionResolver) ID(ctx context.Context, obj *models.CommentRevision) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// ID is the resolver for the id field.
+func (r *escalationRuleResolver) ID(ctx context.Context, obj *models.EscalationRule) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// ID is the resolver for the id field.
+func (r *locationResolver) ID(ctx context.Context, obj *models.Location) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *locationResolver) Parent(ctx context.Context, obj *models.Location) (*models.Location, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	parent, err := r.LocationService.GetLocation(*obj.ParentID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "location", obj.ParentID.String())
+	}
+	return parent, nil
+}
+
+// Children is the resolver for the children field.
+func (r *locationResolver) Children(ctx context.Context, obj *models.Location) ([]*models.Location, error) {
+	children, err := r.LocationService.GetLocations(&obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "locations", "")
+	}
+	return children, nil
+}
+
+// Path is the resolver for the path field.
+func (r *locationResolver) Path(ctx context.Context, obj *models.Location) (string, error) {
+	path, err := r.LocationService.GetLocationPath(obj.ID)
+	if err != nil {
+		return "", toGraphQLError(ctx, err, "location", uuidToString(obj.ID))
+	}
+	return models.LocationPath(path), nil
 }
 
 // ID is the resolver for the id field.
 func (r *maintenanceRecordResolver) ID(ctx context.Context, obj *models.MaintenanceRecord) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
 }
 
 // ID is the resolver for the id field.
 func (r *maintenanceScheduleResolver) ID(ctx context.Context, obj *models.MaintenanceSchedule) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// Login is the resolver for the login field.
+func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*model.AuthPayload, error) {
+	result, err := r.AuthService.Login(input.Email, input.Password)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", input.Email)
+	}
+	return toAuthPayload(result), nil
+}
+
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, token string) (*model.AuthPayload, error) {
+	result, err := r.AuthService.Refresh(token)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", "")
+	}
+	return toAuthPayload(result), nil
 }
 
 // CreateTicket is the resolver for the createTicket field.
 func (r *mutationResolver) CreateTicket(ctx context.Context, input model.CreateTicketInput) (*models.Ticket, error) {
-	panic(fmt.Errorf("not implemented: CreateTicket - createTicket"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignedToID, err := parseOptionalID(ctx, input.AssignedTo)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseOptionalID(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := r.TicketService.CreateTicket(user, &service.CreateTicketInput{
+		Title:        input.Title,
+		Description:  input.Description,
+		Priority:     input.Priority,
+		AssignedToID: assignedToID,
+		AssetID:      assetID,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", "")
+	}
+	return ticket, nil
 }
 
 // UpdateTicket is the resolver for the updateTicket field.
 func (r *mutationResolver) UpdateTicket(ctx context.Context, id string, input model.UpdateTicketInput) (*models.Ticket, error) {
-	panic(fmt.Errorf("not implemented: UpdateTicket - updateTicket"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ticketID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignedToID, err := parseOptionalID(ctx, input.AssignedTo)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseOptionalID(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := r.TicketService.UpdateTicket(user, ticketID, &service.UpdateTicketInput{
+		Title:        input.Title,
+		Description:  input.Description,
+		Status:       input.Status,
+		Priority:     input.Priority,
+		AssignedToID: assignedToID,
+		AssetID:      assetID,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", id)
+	}
+	return ticket, nil
+}
+
+// StartTicket is the resolver for the startTicket field.
+func (r *mutationResolver) StartTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusInProgress)
+}
+
+// ResolveTicket is the resolver for the resolveTicket field.
+func (r *mutationResolver) ResolveTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusResolved)
+}
+
+// CloseTicket is the resolver for the closeTicket field.
+func (r *mutationResolver) CloseTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusClosed)
+}
+
+// ReopenTicket is the resolver for the reopenTicket field.
+func (r *mutationResolver) ReopenTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusOpen)
+}
+
+// CancelTicket is the resolver for the cancelTicket field.
+func (r *mutationResolver) CancelTicket(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusCancelled)
+}
+
+// WaitOnRequester is the resolver for the waitOnRequester field.
+func (r *mutationResolver) WaitOnRequester(ctx context.Context, id string) (*models.Ticket, error) {
+	return r.transitionTicket(ctx, id, models.TicketStatusWaiting)
 }
 
 // DeleteTicket is the resolver for the deleteTicket field.
 func (r *mutationResolver) DeleteTicket(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteTicket - deleteTicket"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	ticketID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.TicketService.DeleteTicket(user, ticketID); err != nil {
+		return false, toGraphQLError(ctx, err, "ticket", id)
+	}
+	return true, nil
+}
+
+// CreateSLAPolicy is the resolver for the createSLAPolicy field.
+func (r *mutationResolver) CreateSLAPolicy(ctx context.Context, input model.CreateSLAPolicyInput) (*models.SLAPolicy, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := r.SLAService.CreateSLAPolicy(user, &service.CreateSLAPolicyInput{
+		Name:              input.Name,
+		Priority:          input.Priority,
+		AssetType:         input.AssetType,
+		ResponseMinutes:   input.ResponseMinutes,
+		ResolutionMinutes: input.ResolutionMinutes,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "SLA policy", "")
+	}
+	return policy, nil
+}
+
+// UpdateSLAPolicy is the resolver for the updateSLAPolicy field.
+func (r *mutationResolver) UpdateSLAPolicy(ctx context.Context, id string, input model.UpdateSLAPolicyInput) (*models.SLAPolicy, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policyID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := r.SLAService.UpdateSLAPolicy(user, policyID, &service.UpdateSLAPolicyInput{
+		Name:              input.Name,
+		ResponseMinutes:   input.ResponseMinutes,
+		ResolutionMinutes: input.ResolutionMinutes,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "SLA policy", id)
+	}
+	return policy, nil
+}
+
+// DeleteSLAPolicy is the resolver for the deleteSLAPolicy field.
+func (r *mutationResolver) DeleteSLAPolicy(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	policyID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.SLAService.DeleteSLAPolicy(user, policyID); err != nil {
+		return false, toGraphQLError(ctx, err, "SLA policy", id)
+	}
+	return true, nil
+}
+
+// CreateBusinessCalendar is the resolver for the createBusinessCalendar field.
+func (r *mutationResolver) CreateBusinessCalendar(ctx context.Context, input model.CreateBusinessCalendarInput) (*models.BusinessCalendar, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := r.CalendarService.CreateBusinessCalendar(user, toCreateBusinessCalendarInput(input))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "business calendar", "")
+	}
+	return calendar, nil
+}
+
+// UpdateBusinessCalendar is the resolver for the updateBusinessCalendar field.
+func (r *mutationResolver) UpdateBusinessCalendar(ctx context.Context, id string, input model.UpdateBusinessCalendarInput) (*models.BusinessCalendar, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	calendarID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := r.CalendarService.UpdateBusinessCalendar(user, calendarID, toUpdateBusinessCalendarInput(input))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "business calendar", id)
+	}
+	return calendar, nil
+}
+
+// DeleteBusinessCalendar is the resolver for the deleteBusinessCalendar field.
+func (r *mutationResolver) DeleteBusinessCalendar(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	calendarID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.CalendarService.DeleteBusinessCalendar(user, calendarID); err != nil {
+		return false, toGraphQLError(ctx, err, "business calendar", id)
+	}
+	return true, nil
+}
+
+// CreateLocation is the resolver for the createLocation field.
+func (r *mutationResolver) CreateLocation(ctx context.Context, input model.CreateLocationInput) (*models.Location, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalID(ctx, input.Parent)
+	if err != nil {
+		return nil, err
+	}
+	location, err := r.LocationService.CreateLocation(user, &service.CreateLocationInput{
+		Name:     input.Name,
+		Kind:     input.Kind,
+		ParentID: parentID,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "location", "")
+	}
+	return location, nil
+}
+
+// UpdateLocation is the resolver for the updateLocation field.
+func (r *mutationResolver) UpdateLocation(ctx context.Context, id string, input model.UpdateLocationInput) (*models.Location, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locationID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalID(ctx, input.Parent)
+	if err != nil {
+		return nil, err
+	}
+	location, err := r.LocationService.UpdateLocation(user, locationID, &service.UpdateLocationInput{
+		Name:     input.Name,
+		ParentID: parentID,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "location", id)
+	}
+	return location, nil
+}
+
+// DeleteLocation is the resolver for the deleteLocation field.
+func (r *mutationResolver) DeleteLocation(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	locationID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.LocationService.DeleteLocation(user, locationID); err != nil {
+		return false, toGraphQLError(ctx, err, "location", id)
+	}
+	return true, nil
+}
+
+// CreateEscalationRule is the resolver for the createEscalationRule field.
+func (r *mutationResolver) CreateEscalationRule(ctx context.Context, input model.CreateEscalationRuleInput) (*models.EscalationRule, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := toCreateEscalationRuleInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := r.EscalationService.CreateEscalationRule(user, converted)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "escalation rule", "")
+	}
+	return rule, nil
+}
+
+// UpdateEscalationRule is the resolver for the updateEscalationRule field.
+func (r *mutationResolver) UpdateEscalationRule(ctx context.Context, id string, input model.UpdateEscalationRuleInput) (*models.EscalationRule, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ruleID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := toUpdateEscalationRuleInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := r.EscalationService.UpdateEscalationRule(user, ruleID, converted)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "escalation rule", id)
+	}
+	return rule, nil
+}
+
+// DeleteEscalationRule is the resolver for the deleteEscalationRule field.
+func (r *mutationResolver) DeleteEscalationRule(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	ruleID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.EscalationService.DeleteEscalationRule(user, ruleID); err != nil {
+		return false, toGraphQLError(ctx, err, "escalation rule", id)
+	}
+	return true, nil
+}
+
+// SetAssetTypeSchema is the resolver for the setAssetTypeSchema field.
+func (r *mutationResolver) SetAssetTypeSchema(ctx context.Context, typeArg models.AssetType, schema models.JSONB) (*models.AssetTypeSchema, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assetTypeSchema, err := r.AssetTypeSchemaService.SetAssetTypeSchema(user, typeArg, schema)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset type schema", string(typeArg))
+	}
+	return assetTypeSchema, nil
+}
+
+// DeleteAssetTypeSchema is the resolver for the deleteAssetTypeSchema field.
+func (r *mutationResolver) DeleteAssetTypeSchema(ctx context.Context, typeArg models.AssetType) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := r.AssetTypeSchemaService.DeleteAssetTypeSchema(user, typeArg); err != nil {
+		return false, toGraphQLError(ctx, err, "asset type schema", string(typeArg))
+	}
+	return true, nil
 }
 
 // CreateAsset is the resolver for the createAsset field.
 func (r *mutationResolver) CreateAsset(ctx context.Context, input model.CreateAssetInput) (*models.Asset, error) {
-	panic(fmt.Errorf("not implemented: CreateAsset - createAsset"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	placeID, err := parseOptionalID(ctx, input.Place)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalID(ctx, input.Parent)
+	if err != nil {
+		return nil, err
+	}
+	converted := &service.CreateAssetInput{
+		Name:         input.Name,
+		Type:         input.Type,
+		PurchaseDate: input.PurchaseDate,
+		Metadata:     input.Metadata,
+		PlaceID:      placeID,
+		ParentID:     parentID,
+	}
+	if input.Location != nil {
+		converted.Location = *input.Location
+	}
+	asset, err := r.AssetService.CreateAsset(user, converted)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", "")
+	}
+	return asset, nil
 }
 
 // UpdateAsset is the resolver for the updateAsset field.
 func (r *mutationResolver) UpdateAsset(ctx context.Context, id string, input model.UpdateAssetInput) (*models.Asset, error) {
-	panic(fmt.Errorf("not implemented: UpdateAsset - updateAsset"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	placeID, err := parseOptionalID(ctx, input.Place)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := parseOptionalID(ctx, input.Parent)
+	if err != nil {
+		return nil, err
+	}
+	cause, err := toAssetStatusCause(ctx, input.StatusCause)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := r.AssetService.UpdateAsset(user, assetID, &service.UpdateAssetInput{
+		Name:        input.Name,
+		Type:        input.Type,
+		Status:      input.Status,
+		Location:    input.Location,
+		Metadata:    input.Metadata,
+		PlaceID:     placeID,
+		ParentID:    parentID,
+		StatusCause: cause,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", id)
+	}
+	return asset, nil
+}
+
+// DecommissionAsset is the resolver for the decommissionAsset field.
+func (r *mutationResolver) DecommissionAsset(ctx context.Context, id string, reason *string) (*models.Asset, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var why string
+	if reason != nil {
+		why = *reason
+	}
+	asset, err := r.AssetService.DecommissionAsset(user, assetID, why)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", id)
+	}
+	return asset, nil
 }
 
 // DeleteAsset is the resolver for the deleteAsset field.
 func (r *mutationResolver) DeleteAsset(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteAsset - deleteAsset"))
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	assetID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.AssetService.DeleteAsset(user, assetID); err != nil {
+		return false, toGraphQLError(ctx, err, "asset", id)
+	}
+	return true, nil
+}
+
+// AddComment is the resolver for the addComment field.
+func (r *mutationResolver) AddComment(ctx context.Context, input model.AddCommentInput) (*models.Comment, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ticketID, err := parseID(ctx, input.Ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := r.CommentService.AddComment(user, &service.AddCommentInput{
+		TicketID: ticketID,
+		Content:  input.Content,
+		Internal: input.Internal != nil && *input.Internal,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", input.Ticket)
+	}
+	return comment, nil
+}
+
+// EditComment is the resolver for the editComment field.
+func (r *mutationResolver) EditComment(ctx context.Context, id string, content string) (*models.Comment, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.CommentService.EditComment(user, commentID, content)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "comment", id)
+	}
+	return comment, nil
+}
+
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	commentID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.CommentService.DeleteComment(user, commentID); err != nil {
+		return false, toGraphQLError(ctx, err, "comment", id)
+	}
+	return true, nil
 }
 
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserInput) (*models.User, error) {
-	panic(fmt.Errorf("not implemented: CreateUser - createUser"))
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.UserService.CreateUser(actor, &service.CreateUserInput{
+		Email:    input.Email,
+		Name:     input.Name,
+		Role:     input.Role,
+		Password: input.Password,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", "")
+	}
+	return user, nil
 }
 
 // UpdateUser is the resolver for the updateUser field.
 func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*models.User, error) {
-	panic(fmt.Errorf("not implemented: UpdateUser - updateUser"))
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.UserService.UpdateUser(actor, userID, &service.UpdateUserInput{
+		Email:    input.Email,
+		Name:     input.Name,
+		Role:     input.Role,
+		Password: input.Password,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", id)
+	}
+	return user, nil
 }
 
 // DeleteUser is the resolver for the deleteUser field.
 func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteUser - deleteUser"))
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	userID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.UserService.DeleteUser(actor, userID); err != nil {
+		return false, toGraphQLError(ctx, err, "user", id)
+	}
+	return true, nil
+}
+
+// UpdateNotificationPreferences is the resolver for the updateNotificationPreferences field.
+func (r *mutationResolver) UpdateNotificationPreferences(ctx context.Context, input model.NotificationPreferencesInput) (*models.User, error) {
+	actor, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.UserService.UpdateNotificationPreferences(actor, &service.UpdateNotificationPreferencesInput{
+		OnAssignment:         input.OnAssignment,
+		OnResolution:         input.OnResolution,
+		OnMaintenanceOverdue: input.OnMaintenanceOverdue,
+		OnEscalation:         input.OnEscalation,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", actor.ID.String())
+	}
+	return user, nil
 }
 
 // CreateMaintenanceSchedule is the resolver for the createMaintenanceSchedule field.
 func (r *mutationResolver) CreateMaintenanceSchedule(ctx context.Context, input model.CreateMaintenanceScheduleInput) (*models.MaintenanceSchedule, error) {
-	panic(fmt.Errorf("not implemented: CreateMaintenanceSchedule - createMaintenanceSchedule"))
+	assetID, err := parseID(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+	assignedToID, err := parseID(ctx, input.AssignedTo)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.AssetRepo.GetByID(assetID); err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", input.Asset)
+	}
+	if _, err := r.UserService.GetUser(assignedToID); err != nil {
+		return nil, toGraphQLError(ctx, err, "user", input.AssignedTo)
+	}
+
+	nextDue, err := r.MaintenanceService.NextDue(assetID, input.Frequency, time.Now())
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", input.Asset)
+	}
+
+	schedule := &models.MaintenanceSchedule{
+		AssetID:      assetID,
+		Frequency:    input.Frequency,
+		NextDue:      nextDue,
+		AssignedToID: assignedToID,
+		Status:       models.MaintenanceStatusScheduled,
+	}
+	if input.Notes != nil {
+		schedule.Notes = *input.Notes
+	}
+
+	if err := r.MaintenanceScheduleRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+	return r.MaintenanceScheduleRepo.GetByID(schedule.ID)
 }
 
 // UpdateMaintenanceSchedule is the resolver for the updateMaintenanceSchedule field.
 func (r *mutationResolver) UpdateMaintenanceSchedule(ctx context.Context, id string, input model.UpdateMaintenanceScheduleInput) (*models.MaintenanceSchedule, error) {
-	panic(fmt.Errorf("not implemented: UpdateMaintenanceSchedule - updateMaintenanceSchedule"))
+	scheduleID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignedToID, err := parseOptionalID(ctx, input.AssignedTo)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := r.MaintenanceScheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance schedule", id)
+	}
+
+	if input.Frequency != nil && *input.Frequency != schedule.Frequency {
+		schedule.Frequency = *input.Frequency
+		from := schedule.CreatedAt
+		if schedule.LastPerformed != nil {
+			from = *schedule.LastPerformed
+		}
+		nextDue, err := r.MaintenanceService.NextDue(schedule.AssetID, schedule.Frequency, from)
+		if err != nil {
+			return nil, toGraphQLError(ctx, err, "asset", uuidToString(schedule.AssetID))
+		}
+		schedule.NextDue = nextDue
+	}
+	if assignedToID != nil {
+		if _, err := r.UserService.GetUser(*assignedToID); err != nil {
+			return nil, toGraphQLError(ctx, err, "user", *input.AssignedTo)
+		}
+		schedule.AssignedToID = *assignedToID
+	}
+	if input.Status != nil {
+		schedule.Status = *input.Status
+	}
+	if input.Notes != nil {
+		schedule.Notes = *input.Notes
+	}
+
+	if err := r.MaintenanceScheduleRepo.Update(schedule); err != nil {
+		return nil, err
+	}
+	return r.MaintenanceScheduleRepo.GetByID(scheduleID)
 }
 
 // DeleteMaintenanceSchedule is the resolver for the deleteMaintenanceSchedule field.
 func (r *mutationResolver) DeleteMaintenanceSchedule(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteMaintenanceSchedule - deleteMaintenanceSchedule"))
+	scheduleID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if _, err := r.MaintenanceScheduleRepo.GetByID(scheduleID); err != nil {
+		return false, toGraphQLError(ctx, err, "maintenance schedule", id)
+	}
+	if err := r.MaintenanceScheduleRepo.Delete(scheduleID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordMaintenance is the resolver for the recordMaintenance field.
+func (r *mutationResolver) RecordMaintenance(ctx context.Context, input model.RecordMaintenanceInput) (*models.MaintenanceRecord, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := parseID(ctx, input.Asset)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceInput := &service.RecordMaintenanceInput{
+		AssetID:     assetID,
+		Type:        input.Type,
+		PerformedAt: input.PerformedAt,
+	}
+	if input.Notes != nil {
+		serviceInput.Notes = *input.Notes
+	}
+	for _, line := range input.Parts {
+		partID, err := parseID(ctx, line.Part)
+		if err != nil {
+			return nil, err
+		}
+		serviceInput.Parts = append(serviceInput.Parts, service.PartUsageInput{
+			PartID:   partID,
+			Quantity: line.Quantity,
+		})
+	}
+
+	record, err := r.MaintenanceService.RecordMaintenance(user, serviceInput)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", input.Asset)
+	}
+	return record, nil
+}
+
+// CreatePart is the resolver for the createPart field.
+func (r *mutationResolver) CreatePart(ctx context.Context, input model.CreatePartInput) (*models.Part, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	part, err := r.InventoryService.CreatePart(user, &service.CreatePartInput{
+		Name:            input.Name,
+		Description:     input.Description,
+		Quantity:        input.Quantity,
+		MinimumQuantity: input.MinimumQuantity,
+		Location:        input.Location,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "part", "")
+	}
+	return part, nil
+}
+
+// UpdatePart is the resolver for the updatePart field.
+func (r *mutationResolver) UpdatePart(ctx context.Context, id string, input model.UpdatePartInput) (*models.Part, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	part, err := r.InventoryService.UpdatePart(user, partID, &service.UpdatePartInput{
+		Name:            input.Name,
+		Description:     input.Description,
+		MinimumQuantity: input.MinimumQuantity,
+		Location:        input.Location,
+	})
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "part", id)
+	}
+	return part, nil
+}
+
+// RestockPart is the resolver for the restockPart field.
+func (r *mutationResolver) RestockPart(ctx context.Context, id string, quantity int, note *string) (*models.Part, error) {
+	var restockNote string
+	if note != nil {
+		restockNote = *note
+	}
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	part, err := r.InventoryService.RestockPart(user, partID, quantity, restockNote)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "part", id)
+	}
+	return part, nil
+}
+
+// AdjustStock is the resolver for the adjustStock field.
+func (r *mutationResolver) AdjustStock(ctx context.Context, id string, change int, note string) (*models.Part, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	partID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	part, err := r.InventoryService.AdjustStock(user, partID, change, note)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "part", id)
+	}
+	return part, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.CreateWebhookInput) (*models.Webhook, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := r.WebhookService.CreateWebhook(user, toCreateWebhookInput(input))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhook", "")
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.UpdateWebhookInput) (*models.Webhook, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhookID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := r.WebhookService.UpdateWebhook(user, webhookID, toUpdateWebhookInput(input))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhook", id)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	webhookID, err := parseID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.WebhookService.DeleteWebhook(user, webhookID); err != nil {
+		return false, toGraphQLError(ctx, err, "webhook", id)
+	}
+	return true, nil
+}
+
+// RedeliverWebhookDelivery is the resolver for the redeliverWebhookDelivery field.
+func (r *mutationResolver) RedeliverWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deliveryID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := r.WebhookService.Redeliver(user, deliveryID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhook delivery", id)
+	}
+	return delivery, nil
 }
 
 // ID is the resolver for the id field.
 func (r *partResolver) ID(ctx context.Context, obj *models.Part) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// Movements is the resolver for the movements field.
+func (r *partResolver) Movements(ctx context.Context, obj *models.Part) ([]*models.StockMovement, error) {
+	return r.InventoryService.GetStockMovements(obj.ID)
 }
 
 // ID is the resolver for the id field.
 func (r *partUsageResolver) ID(ctx context.Context, obj *models.PartUsage) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// MaintenanceRecord is the resolver for the maintenanceRecord field.
+func (r *partUsageResolver) MaintenanceRecord(ctx context.Context, obj *models.PartUsage) (*models.MaintenanceRecord, error) {
+	record, err := r.MaintenanceService.GetRecord(obj.MaintenanceRecordID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance record", uuidToString(obj.MaintenanceRecordID))
+	}
+	return record, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*models.User, error) {
+	return currentUser(ctx)
 }
 
 // Tickets is the resolver for the tickets field.
-func (r *queryResolver) Tickets(ctx context.Context, filter *models.TicketFilter) ([]*models.Ticket, error) {
-	panic(fmt.Errorf("not implemented: Tickets - tickets"))
+func (r *queryResolver) Tickets(ctx context.Context, filter *models.TicketFilter, first *int, after *string, orderBy *models.OrderBy) (*model.TicketConnection, error) {
+	page, err := r.TicketService.GetTickets(filter, pageArgs(first, after, orderBy))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "tickets", "")
+	}
+	return &model.TicketConnection{
+		Edges: toEdges(page, func(cursor string, node *models.Ticket) *model.TicketEdge {
+			return &model.TicketEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
 }
 
 // Ticket is the resolver for the ticket field.
 func (r *queryResolver) Ticket(ctx context.Context, id string) (*models.Ticket, error) {
-	panic(fmt.Errorf("not implemented: Ticket - ticket"))
+	ticketID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := r.TicketService.GetTicket(ticketID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", id)
+	}
+	return ticket, nil
 }
 
 // Assets is the resolver for the assets field.
-func (r *queryResolver) Assets(ctx context.Context, filter *models.AssetFilter) ([]*models.Asset, error) {
-	panic(fmt.Errorf("not implemented: Assets - assets"))
+func (r *queryResolver) Assets(ctx context.Context, filter *models.AssetFilter, first *int, after *string, orderBy *models.OrderBy) (*model.AssetConnection, error) {
+	page, err := r.AssetService.GetAssets(filter, pageArgs(first, after, orderBy))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "assets", "")
+	}
+	return &model.AssetConnection{
+		Edges: toEdges(page, func(cursor string, node *models.Asset) *model.AssetEdge {
+			return &model.AssetEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
 }
 
 // Asset is the resolver for the asset field.
 func (r *queryResolver) Asset(ctx context.Context, id string) (*models.Asset, error) {
-	panic(fmt.Errorf("not implemented: Asset - asset"))
+	assetID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := r.AssetService.GetAsset(assetID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset", id)
+	}
+	return asset, nil
+}
+
+// AssetByQRCode is the resolver for the assetByQRCode field.
+func (r *queryResolver) AssetByQRCode(ctx context.Context, code string) (*models.Asset, error) {
+	asset, err := r.AssetService.GetAssetByQRCode(code)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset with QR code", code)
+	}
+	return asset, nil
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, filter *models.UserFilter) ([]*models.User, error) {
-	panic(fmt.Errorf("not implemented: Users - users"))
+func (r *queryResolver) Users(ctx context.Context, filter *models.UserFilter, first *int, after *string, orderBy *models.OrderBy) (*model.UserConnection, error) {
+	page, err := r.UserService.GetUsers(filter, pageArgs(first, after, orderBy))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "users", "")
+	}
+	return &model.UserConnection{
+		Edges: toEdges(page, func(cursor string, node *models.User) *model.UserEdge {
+			return &model.UserEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
 }
 
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id string) (*models.User, error) {
-	panic(fmt.Errorf("not implemented: User - user"))
+	userID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.UserService.GetUser(userID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "user", id)
+	}
+	return user, nil
 }
 
 // MaintenanceSchedules is the resolver for the maintenanceSchedules field.
-func (r *queryResolver) MaintenanceSchedules(ctx context.Context, filter *models.MaintenanceScheduleFilter) ([]*models.MaintenanceSchedule, error) {
-	panic(fmt.Errorf("not implemented: MaintenanceSchedules - maintenanceSchedules"))
+func (r *queryResolver) MaintenanceSchedules(ctx context.Context, filter *models.MaintenanceScheduleFilter, first *int, after *string, orderBy *models.OrderBy) (*model.MaintenanceScheduleConnection, error) {
+	page, err := r.MaintenanceScheduleRepo.GetAll(filter, pageArgs(first, after, orderBy))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance schedules", "")
+	}
+	return &model.MaintenanceScheduleConnection{
+		Edges: toEdges(page, func(cursor string, node *models.MaintenanceSchedule) *model.MaintenanceScheduleEdge {
+			return &model.MaintenanceScheduleEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
 }
 
 // MaintenanceSchedule is the resolver for the maintenanceSchedule field.
 func (r *queryResolver) MaintenanceSchedule(ctx context.Context, id string) (*models.MaintenanceSchedule, error) {
-	panic(fmt.Errorf("not implemented: MaintenanceSchedule - maintenanceSchedule"))
+	scheduleID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := r.MaintenanceScheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance schedule", id)
+	}
+	return schedule, nil
+}
+
+// Parts is the resolver for the parts field.
+func (r *queryResolver) Parts(ctx context.Context, filter *models.PartFilter, first *int, after *string, orderBy *models.OrderBy) (*model.PartConnection, error) {
+	page, err := r.InventoryService.GetParts(filter, pageArgs(first, after, orderBy))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "parts", "")
+	}
+	return &model.PartConnection{
+		Edges: toEdges(page, func(cursor string, node *models.Part) *model.PartEdge {
+			return &model.PartEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
+}
+
+// Part is the resolver for the part field.
+func (r *queryResolver) Part(ctx context.Context, id string) (*models.Part, error) {
+	partID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	part, err := r.InventoryService.GetPart(partID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "part", id)
+	}
+	return part, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, types []models.SearchableType, first *int) ([]*models.SearchHit, error) {
+	viewer, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := r.SearchService.Search(viewer, query, types, first)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "search", "")
+	}
+	return hits, nil
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*models.Webhook, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhooks, err := r.WebhookService.GetWebhooks(user)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhooks", "")
+	}
+	return webhooks, nil
+}
+
+// Webhook is the resolver for the webhook field.
+func (r *queryResolver) Webhook(ctx context.Context, id string) (*models.Webhook, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhookID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := r.WebhookService.GetWebhook(user, webhookID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhook", id)
+	}
+	return webhook, nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID string, filter *models.WebhookDeliveryFilter, first *int, after *string) (*model.WebhookDeliveryConnection, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	page, err := r.WebhookService.GetDeliveries(user, id, filter, pageArgs(first, after, nil))
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "webhook", webhookID)
+	}
+	return &model.WebhookDeliveryConnection{
+		Edges: toEdges(page, func(cursor string, node *models.WebhookDelivery) *model.WebhookDeliveryEdge {
+			return &model.WebhookDeliveryEdge{Cursor: cursor, Node: node}
+		}),
+		PageInfo:   &page.PageInfo,
+		TotalCount: int(page.TotalCount),
+	}, nil
+}
+
+// SLAPolicies is the resolver for the slaPolicies field.
+func (r *queryResolver) SLAPolicies(ctx context.Context) ([]*models.SLAPolicy, error) {
+	policies, err := r.SLAService.GetSLAPolicies()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "SLA policies", "")
+	}
+	return policies, nil
+}
+
+// BusinessCalendars is the resolver for the businessCalendars field.
+func (r *queryResolver) BusinessCalendars(ctx context.Context) ([]*models.BusinessCalendar, error) {
+	calendars, err := r.CalendarService.GetBusinessCalendars()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "business calendars", "")
+	}
+	return calendars, nil
+}
+
+// BusinessCalendar is the resolver for the businessCalendar field.
+func (r *queryResolver) BusinessCalendar(ctx context.Context, id string) (*models.BusinessCalendar, error) {
+	calendarID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := r.CalendarService.GetBusinessCalendar(calendarID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "business calendar", id)
+	}
+	return calendar, nil
+}
+
+// Locations is the resolver for the locations field.
+func (r *queryResolver) Locations(ctx context.Context, parent *string) ([]*models.Location, error) {
+	parentID, err := parseOptionalID(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := r.LocationService.GetLocations(parentID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "locations", "")
+	}
+	return locations, nil
+}
+
+// Location is the resolver for the location field.
+func (r *queryResolver) Location(ctx context.Context, id string) (*models.Location, error) {
+	locationID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	location, err := r.LocationService.GetLocation(locationID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "location", id)
+	}
+	return location, nil
+}
+
+// EscalationRules is the resolver for the escalationRules field.
+func (r *queryResolver) EscalationRules(ctx context.Context) ([]*models.EscalationRule, error) {
+	rules, err := r.EscalationService.GetEscalationRules()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "escalation rules", "")
+	}
+	return rules, nil
+}
+
+// AssetTypeSchemas is the resolver for the assetTypeSchemas field.
+func (r *queryResolver) AssetTypeSchemas(ctx context.Context) ([]*models.AssetTypeSchema, error) {
+	schemas, err := r.AssetTypeSchemaService.GetAssetTypeSchemas()
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset type schemas", "")
+	}
+	return schemas, nil
+}
+
+// AssetTypeSchema is the resolver for the assetTypeSchema field.
+func (r *queryResolver) AssetTypeSchema(ctx context.Context, typeArg models.AssetType) (*models.AssetTypeSchema, error) {
+	assetTypeSchema, err := r.AssetTypeSchemaService.GetAssetTypeSchema(typeArg)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "asset type schema", string(typeArg))
+	}
+	return assetTypeSchema, nil
+}
+
+// ID is the resolver for the id field.
+func (r *sLAPolicyResolver) ID(ctx context.Context, obj *models.SLAPolicy) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// ID is the resolver for the id field.
+func (r *stockMovementResolver) ID(ctx context.Context, obj *models.StockMovement) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// MaintenanceRecord is the resolver for the maintenanceRecord field.
+func (r *stockMovementResolver) MaintenanceRecord(ctx context.Context, obj *models.StockMovement) (*models.MaintenanceRecord, error) {
+	if obj.MaintenanceRecordID == nil {
+		return nil, nil
+	}
+	record, err := r.MaintenanceService.GetRecord(*obj.MaintenanceRecordID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "maintenance record", uuidToString(*obj.MaintenanceRecordID))
+	}
+	return record, nil
+}
+
+// TicketCreated is the resolver for the ticketCreated field.
+func (r *subscriptionResolver) TicketCreated(ctx context.Context) (<-chan *models.Ticket, error) {
+	if _, err := currentUser(ctx); err != nil {
+		return nil, err
+	}
+	return r.ticketStream(ctx, ticketCreatedMatch), nil
+}
+
+// TicketUpdated is the resolver for the ticketUpdated field.
+func (r *subscriptionResolver) TicketUpdated(ctx context.Context, id string) (<-chan *models.Ticket, error) {
+	if _, err := currentUser(ctx); err != nil {
+		return nil, err
+	}
+	ticketID, err := parseID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.ticketStream(ctx, ticketUpdatedMatch(ticketID)), nil
+}
+
+// TicketAssignedToMe is the resolver for the ticketAssignedToMe field.
+func (r *subscriptionResolver) TicketAssignedToMe(ctx context.Context) (<-chan *models.Ticket, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.ticketStream(ctx, ticketAssignedMatch(user.ID)), nil
+}
+
+// CommentAdded is the resolver for the commentAdded field.
+func (r *subscriptionResolver) CommentAdded(ctx context.Context, ticketID string) (<-chan *models.Comment, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseID(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return r.commentStream(ctx, user, commentAddedMatch(id)), nil
 }
 
 // ID is the resolver for the id field.
 func (r *ticketResolver) ID(ctx context.Context, obj *models.Ticket) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// SLA is the resolver for the sla field.
+func (r *ticketResolver) SLA(ctx context.Context, obj *models.Ticket) (*model.TicketSLA, error) {
+	if obj.SLAPolicyID == nil {
+		return nil, nil
+	}
+	if obj.SLAPolicy == nil {
+		policy, err := r.SLAService.GetSLAPolicy(*obj.SLAPolicyID)
+		if err != nil {
+			return nil, toGraphQLError(ctx, err, "SLA policy", obj.SLAPolicyID.String())
+		}
+		obj.SLAPolicy = policy
+	}
+	now := time.Now()
+	return &model.TicketSLA{
+		Policy:             obj.SLAPolicy,
+		ResponseDueAt:      obj.ResponseDueAt,
+		ResolutionDueAt:    obj.ResolutionDueAt,
+		RespondedAt:        obj.RespondedAt,
+		Paused:             obj.SLAPaused(),
+		ResponseBreached:   obj.ResponseBreached(now),
+		ResolutionBreached: obj.ResolutionBreached(now),
+		Breached:           obj.SLABreached(now),
+		AtRisk:             obj.SLAAtRisk(now),
+	}, nil
+}
+
+// Comments is the resolver for the comments field.
+func (r *ticketResolver) Comments(ctx context.Context, obj *models.Ticket) ([]*models.Comment, error) {
+	viewer, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.CommentService.GetComments(viewer, obj.ID)
+}
+
+// History is the resolver for the history field.
+func (r *ticketResolver) History(ctx context.Context, obj *models.Ticket) ([]*models.TicketEvent, error) {
+	history, err := r.TicketService.GetTicketHistory(obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", uuidToString(obj.ID))
+	}
+	return history, nil
+}
+
+// Timeline is the resolver for the timeline field.
+func (r *ticketResolver) Timeline(ctx context.Context, obj *models.Ticket) ([]models.TimelineEntry, error) {
+	viewer, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	timeline, err := r.TicketService.GetTicketTimeline(viewer, obj.ID)
+	if err != nil {
+		return nil, toGraphQLError(ctx, err, "ticket", uuidToString(obj.ID))
+	}
+	return timeline, nil
+}
+
+// ID is the resolver for the id field.
+func (r *ticketEventResolver) ID(ctx context.Context, obj *models.TicketEvent) (string, error) {
+	return uuidToString(obj.ID), nil
 }
 
 // ID is the resolver for the id field.
 func (r *userResolver) ID(ctx context.Context, obj *models.User) (string, error) {
-	panic(fmt.Errorf("not implemented: ID - id"))
+	return uuidToString(obj.ID), nil
+}
+
+// ID is the resolver for the id field.
+func (r *webhookResolver) ID(ctx context.Context, obj *models.Webhook) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// EventTypes is the resolver for the eventTypes field.
+func (r *webhookResolver) EventTypes(ctx context.Context, obj *models.Webhook) ([]model.WebhookEventType, error) {
+	types := make([]model.WebhookEventType, 0, len(obj.EventTypes))
+	for _, eventType := range obj.EventTypes {
+		converted, err := toWebhookEventType(ctx, eventType)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, converted)
+	}
+	return types, nil
+}
+
+// ID is the resolver for the id field.
+func (r *webhookDeliveryResolver) ID(ctx context.Context, obj *models.WebhookDelivery) (string, error) {
+	return uuidToString(obj.ID), nil
+}
+
+// EventID is the resolver for the eventId field.
+func (r *webhookDeliveryResolver) EventID(ctx context.Context, obj *models.WebhookDelivery) (string, error) {
+	return uuidToString(obj.EventID), nil
+}
+
+// EventType is the resolver for the eventType field.
+func (r *webhookDeliveryResolver) EventType(ctx context.Context, obj *models.WebhookDelivery) (model.WebhookEventType, error) {
+	return toWebhookEventType(ctx, obj.EventType)
+}
+
+// Place is the resolver for the place field.
+func (r *assetFilterResolver) Place(ctx context.Context, obj *models.AssetFilter, data *string) error {
+	placeID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.PlaceID = placeID
+	return nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *assetFilterResolver) Parent(ctx context.Context, obj *models.AssetFilter, data *string) error {
+	parentID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.ParentID = parentID
+	return nil
 }
 
 // AssignedTo is the resolver for the assignedTo field.
 func (r *maintenanceScheduleFilterResolver) AssignedTo(ctx context.Context, obj *models.MaintenanceScheduleFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: AssignedTo - assignedTo"))
+	assignedToID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.AssignedToID = assignedToID
+	return nil
 }
 
 // Asset is the resolver for the asset field.
 func (r *maintenanceScheduleFilterResolver) Asset(ctx context.Context, obj *models.MaintenanceScheduleFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: Asset - asset"))
+	assetID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.AssetID = assetID
+	return nil
+}
+
+// Path is the resolver for the path field.
+func (r *metadataFilterResolver) Path(ctx context.Context, obj *models.MetadataFilter, data string) error {
+	obj.Path = strings.Split(data, ".")
+	return nil
 }
 
 // AssignedTo is the resolver for the assignedTo field.
 func (r *ticketFilterResolver) AssignedTo(ctx context.Context, obj *models.TicketFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: AssignedTo - assignedTo"))
+	assignedToID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.AssignedToID = assignedToID
+	return nil
 }
 
 // CreatedBy is the resolver for the createdBy field.
 func (r *ticketFilterResolver) CreatedBy(ctx context.Context, obj *models.TicketFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: CreatedBy - createdBy"))
+	createdByID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.CreatedByID = createdByID
+	return nil
 }
 
 // Asset is the resolver for the asset field.
 func (r *ticketFilterResolver) Asset(ctx context.Context, obj *models.TicketFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: Asset - asset"))
+	assetID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.AssetID = assetID
+	return nil
 }
 
-// Email is the resolver for the email field.
-func (r *userFilterResolver) Email(ctx context.Context, obj *models.UserFilter, data *string) error {
-	panic(fmt.Errorf("not implemented: Email - email"))
+// Place is the resolver for the place field.
+func (r *ticketFilterResolver) Place(ctx context.Context, obj *models.TicketFilter, data *string) error {
+	placeID, err := parseOptionalID(ctx, data)
+	if err != nil {
+		return err
+	}
+	obj.PlaceID = placeID
+	return nil
 }
 
 // Asset returns generated.AssetResolver implementation.
 func (r *Resolver) Asset() generated.AssetResolver { return &assetResolver{r} }
 
+// AssetAvailability returns generated.AssetAvailabilityResolver implementation.
+func (r *Resolver) AssetAvailability() generated.AssetAvailabilityResolver {
+	return &assetAvailabilityResolver{r}
+}
+
+// AssetStatusChange returns generated.AssetStatusChangeResolver implementation.
+func (r *Resolver) AssetStatusChange() generated.AssetStatusChangeResolver {
+	return &assetStatusChangeResolver{r}
+}
+
+// BusinessCalendar returns generated.BusinessCalendarResolver implementation.
+func (r *Resolver) BusinessCalendar() generated.BusinessCalendarResolver {
+	return &businessCalendarResolver{r}
+}
+
 // Comment returns generated.CommentResolver implementation.
 func (r *Resolver) Comment() generated.CommentResolver { return &commentResolver{r} }
 
+// CommentRevision returns generated.CommentRevisionResolver implementation.
+func (r *Resolver) CommentRevision() generated.CommentRevisionResolver {
+	return &commentRevisionResolver{r}
+}
+
+// EscalationRule returns generated.EscalationRuleResolver implementation.
+func (r *Resolver) EscalationRule() generated.EscalationRuleResolver {
+	return &escalationRuleResolver{r}
+}
+
+// Location returns generated.LocationResolver implementation.
+func (r *Resolver) Location() generated.LocationResolver { return &locationResolver{r} }
+
 // MaintenanceRecord returns generated.MaintenanceRecordResolver implementation.
 func (r *Resolver) MaintenanceRecord() generated.MaintenanceRecordResolver {
 	return &maintenanceRecordResolver{r}
@@ -211,33 +1743,71 @@ func (r *Resolver) PartUsage() generated.PartUsageResolver { return &partUsageRe
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// SLAPolicy returns generated.SLAPolicyResolver implementation.
+func (r *Resolver) SLAPolicy() generated.SLAPolicyResolver { return &sLAPolicyResolver{r} }
+
+// StockMovement returns generated.StockMovementResolver implementation.
+func (r *Resolver) StockMovement() generated.StockMovementResolver { return &stockMovementResolver{r} }
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
 // Ticket returns generated.TicketResolver implementation.
 func (r *Resolver) Ticket() generated.TicketResolver { return &ticketResolver{r} }
 
+// TicketEvent returns generated.TicketEventResolver implementation.
+func (r *Resolver) TicketEvent() generated.TicketEventResolver { return &ticketEventResolver{r} }
+
 // User returns generated.UserResolver implementation.
 func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
 
+// Webhook returns generated.WebhookResolver implementation.
+func (r *Resolver) Webhook() generated.WebhookResolver { return &webhookResolver{r} }
+
+// WebhookDelivery returns generated.WebhookDeliveryResolver implementation.
+func (r *Resolver) WebhookDelivery() generated.WebhookDeliveryResolver {
+	return &webhookDeliveryResolver{r}
+}
+
+// AssetFilter returns generated.AssetFilterResolver implementation.
+func (r *Resolver) AssetFilter() generated.AssetFilterResolver { return &assetFilterResolver{r} }
+
 // MaintenanceScheduleFilter returns generated.MaintenanceScheduleFilterResolver implementation.
 func (r *Resolver) MaintenanceScheduleFilter() generated.MaintenanceScheduleFilterResolver {
 	return &maintenanceScheduleFilterResolver{r}
 }
 
+// MetadataFilter returns generated.MetadataFilterResolver implementation.
+func (r *Resolver) MetadataFilter() generated.MetadataFilterResolver {
+	return &metadataFilterResolver{r}
+}
+
 // TicketFilter returns generated.TicketFilterResolver implementation.
 func (r *Resolver) TicketFilter() generated.TicketFilterResolver { return &ticketFilterResolver{r} }
 
-// UserFilter returns generated.UserFilterResolver implementation.
-func (r *Resolver) UserFilter() generated.UserFilterResolver { return &userFilterResolver{r} }
-
 type assetResolver struct{ *Resolver }
+type assetAvailabilityResolver struct{ *Resolver }
+type assetStatusChangeResolver struct{ *Resolver }
+type businessCalendarResolver struct{ *Resolver }
 type commentResolver struct{ *Resolver }
+type commentRevisionResolver struct{ *Resolver }
+type escalationRuleResolver struct{ *Resolver }
+type locationResolver struct{ *Resolver }
 type maintenanceRecordResolver struct{ *Resolver }
 type maintenanceScheduleResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type partResolver struct{ *Resolver }
 type partUsageResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type sLAPolicyResolver struct{ *Resolver }
+type stockMovementResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
 type ticketResolver struct{ *Resolver }
+type ticketEventResolver struct{ *Resolver }
 type userResolver struct{ *Resolver }
+type webhookResolver struct{ *Resolver }
+type webhookDeliveryResolver struct{ *Resolver }
+type assetFilterResolver struct{ *Resolver }
 type maintenanceScheduleFilterResolver struct{ *Resolver }
+type metadataFilterResolver struct{ *Resolver }
 type ticketFilterResolver struct{ *Resolver }
-type userFilterResolver struct{ *Resolver }
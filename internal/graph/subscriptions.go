@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// subscribe streams the records behind the events accepted by match to a
+// GraphQL subscription until ctx is done. load fetches the current state of
+// the record an event refers to; events it rejects are skipped.
+func subscribe[T any](ctx context.Context, source events.Subscriber, match func(events.Event) bool, load func(events.Event) (T, error)) <-chan T {
+	in := source.Subscribe(ctx, match)
+	out := make(chan T, 1)
+	go func() {
+		defer close(out)
+		for event := range in {
+			item, err := load(event)
+			if err != nil {
+				continue
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ticketCreatedMatch matches new tickets
+func ticketCreatedMatch(e events.Event) bool {
+	return e.Type == events.TicketCreated && e.TicketID != nil
+}
+
+// ticketStream streams the tickets referred to by the events accepted by match
+func (r *Resolver) ticketStream(ctx context.Context, match func(events.Event) bool) <-chan *models.Ticket {
+	return subscribe(ctx, r.Events, match, func(e events.Event) (*models.Ticket, error) {
+		return r.TicketService.GetTicket(*e.TicketID)
+	})
+}
+
+// commentStream streams the comments added by the events accepted by match
+// that viewer is allowed to see
+func (r *Resolver) commentStream(ctx context.Context, viewer *models.User, match func(events.Event) bool) <-chan *models.Comment {
+	return subscribe(ctx, r.Events, match, func(e events.Event) (*models.Comment, error) {
+		return r.CommentService.GetComment(viewer, *e.CommentID)
+	})
+}
+
+// ticketUpdatedMatch matches updates to one ticket
+func ticketUpdatedMatch(ticketID uuid.UUID) func(events.Event) bool {
+	return func(e events.Event) bool {
+		return e.Type == events.TicketUpdated && isTicket(e, ticketID)
+	}
+}
+
+// ticketAssignedMatch matches tickets being assigned to a user
+func ticketAssignedMatch(userID uuid.UUID) func(events.Event) bool {
+	return func(e events.Event) bool {
+		return e.Type == events.TicketAssigned && e.TicketID != nil && e.AssigneeID != nil && *e.AssigneeID == userID
+	}
+}
+
+// commentAddedMatch matches comments added to one ticket
+func commentAddedMatch(ticketID uuid.UUID) func(events.Event) bool {
+	return func(e events.Event) bool {
+		return e.Type == events.CommentAdded && isTicket(e, ticketID) && e.CommentID != nil
+	}
+}
+
+// isTicket reports whether an event concerns the given ticket
+func isTicket(e events.Event, ticketID uuid.UUID) bool {
+	return e.TicketID != nil && *e.TicketID == ticketID
+}
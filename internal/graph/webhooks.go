@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/graph/model"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// webhookEventTypes maps the GraphQL webhook event types onto event types
+var webhookEventTypes = map[model.WebhookEventType]events.Type{
+	model.WebhookEventTypeTicketCreated:       events.TicketCreated,
+	model.WebhookEventTypeTicketStatusChanged: events.TicketStatusChanged,
+	model.WebhookEventTypeAssetStatusChanged:  events.AssetStatusChanged,
+	model.WebhookEventTypePartLowStock:        events.PartLowStock,
+}
+
+// toEventTypes converts GraphQL webhook event types into event types
+func toEventTypes(types []model.WebhookEventType) []events.Type {
+	if types == nil {
+		return nil
+	}
+	converted := make([]events.Type, len(types))
+	for i, t := range types {
+		converted[i] = webhookEventTypes[t]
+	}
+	return converted
+}
+
+// toWebhookEventType converts a stored event type into its GraphQL enum value
+func toWebhookEventType(ctx context.Context, eventType string) (model.WebhookEventType, error) {
+	for gqlType, t := range webhookEventTypes {
+		if string(t) == eventType {
+			return gqlType, nil
+		}
+	}
+	return "", validationError(ctx, "unknown webhook event type "+eventType)
+}
+
+// toCreateWebhookInput converts the GraphQL input into the service input
+func toCreateWebhookInput(input model.CreateWebhookInput) *service.CreateWebhookInput {
+	converted := &service.CreateWebhookInput{
+		URL:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: toEventTypes(input.EventTypes),
+		Active:     input.Active,
+	}
+	if input.Description != nil {
+		converted.Description = *input.Description
+	}
+	return converted
+}
+
+// toUpdateWebhookInput converts the GraphQL input into the service input
+func toUpdateWebhookInput(input model.UpdateWebhookInput) *service.UpdateWebhookInput {
+	return &service.UpdateWebhookInput{
+		URL:         input.URL,
+		Secret:      input.Secret,
+		Description: input.Description,
+		EventTypes:  toEventTypes(input.EventTypes),
+		Active:      input.Active,
+	}
+}
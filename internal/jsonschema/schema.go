@@ -0,0 +1,328 @@
+// Package jsonschema validates JSON documents against schemas written in a
+// subset of JSON Schema (draft 2020-12): type, enum, const, the numeric,
+// string, array and object assertions, format for dates, emails and URIs,
+// and the allOf, anyOf, oneOf and not combinators. References and
+// conditionals are not supported; Compile rejects schemas using them rather
+// than silently accepting documents they would reject.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is a compiled schema
+type Schema struct {
+	// always is set for the boolean schemas true and false
+	always *bool
+
+	types    []string
+	enum     []any
+	constant any
+	hasConst bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	items       *Schema
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	properties    map[string]*Schema
+	required      []string
+	additional    *Schema
+	minProperties *int
+	maxProperties *int
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+}
+
+// annotations are keywords that do not affect validation
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+var typeNames = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+// Compile parses a schema
+func Compile(data []byte) (*Schema, error) {
+	v, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema: schema is not valid JSON: %w", err)
+	}
+	return compile(v, "")
+}
+
+// Types returns the types the schema allows at its root, or nil when it
+// does not restrict them
+func (s *Schema) Types() []string {
+	return s.types
+}
+
+func compile(v any, at string) (*Schema, error) {
+	switch v := v.(type) {
+	case bool:
+		return &Schema{always: &v}, nil
+	case map[string]any:
+		c := compiler{object: v, at: at}
+		return c.compile()
+	}
+	return nil, schemaError(at, "a schema must be an object or a boolean")
+}
+
+// compiler reads the keywords of one schema object
+type compiler struct {
+	object map[string]any
+	at     string
+	err    error
+}
+
+func (c *compiler) compile() (*Schema, error) {
+	s := &Schema{}
+	for _, keyword := range sortedKeys(c.object) {
+		value := c.object[keyword]
+		at := c.at + "/" + keyword
+		switch keyword {
+		case "type":
+			s.types = c.typeList(value, at)
+		case "enum":
+			values, ok := value.([]any)
+			if !ok || len(values) == 0 {
+				c.fail(at, "must be a non-empty array")
+			}
+			s.enum = values
+		case "const":
+			s.constant, s.hasConst = value, true
+		case "minimum":
+			s.minimum = c.number(value, at)
+		case "maximum":
+			s.maximum = c.number(value, at)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum = c.number(value, at)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum = c.number(value, at)
+		case "multipleOf":
+			if s.multipleOf = c.number(value, at); s.multipleOf != nil && *s.multipleOf <= 0 {
+				c.fail(at, "must be greater than 0")
+			}
+		case "minLength":
+			s.minLength = c.count(value, at)
+		case "maxLength":
+			s.maxLength = c.count(value, at)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				c.fail(at, "must be a string")
+				continue
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				c.fail(at, "is not a valid regular expression: "+err.Error())
+				continue
+			}
+			s.pattern = re
+		case "format":
+			format, ok := value.(string)
+			if !ok {
+				c.fail(at, "must be a string")
+			}
+			s.format = format
+		case "items":
+			s.items = c.schema(value, at)
+		case "minItems":
+			s.minItems = c.count(value, at)
+		case "maxItems":
+			s.maxItems = c.count(value, at)
+		case "uniqueItems":
+			unique, ok := value.(bool)
+			if !ok {
+				c.fail(at, "must be a boolean")
+			}
+			s.uniqueItems = unique
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				c.fail(at, "must be an object")
+				continue
+			}
+			s.properties = make(map[string]*Schema, len(properties))
+			for _, name := range sortedKeys(properties) {
+				s.properties[name] = c.schema(properties[name], at+"/"+escapePointer(name))
+			}
+		case "required":
+			names, ok := value.([]any)
+			if !ok {
+				c.fail(at, "must be an array of strings")
+				continue
+			}
+			for _, name := range names {
+				name, ok := name.(string)
+				if !ok {
+					c.fail(at, "must be an array of strings")
+					break
+				}
+				s.required = append(s.required, name)
+			}
+		case "additionalProperties":
+			s.additional = c.schema(value, at)
+		case "minProperties":
+			s.minProperties = c.count(value, at)
+		case "maxProperties":
+			s.maxProperties = c.count(value, at)
+		case "allOf":
+			s.allOf = c.schemaList(value, at)
+		case "anyOf":
+			s.anyOf = c.schemaList(value, at)
+		case "oneOf":
+			s.oneOf = c.schemaList(value, at)
+		case "not":
+			s.not = c.schema(value, at)
+		default:
+			if !annotations[keyword] {
+				c.fail(at, "keyword is not supported")
+			}
+		}
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return s, nil
+}
+
+// fail records the first error found
+func (c *compiler) fail(at, message string) {
+	if c.err == nil {
+		c.err = schemaError(at, message)
+	}
+}
+
+func (c *compiler) schema(v any, at string) *Schema {
+	s, err := compile(v, at)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return s
+}
+
+func (c *compiler) schemaList(v any, at string) []*Schema {
+	values, ok := v.([]any)
+	if !ok || len(values) == 0 {
+		c.fail(at, "must be a non-empty array of schemas")
+		return nil
+	}
+	schemas := make([]*Schema, len(values))
+	for i, value := range values {
+		schemas[i] = c.schema(value, at+"/"+strconv.Itoa(i))
+	}
+	return schemas
+}
+
+func (c *compiler) typeList(v any, at string) []string {
+	var names []any
+	switch v := v.(type) {
+	case string:
+		names = []any{v}
+	case []any:
+		names = v
+	}
+	if len(names) == 0 {
+		c.fail(at, "must be a type name or an array of them")
+		return nil
+	}
+	types := make([]string, 0, len(names))
+	for _, name := range names {
+		t, ok := name.(string)
+		if !ok || !typeNames[t] {
+			c.fail(at, fmt.Sprintf("unknown type %v", encodeValue(name)))
+			return nil
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+func (c *compiler) number(v any, at string) *float64 {
+	n, ok := v.(json.Number)
+	if !ok {
+		c.fail(at, "must be a number")
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		c.fail(at, "must be a number")
+		return nil
+	}
+	return &f
+}
+
+func (c *compiler) count(v any, at string) *int {
+	f := c.number(v, at)
+	if f == nil {
+		return nil
+	}
+	if *f < 0 || *f != math.Trunc(*f) || *f > math.MaxInt32 {
+		c.fail(at, "must be a non-negative integer")
+		return nil
+	}
+	n := int(*f)
+	return &n
+}
+
+func schemaError(at, message string) error {
+	if at == "" {
+		return fmt.Errorf("jsonschema: %s", message)
+	}
+	return fmt.Errorf("jsonschema: %s: %s", at, message)
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a property name for use in a JSON pointer
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// decode parses a JSON document keeping numbers as json.Number so large
+// integers are compared exactly
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the document")
+	}
+	return v, nil
+}
@@ -0,0 +1,264 @@
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompileRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"unknown keyword", `{"type": "object", "colour": "red"}`, "/colour: keyword is not supported"},
+		{"reference", `{"$ref": "#/$defs/tonnage"}`, "/$ref: keyword is not supported"},
+		{"conditional", `{"if": {"type": "string"}, "then": {"minLength": 1}}`, "/if: keyword is not supported"},
+		{"nested unknown keyword", `{"properties": {"hvac": {"properties": {"tonnage": {"minimun": 1}}}}}`, "/properties/hvac/properties/tonnage/minimun: keyword is not supported"},
+		{"unknown keyword in items", `{"items": {"typo": true}}`, "/items/typo: keyword is not supported"},
+		{"unknown type", `{"type": "decimal"}`, `/type: unknown type "decimal"`},
+		{"empty enum", `{"enum": []}`, "/enum: must be a non-empty array"},
+		{"non-numeric minimum", `{"minimum": "1"}`, "/minimum: must be a number"},
+		{"negative count", `{"minLength": -1}`, "/minLength: must be a non-negative integer"},
+		{"required not strings", `{"required": ["a", 1]}`, "/required: must be an array of strings"},
+		{"bad pattern", `{"pattern": "("}`, "/pattern: is not a valid regular expression"},
+		{"zero multipleOf", `{"multipleOf": 0}`, "/multipleOf: must be greater than 0"},
+		{"not a schema", `[]`, "a schema must be an object or a boolean"},
+		{"invalid JSON", `{"type": }`, "schema is not valid JSON"},
+		{"trailing data", `{} {}`, "schema is not valid JSON"},
+	}
+	for _, tc := range tests {
+		_, err := Compile([]byte(tc.schema))
+		if err == nil {
+			t.Errorf("%s: compiled, want an error containing %q", tc.name, tc.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got %q, want it to contain %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestCompileAcceptsAnnotations(t *testing.T) {
+	schema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/hvac",
+		"$comment": "tonnage in refrigeration tons",
+		"title": "HVAC",
+		"description": "Metadata of HVAC units",
+		"type": "object",
+		"properties": {"tonnage": {"type": "number", "default": 5, "examples": [5, 10], "deprecated": false, "readOnly": true, "writeOnly": false}}
+	}`
+	if _, err := Compile([]byte(schema)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	tests := map[string]string{
+		`{"type": "object"}`:           "object",
+		`{"type": ["string", "null"]}`: "string,null",
+		`{"minimum": 1}`:               "",
+		`true`:                         "",
+	}
+	for schema, want := range tests {
+		s, err := Compile([]byte(schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(s.Types(), ","); got != want {
+			t.Errorf("Types of %s = %q, want %q", schema, got, want)
+		}
+	}
+}
+
+// hvac is a metadata schema of the kind administrators set per asset type
+const hvac = `{
+	"type": "object",
+	"required": ["manufacturer", "hvac"],
+	"properties": {
+		"manufacturer": {"type": "string", "minLength": 1},
+		"refrigerant": {"enum": ["R-410A", "R-32", "R-454B"]},
+		"installed": {"type": "string", "format": "date"},
+		"hvac": {
+			"type": "object",
+			"required": ["tonnage"],
+			"properties": {
+				"tonnage": {"type": "number", "minimum": 0.5, "maximum": 100},
+				"zones": {"type": "integer", "minimum": 1},
+				"filters": {"type": "array", "items": {"type": "string"}, "uniqueItems": true}
+			},
+			"additionalProperties": false
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(hvac))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		document string
+		// want lists the expected errors, "path: message" as Error formats
+		// them; empty when the document is valid
+		want []string
+	}{
+		{
+			name:     "valid",
+			document: `{"manufacturer": "Trane", "refrigerant": "R-32", "installed": "2024-05-01", "hvac": {"tonnage": 12.5, "zones": 3, "filters": ["MERV13", "MERV8"]}}`,
+		},
+		{
+			name:     "valid at the bounds",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 100}}`,
+		},
+		{
+			name:     "integer written with a fraction",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 0.5, "zones": 2.0}}`,
+		},
+		{
+			name:     "not an object",
+			document: `["Trane"]`,
+			want:     []string{"must be an object"},
+		},
+		{
+			name:     "missing required",
+			document: `{}`,
+			want:     []string{"manufacturer: is required", "hvac: is required"},
+		},
+		{
+			name:     "missing nested required",
+			document: `{"manufacturer": "Trane", "hvac": {}}`,
+			want:     []string{"hvac.tonnage: is required"},
+		},
+		{
+			name:     "wrong types",
+			document: `{"manufacturer": 42, "hvac": {"tonnage": "12"}}`,
+			want:     []string{"hvac.tonnage: must be a number", "manufacturer: must be a string"},
+		},
+		{
+			name:     "not an integer",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 2, "zones": 1.5}}`,
+			want:     []string{"hvac.zones: must be an integer"},
+		},
+		{
+			name:     "below minimum",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 0.25, "zones": 0}}`,
+			want:     []string{"hvac.tonnage: must be at least 0.5", "hvac.zones: must be at least 1"},
+		},
+		{
+			name:     "above maximum",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 100.5}}`,
+			want:     []string{"hvac.tonnage: must be at most 100"},
+		},
+		{
+			name:     "not in enum",
+			document: `{"manufacturer": "Trane", "refrigerant": "R-22", "hvac": {"tonnage": 5}}`,
+			want:     []string{`refrigerant: must be one of "R-410A", "R-32", "R-454B"`},
+		},
+		{
+			name:     "empty string",
+			document: `{"manufacturer": "", "hvac": {"tonnage": 5}}`,
+			want:     []string{"manufacturer: must be at least 1 characters long"},
+		},
+		{
+			name:     "bad date",
+			document: `{"manufacturer": "Trane", "installed": "05/01/2024", "hvac": {"tonnage": 5}}`,
+			want:     []string{"installed: must be a date (YYYY-MM-DD)"},
+		},
+		{
+			name:     "additional nested property",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 5, "seer": 16}}`,
+			want:     []string{"hvac.seer: is not allowed"},
+		},
+		{
+			name:     "array items",
+			document: `{"manufacturer": "Trane", "hvac": {"tonnage": 5, "filters": ["MERV13", 8, "MERV13"]}}`,
+			want:     []string{"hvac.filters: must not contain duplicate items", "hvac.filters[1]: must be a string"},
+		},
+		{
+			name:     "invalid JSON",
+			document: `{"manufacturer": `,
+			want:     []string{"is not valid JSON"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.document))
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("got %v, want valid", err)
+				}
+				return
+			}
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("got %v, want Errors", err)
+			}
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Fatalf("got\n\t%s\nwant\n\t%s", strings.Join(got, "\n\t"), strings.Join(tc.want, "\n\t"))
+			}
+		})
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	tests := []struct {
+		schema   string
+		document string
+		valid    bool
+	}{
+		{`{"type": "null"}`, `null`, true},
+		{`{"type": "boolean"}`, `0`, false},
+		{`{"type": ["string", "number"]}`, `1`, true},
+		{`{"type": ["string", "number"]}`, `true`, false},
+		{`{"type": "integer"}`, `12345678901234567890`, true},
+		{`{"enum": [1, "a", null]}`, `1.0`, true},
+		{`{"enum": [{"a": [1]}]}`, `{"a": [1]}`, true},
+		{`{"enum": [{"a": [1]}]}`, `{"a": [2]}`, false},
+		{`{"const": "x"}`, `"x"`, true},
+		{`{"const": "x"}`, `"y"`, false},
+		{`{"minimum": 1}`, `1`, true},
+		{`{"minimum": 1}`, `"0"`, true},
+		{`{"exclusiveMinimum": 1}`, `1`, false},
+		{`{"maximum": 1}`, `1`, true},
+		{`{"exclusiveMaximum": 1}`, `1`, false},
+		{`{"multipleOf": 0.5}`, `2.5`, true},
+		{`{"multipleOf": 0.5}`, `2.25`, false},
+		{`{"maxLength": 2}`, `"né"`, true},
+		{`{"pattern": "^[A-Z]{3}-\\d+$"}`, `"HVC-12"`, true},
+		{`{"pattern": "^[A-Z]{3}-\\d+$"}`, `"hvc-12"`, false},
+		{`{"format": "email"}`, `"ops@example.com"`, true},
+		{`{"format": "email"}`, `"Ops <ops@example.com>"`, false},
+		{`{"format": "uri"}`, `"/relative"`, false},
+		{`{"format": "date-time"}`, `"2026-03-10T09:00:00Z"`, true},
+		{`{"minItems": 1, "maxItems": 2}`, `[]`, false},
+		{`{"minProperties": 1}`, `{}`, false},
+		{`{"maxProperties": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"additionalProperties": {"type": "string"}}`, `{"a": "x"}`, true},
+		{`{"additionalProperties": {"type": "string"}}`, `{"a": 1}`, false},
+		{`{"allOf": [{"minimum": 1}, {"maximum": 3}]}`, `4`, false},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 3}]}`, `4`, true},
+		{`{"anyOf": [{"type": "string"}, {"minimum": 3}]}`, `2`, false},
+		{`{"oneOf": [{"minimum": 1}, {"maximum": 3}]}`, `2`, false},
+		{`{"oneOf": [{"minimum": 1}, {"maximum": 3}]}`, `5`, true},
+		{`{"not": {"type": "string"}}`, `"x"`, false},
+		{`false`, `{}`, false},
+		{`true`, `[1, 2]`, true},
+	}
+	for _, tc := range tests {
+		schema, err := Compile([]byte(tc.schema))
+		if err != nil {
+			t.Fatalf("Compile(%s): %v", tc.schema, err)
+		}
+		if err := schema.Validate([]byte(tc.document)); (err == nil) != tc.valid {
+			t.Errorf("%s against %s: got %v, want valid=%v", tc.document, tc.schema, err, tc.valid)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Error is a value that does not satisfy the schema
+type Error struct {
+	// Path locates the value in the document, e.g. "hvac.tonnage" or
+	// "tags[2]"; it is empty for the document itself
+	Path    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Errors lists every problem found in a document
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks a JSON document against the schema. It returns Errors
+// when the document does not satisfy the schema.
+func (s *Schema) Validate(data []byte) error {
+	v, err := decode(data)
+	if err != nil {
+		return Errors{{Message: "is not valid JSON"}}
+	}
+	if errs := s.validate(v, ""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (s *Schema) validate(v any, path string) Errors {
+	if s.always != nil {
+		if *s.always {
+			return nil
+		}
+		return Errors{{Path: path, Message: "is not allowed"}}
+	}
+
+	var errs Errors
+	fail := func(format string, args ...any) {
+		errs = append(errs, &Error{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !hasType(v, s.types) {
+		fail("must be %s", describeTypes(s.types))
+		return errs
+	}
+	if s.enum != nil && !contains(s.enum, v) {
+		fail("must be one of %s", listValues(s.enum))
+	}
+	if s.hasConst && !equal(s.constant, v) {
+		fail("must be %s", encodeValue(s.constant))
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		switch {
+		case s.minimum != nil && f < *s.minimum:
+			fail("must be at least %s", formatNumber(*s.minimum))
+		case s.exclusiveMinimum != nil && f <= *s.exclusiveMinimum:
+			fail("must be greater than %s", formatNumber(*s.exclusiveMinimum))
+		}
+		switch {
+		case s.maximum != nil && f > *s.maximum:
+			fail("must be at most %s", formatNumber(*s.maximum))
+		case s.exclusiveMaximum != nil && f >= *s.exclusiveMaximum:
+			fail("must be less than %s", formatNumber(*s.exclusiveMaximum))
+		}
+		if s.multipleOf != nil {
+			if q := f / *s.multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("must be a multiple of %s", formatNumber(*s.multipleOf))
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			fail("must be at least %d characters long", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("must be at most %d characters long", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("must match %s", s.pattern)
+		}
+		if message := checkFormat(s.format, v); message != "" {
+			fail("%s", message)
+		}
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.uniqueItems {
+			for i := 1; i < len(v); i++ {
+				if contains(v[:i], v[i]) {
+					fail("must not contain duplicate items")
+					break
+				}
+			}
+		}
+		if s.items != nil {
+			for i, item := range v {
+				errs = append(errs, s.items.validate(item, path+"["+strconv.Itoa(i)+"]")...)
+			}
+		}
+	case map[string]any:
+		if s.minProperties != nil && len(v) < *s.minProperties {
+			fail("must have at least %d properties", *s.minProperties)
+		}
+		if s.maxProperties != nil && len(v) > *s.maxProperties {
+			fail("must have at most %d properties", *s.maxProperties)
+		}
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, &Error{Path: join(path, name), Message: "is required"})
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			property, ok := s.properties[name]
+			if !ok {
+				property = s.additional
+			}
+			if property != nil {
+				errs = append(errs, property.validate(v[name], join(path, name))...)
+			}
+		}
+	}
+
+	for _, sub := range s.allOf {
+		errs = append(errs, sub.validate(v, path)...)
+	}
+	if s.anyOf != nil && matching(s.anyOf, v) == 0 {
+		fail("must match at least one of the allowed schemas")
+	}
+	if s.oneOf != nil {
+		if n := matching(s.oneOf, v); n != 1 {
+			fail("must match exactly one of the allowed schemas, matches %d", n)
+		}
+	}
+	if s.not != nil && len(s.not.validate(v, path)) == 0 {
+		fail("must not match the excluded schema")
+	}
+	return errs
+}
+
+// matching counts the schemas v satisfies
+func matching(schemas []*Schema, v any) int {
+	n := 0
+	for _, s := range schemas {
+		if len(s.validate(v, "")) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func hasType(v any, types []string) bool {
+	for _, t := range types {
+		switch v := v.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if t == "integer" {
+				f, err := v.Float64()
+				if err == nil && f == math.Trunc(f) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+var typeArticles = map[string]string{
+	"null": "null", "boolean": "a boolean", "object": "an object", "array": "an array",
+	"number": "a number", "integer": "an integer", "string": "a string",
+}
+
+func describeTypes(types []string) string {
+	described := make([]string, len(types))
+	for i, t := range types {
+		described[i] = typeArticles[t]
+	}
+	if len(described) == 1 {
+		return described[0]
+	}
+	return strings.Join(described[:len(described)-1], ", ") + " or " + described[len(described)-1]
+}
+
+// checkFormat returns why value does not have the format, or "" when it
+// does or the format is not one that is checked
+func checkFormat(format, value string) string {
+	switch format {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return "must be a date (YYYY-MM-DD)"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be a date and time (RFC 3339)"
+		}
+	case "email":
+		if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+			return "must be an email address"
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || !u.IsAbs() {
+			return "must be an absolute URI"
+		}
+	}
+	return ""
+}
+
+func contains(values []any, v any) bool {
+	for _, value := range values {
+		if equal(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares JSON values, numbers by value so 1 and 1.0 are equal
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if a == b {
+			return true
+		}
+		fa, errA := a.Float64()
+		fb, errB := b.Float64()
+		return errA == nil && errB == nil && fa == fb
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := b[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func listValues(values []any) string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = encodeValue(value)
+	}
+	return strings.Join(encoded, ", ")
+}
+
+func encodeValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// join appends a property name to a path
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
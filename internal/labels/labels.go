@@ -0,0 +1,105 @@
+// Package labels lays out printable asset labels, a QR code with a few lines
+// of text each, on A4 PDF sheets.
+package labels
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rixtrayker/ticketing-system/internal/qrcode"
+)
+
+// Label is one label on a sheet
+type Label struct {
+	Title string
+	Lines []string
+	Code  *qrcode.Code
+}
+
+// ErrNoLabels is returned for an empty sheet
+var ErrNoLabels = errors.New("labels: no labels to print")
+
+// Sheet geometry in points: A4 with two columns of seven labels
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 36.0
+	columns    = 2
+	rows       = 7
+	padding    = 8.0
+	titleSize  = 11.0
+	lineSize   = 9.0
+	lineHeight = 12.0
+
+	// PerPage is the number of labels on a sheet
+	PerPage = columns * rows
+)
+
+// Write renders the labels onto as many sheets as needed
+func Write(w io.Writer, labels []Label) error {
+	if len(labels) == 0 {
+		return ErrNoLabels
+	}
+	var doc document
+	for start := 0; start < len(labels); start += PerPage {
+		end := min(start+PerPage, len(labels))
+		var page canvas
+		for i, label := range labels[start:end] {
+			drawLabel(&page, i, label)
+		}
+		doc.addPage(page.bytes())
+	}
+	return doc.write(w)
+}
+
+// drawLabel draws a label in the given slot: a cut outline, the QR code on
+// the left and the text to its right
+func drawLabel(c *canvas, slot int, label Label) {
+	width := (pageWidth - 2*margin) / columns
+	height := (pageHeight - 2*margin) / rows
+	x := margin + float64(slot%columns)*width
+	y := margin + float64(slot/columns)*height
+
+	c.rect(x, y, width, height)
+	c.stroke()
+
+	side := height - 2*padding
+	module := side / float64(label.Code.Size()+2*qrcode.QuietZone)
+	origin := float64(qrcode.QuietZone) * module
+	label.Code.Runs(func(col, row, run int) {
+		c.rect(x+padding+origin+float64(col)*module, y+padding+origin+float64(row)*module, float64(run)*module, module)
+	})
+	c.fill()
+
+	textX := x + padding + side + padding
+	textWidth := x + width - padding - textX
+	baseline := y + padding + origin + titleSize
+	c.text(fontBold, titleSize, textX, baseline, fit(label.Title, titleSize, textWidth, boldWidth))
+	for _, line := range label.Lines {
+		baseline += lineHeight
+		c.text(fontRegular, lineSize, textX, baseline, fit(line, lineSize, textWidth, regularWidth))
+	}
+}
+
+// Average glyph widths of Helvetica and Helvetica-Bold, in ems, used to keep
+// text within a label without the fonts' full metrics. They sit a little
+// above the average of mixed-case text, so long runs of capitals may still
+// run past the label edge.
+const (
+	regularWidth = 0.55
+	boldWidth    = 0.6
+)
+
+// fit shortens s with an ellipsis until it fits in width points at the given
+// font size
+func fit(s string, size, width, em float64) string {
+	limit := int(width / (size * em))
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	if limit < 4 {
+		return string(runes[:max(limit, 0)])
+	}
+	return string(runes[:limit-3]) + "..."
+}
@@ -0,0 +1,168 @@
+package labels
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rixtrayker/ticketing-system/internal/qrcode"
+)
+
+func TestWriteNoLabels(t *testing.T) {
+	if err := Write(io.Discard, nil); !errors.Is(err, ErrNoLabels) {
+		t.Fatalf("got %v, want ErrNoLabels", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	code, err := qrcode.Encode("https://tickets.example.com/scan/7K3M9Q2X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := make([]Label, PerPage+1)
+	for i := range labels {
+		labels[i] = Label{Title: fmt.Sprintf("Chiller (%d)", i), Lines: []string{"7K3M9Q2X", "Plant room"}, Code: code}
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, labels); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing PDF header: %q", pdf[:min(len(pdf), 16)])
+	}
+	if !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("missing end of file marker")
+	}
+
+	// catalog, page tree, two fonts, then a page and a content stream per page
+	const objects = 4 + 2*2
+	offsets := checkXref(t, pdf, objects)
+	for i, offset := range offsets {
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, pdf[offset:min(offset+12, len(pdf))], want)
+		}
+	}
+	if !bytes.Contains(pdf, []byte("/Kids [5 0 R 7 0 R] /Count 2")) {
+		t.Error("page tree does not list two pages")
+	}
+
+	contents := streams(t, pdf)
+	if len(contents) != 2 {
+		t.Fatalf("%d content streams, want 2", len(contents))
+	}
+	if got := strings.Count(contents[0], " re\n"); got < PerPage*2 {
+		t.Errorf("first page draws %d rectangles, want outlines and codes for %d labels", got, PerPage)
+	}
+	if !strings.Contains(contents[0], `(Chiller \(0\)) Tj`) || !strings.Contains(contents[1], `(Chiller \(14\)) Tj`) {
+		t.Error("label titles missing or not escaped")
+	}
+	if strings.Contains(contents[0], `(Chiller \(14\))`) {
+		t.Error("the fifteenth label is on the first page")
+	}
+}
+
+// checkXref checks the cross-reference table and trailer and returns the
+// object offsets
+func checkXref(t *testing.T, pdf []byte, objects int) []int {
+	t.Helper()
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	start, _ := strconv.Atoi(string(match[1]))
+	table := string(pdf[start:])
+	header := fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", objects+1)
+	if !strings.HasPrefix(table, header) {
+		t.Fatalf("startxref %d points at %q, want %q", start, table[:min(len(table), len(header))], header)
+	}
+	if !strings.Contains(table, fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>", objects+1)) {
+		t.Error("trailer does not give the object count and catalog")
+	}
+
+	entries := strings.Split(strings.TrimPrefix(table, header), "\n")
+	offsets := make([]int, objects)
+	for i := range offsets {
+		if len(entries[i]) != 19 || !strings.HasSuffix(entries[i], " 00000 n ") {
+			t.Fatalf("xref entry %d is %q", i+1, entries[i])
+		}
+		offsets[i], _ = strconv.Atoi(entries[i][:10])
+	}
+	return offsets
+}
+
+// streams inflates the content streams, checking their declared lengths
+func streams(t *testing.T, pdf []byte) []string {
+	t.Helper()
+	var contents []string
+	header := regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	for _, loc := range header.FindAllSubmatchIndex(pdf, -1) {
+		length, _ := strconv.Atoi(string(pdf[loc[2]:loc[3]]))
+		body := pdf[loc[1]:]
+		if !bytes.HasPrefix(body[length:], []byte("\nendstream")) {
+			t.Fatalf("stream at %d is not %d bytes long", loc[0], length)
+		}
+		r, err := zlib.NewReader(bytes.NewReader(body[:length]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents = append(contents, string(content))
+	}
+	return contents
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Chiller 2", "Chiller 2"},
+		{`(a)\b`, `\(a\)\\b`},
+		{"Café", `Caf\351`},
+		{"€5", "?5"},
+		{"tab\there", "tab?here"},
+	}
+	for _, tc := range tests {
+		if got := escape(tc.in); got != tc.want {
+			t.Errorf("escape(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		in    string
+		width float64
+		want  string
+	}{
+		{"Chiller", 100, "Chiller"},
+		{"Rooftop chiller number two", 100, "Rooftop chiller n..."},
+		{"Chiller", 15, "Chi"},
+		{"Chiller", 0, ""},
+	}
+	for _, tc := range tests {
+		// 10pt at 0.5em is 5 points per character
+		if got := fit(tc.in, 10, tc.width, 0.5); got != tc.want {
+			t.Errorf("fit(%q, width %v) = %q, want %q", tc.in, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := map[float64]string{0: "0", 36: "36", 595.28: "595.28", 12.5: "12.5", 1.005: "1"}
+	for in, want := range tests {
+		if got := number(in); got != want {
+			t.Errorf("number(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
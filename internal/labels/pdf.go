@@ -0,0 +1,124 @@
+package labels
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// document is a minimal PDF 1.4 writer: pages of vector drawing and text in
+// the standard Helvetica fonts, which every viewer has built in
+type document struct {
+	pages [][]byte
+}
+
+// Font resource names used in page content streams
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+func (d *document) addPage(content []byte) {
+	d.pages = append(d.pages, content)
+}
+
+// write serializes the document. Objects are numbered catalog, page tree,
+// the two fonts, then a page and its content stream for every page.
+func (d *document) write(w io.Writer) error {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			number(pageWidth), number(pageHeight), fontRegular, fontBold, 6+2*i))
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		zw.Write(content)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// canvas builds a page content stream. Coordinates are in points with the
+// origin at the top left, as on the printed sheet; they are flipped into
+// PDF's bottom-left origin as they are written.
+type canvas struct {
+	buf bytes.Buffer
+}
+
+// rect adds a rectangle to the current path
+func (c *canvas) rect(x, y, width, height float64) {
+	fmt.Fprintf(&c.buf, "%s %s %s %s re\n", number(x), number(pageHeight-y-height), number(width), number(height))
+}
+
+// fill fills the current path in black
+func (c *canvas) fill() {
+	c.buf.WriteString("0 g f\n")
+}
+
+// stroke outlines the current path in a thin light grey line
+func (c *canvas) stroke() {
+	c.buf.WriteString("0.75 G 0.5 w S\n")
+}
+
+// text writes a line of text with its baseline at y
+func (c *canvas) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&c.buf, "BT /%s %s Tf 0 g %s %s Td (%s) Tj ET\n", font, number(size), number(x), number(pageHeight-y), escape(s))
+}
+
+func (c *canvas) bytes() []byte {
+	return c.buf.Bytes()
+}
+
+// number formats a coordinate with at most two decimals
+func number(f float64) string {
+	s := strings.TrimRight(fmt.Sprintf("%.2f", f), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// escape encodes s as the body of a PDF string in WinAnsiEncoding. Characters
+// outside Latin-1 are not in the standard fonts and become question marks.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,199 @@
+package mailin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/notify"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+	"gorm.io/gorm"
+)
+
+// maxTitleLength is the longest ticket title, matching the tickets table
+const maxTitleLength = 255
+
+// RejectedError reports an email the gateway will never accept, such as one
+// from an unknown sender. Other errors are temporary and the email should be
+// delivered again later.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "email rejected: " + e.Reason
+}
+
+// Gateway opens tickets from emails and threads replies onto them as
+// comments. Each email is processed once, identified by its Message-ID.
+type Gateway struct {
+	userRepo       repository.UserRepository
+	inboundRepo    repository.InboundEmailRepository
+	ticketService  service.TicketService
+	commentService service.CommentService
+	logger         *log.Logger
+}
+
+// NewGateway creates a Gateway acting through the ticket and comment services
+func NewGateway(userRepo repository.UserRepository, inboundRepo repository.InboundEmailRepository, ticketService service.TicketService, commentService service.CommentService, logger *log.Logger) *Gateway {
+	return &Gateway{
+		userRepo:       userRepo,
+		inboundRepo:    inboundRepo,
+		ticketService:  ticketService,
+		commentService: commentService,
+		logger:         logger,
+	}
+}
+
+// Deliver processes one RFC 5322 message
+func (g *Gateway) Deliver(ctx context.Context, r io.Reader) error {
+	email, err := Parse(r)
+	if err != nil {
+		return &RejectedError{Reason: err.Error()}
+	}
+	received, err := g.inboundRepo.Received(email.MessageID)
+	if err != nil || received {
+		return err
+	}
+
+	user, err := g.userRepo.GetByEmail(email.From)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &RejectedError{Reason: fmt.Sprintf("unknown sender %s", email.From)}
+		}
+		return err
+	}
+
+	ticketID, err := g.thread(email)
+	if err != nil {
+		return err
+	}
+	record := &models.InboundEmail{
+		MessageID:   email.MessageID,
+		FromAddress: email.From,
+		Subject:     email.Subject,
+		UserID:      user.ID,
+	}
+	if ticketID != nil {
+		comment, err := g.commentService.AddComment(user, &service.AddCommentInput{
+			TicketID: *ticketID,
+			Content:  replyText(email.Body),
+		})
+		if err != nil {
+			return rejectInvalid(err)
+		}
+		record.TicketID = *ticketID
+		record.CommentID = &comment.ID
+		g.logger.Printf("Mail: added comment to ticket %s from %s", *ticketID, email.From)
+	} else {
+		ticket, err := g.ticketService.CreateTicket(user, &service.CreateTicketInput{
+			Title:       ticketTitle(email.Subject),
+			Description: strings.TrimSpace(email.Body),
+			Priority:    models.TicketPriorityMedium,
+		})
+		if err != nil {
+			return rejectInvalid(err)
+		}
+		record.TicketID = ticket.ID
+		g.logger.Printf("Mail: opened ticket %s from %s", ticket.ID, email.From)
+	}
+
+	// The ticket or comment exists at this point; failing to log the email
+	// only risks processing it again if it is redelivered
+	if err := g.inboundRepo.Create(record); err != nil {
+		g.logger.Printf("Mail: failed to record email %s: %v", email.MessageID, err)
+	}
+	return nil
+}
+
+// thread returns the existing ticket an email replies to: the ticket named by
+// a token in the subject, or else the ticket of an email it references
+func (g *Gateway) thread(email *Email) (*uuid.UUID, error) {
+	if id, ok := notify.ParseTicketToken(email.Subject); ok {
+		if found, err := g.ticketExists(id); found || err != nil {
+			return &id, err
+		}
+	}
+	for _, ref := range email.References {
+		if id, ok := notify.ParseTicketMessageID(ref); ok {
+			if found, err := g.ticketExists(id); found || err != nil {
+				return &id, err
+			}
+		}
+	}
+	ticketID, err := g.inboundRepo.FindTicket(email.References)
+	if err != nil || ticketID == nil {
+		return nil, err
+	}
+	if found, err := g.ticketExists(*ticketID); !found || err != nil {
+		return nil, err
+	}
+	return ticketID, nil
+}
+
+func (g *Gateway) ticketExists(id uuid.UUID) (bool, error) {
+	if _, err := g.ticketService.GetTicket(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// rejectInvalid turns the service errors caused by the email itself into
+// rejections
+func rejectInvalid(err error) error {
+	var invalid *service.ValidationError
+	var notFound *service.NotFoundError
+	switch {
+	case errors.As(err, &invalid), errors.As(err, &notFound):
+		return &RejectedError{Reason: err.Error()}
+	case errors.Is(err, service.ErrForbidden):
+		return &RejectedError{Reason: "sender may not open tickets"}
+	}
+	return err
+}
+
+var (
+	replyPrefixPattern = regexp.MustCompile(`(?i)^\s*((re|fwd?|aw|wg|sv)(\[\d+\])?\s*:\s*)+`)
+	tokenPattern       = regexp.MustCompile(`\s*\[#T-[0-9a-fA-F-]{36}\]`)
+	quoteHeaderPattern = regexp.MustCompile(`(?i)^(on .+ wrote:|-+\s*original message\s*-+|_{10,})\s*$`)
+)
+
+// ticketTitle derives a ticket title from an email subject
+func ticketTitle(subject string) string {
+	title := tokenPattern.ReplaceAllString(subject, "")
+	title = strings.TrimSpace(replyPrefixPattern.ReplaceAllString(title, ""))
+	if title == "" {
+		return "(no subject)"
+	}
+	if runes := []rune(title); len(runes) > maxTitleLength {
+		title = string(runes[:maxTitleLength])
+	}
+	return title
+}
+
+// replyText returns the new content of a reply, dropping the quoted message
+// and the signature below it
+func replyText(body string) string {
+	var kept []string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimRight(line, " \t")
+		if quoteHeaderPattern.MatchString(strings.TrimSpace(trimmed)) || trimmed == "--" {
+			break
+		}
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		kept = append(kept, trimmed)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
@@ -0,0 +1,280 @@
+package mailin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+	"gorm.io/gorm"
+)
+
+// The fakes below implement only what the gateway uses; the embedded
+// interfaces are nil, so any other call panics.
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[string]*models.User
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (*models.User, error) {
+	user, ok := r.users[email]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+type fakeInboundRepo struct {
+	emails []*models.InboundEmail
+}
+
+func (r *fakeInboundRepo) Create(email *models.InboundEmail) error {
+	r.emails = append(r.emails, email)
+	return nil
+}
+
+func (r *fakeInboundRepo) Received(messageID string) (bool, error) {
+	for _, email := range r.emails {
+		if email.MessageID == messageID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeInboundRepo) FindTicket(messageIDs []string) (*uuid.UUID, error) {
+	for _, id := range messageIDs {
+		for _, email := range r.emails {
+			if email.MessageID == id {
+				return &email.TicketID, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+type fakeTicketService struct {
+	service.TicketService
+	tickets map[uuid.UUID]*models.Ticket
+	created []*service.CreateTicketInput
+}
+
+func (s *fakeTicketService) CreateTicket(actor *models.User, input *service.CreateTicketInput) (*models.Ticket, error) {
+	if input.Title == "" {
+		return nil, &service.ValidationError{Message: "title is required"}
+	}
+	ticket := &models.Ticket{Base: models.Base{ID: uuid.New()}, Title: input.Title, Description: input.Description, CreatedByID: actor.ID}
+	s.tickets[ticket.ID] = ticket
+	s.created = append(s.created, input)
+	return ticket, nil
+}
+
+func (s *fakeTicketService) GetTicket(id uuid.UUID) (*models.Ticket, error) {
+	ticket, ok := s.tickets[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return ticket, nil
+}
+
+type fakeCommentService struct {
+	service.CommentService
+	comments []*service.AddCommentInput
+}
+
+func (s *fakeCommentService) AddComment(actor *models.User, input *service.AddCommentInput) (*models.Comment, error) {
+	s.comments = append(s.comments, input)
+	return &models.Comment{Base: models.Base{ID: uuid.New()}, TicketID: input.TicketID, UserID: actor.ID, Content: input.Content}, nil
+}
+
+// chiller is the ticket the reply fixture is about
+var chiller = uuid.MustParse("0b7e8c1a-3f2d-4d6e-9a51-2c8f4e7d9b10")
+
+type gatewayFixture struct {
+	gateway  *Gateway
+	inbound  *fakeInboundRepo
+	tickets  *fakeTicketService
+	comments *fakeCommentService
+	dana     *models.User
+}
+
+func newGatewayFixture(tickets ...uuid.UUID) *gatewayFixture {
+	f := &gatewayFixture{
+		inbound:  &fakeInboundRepo{},
+		tickets:  &fakeTicketService{tickets: map[uuid.UUID]*models.Ticket{}},
+		comments: &fakeCommentService{},
+		dana:     &models.User{Base: models.Base{ID: uuid.New()}, Email: "dana@example.com", Role: models.UserRoleStaff},
+	}
+	for _, id := range tickets {
+		f.tickets.tickets[id] = &models.Ticket{Base: models.Base{ID: id}, Title: "Chiller 2 leaking"}
+	}
+	users := &fakeUserRepo{users: map[string]*models.User{f.dana.Email: f.dana}}
+	f.gateway = NewGateway(users, f.inbound, f.tickets, f.comments, log.New(io.Discard, "", 0))
+	return f
+}
+
+func (f *gatewayFixture) deliver(t *testing.T, fixture string) error {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	return f.gateway.Deliver(context.Background(), file)
+}
+
+func TestDeliverOpensTicket(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		title       string
+		description string
+	}{
+		{"alternative.eml", "Chiller 2 leaking in the plant röom", "There is water under Chiller 2 and the pressure gauge reads 3\u00a0bar.\nCould someone have a look before the afternoon shift?\n\nDana"},
+		{"quoted-printable.eml", "Lift 3 door sensor", "The door sensor on lift 3 keeps re-opening the doors on the second floor, so the lift is stuck for minutes at a time. The café staff noticed it first."},
+		{"no-subject.eml", "(no subject)", "The light in meeting room 4 flickers.\n--\nDana Whitfield\nFacilities"},
+		// a reply to emails the gateway never saw opens a ticket of its own
+		{"unknown-thread.eml", "Window latch", "The window latch in room 12 is broken too.\n\n> Is the latch in room 11 broken?"},
+	}
+	for _, tc := range tests {
+		f := newGatewayFixture()
+		if err := f.deliver(t, tc.fixture); err != nil {
+			t.Fatalf("%s: %v", tc.fixture, err)
+		}
+		if len(f.tickets.created) != 1 || len(f.comments.comments) != 0 {
+			t.Fatalf("%s: opened %d tickets and added %d comments, want one ticket", tc.fixture, len(f.tickets.created), len(f.comments.comments))
+		}
+		input := f.tickets.created[0]
+		if input.Title != tc.title || input.Description != tc.description || input.Priority != models.TicketPriorityMedium {
+			t.Errorf("%s: opened %+v, want title %q and description %q", tc.fixture, input, tc.title, tc.description)
+		}
+		if len(f.inbound.emails) != 1 || f.inbound.emails[0].UserID != f.dana.ID || f.inbound.emails[0].CommentID != nil {
+			t.Errorf("%s: recorded %+v, want the email recorded against the new ticket", tc.fixture, f.inbound.emails)
+		}
+	}
+}
+
+func TestDeliverThreadsReply(t *testing.T) {
+	f := newGatewayFixture(chiller)
+	if err := f.deliver(t, "reply.eml"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.tickets.created) != 0 || len(f.comments.comments) != 1 {
+		t.Fatalf("opened %d tickets and added %d comments, want one comment", len(f.tickets.created), len(f.comments.comments))
+	}
+	comment := f.comments.comments[0]
+	if comment.TicketID != chiller {
+		t.Errorf("commented on %s, want %s", comment.TicketID, chiller)
+	}
+	// the quoted notification is dropped
+	if want := "Thanks, the leak has stopped since the valve was closed."; comment.Content != want {
+		t.Errorf("comment %q, want %q", comment.Content, want)
+	}
+	if record := f.inbound.emails[0]; record.TicketID != chiller || record.CommentID == nil {
+		t.Errorf("recorded %+v, want the email recorded against the comment", record)
+	}
+}
+
+func TestDeliverThreadsOnReferences(t *testing.T) {
+	// the subject token names a ticket that no longer exists, so the reply
+	// is threaded on the notification it answers
+	f := newGatewayFixture(chiller)
+	raw := "From: dana@example.com\n" +
+		"Subject: Re: Chiller 2 leaking [#T-" + uuid.NewString() + "]\n" +
+		"Message-ID: <reply-3@mail.example.com>\n" +
+		"In-Reply-To: <ticket." + chiller.String() + ".7a2e@tickets.example.com>\n" +
+		"\n" +
+		"Still dry this morning.\n"
+	if err := f.gateway.Deliver(context.Background(), strings.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.tickets.created) != 0 || len(f.comments.comments) != 1 || f.comments.comments[0].TicketID != chiller {
+		t.Fatalf("opened %d tickets and added comments %+v, want one comment on %s", len(f.tickets.created), f.comments.comments, chiller)
+	}
+}
+
+func TestDeliverThreadsOnReceivedEmail(t *testing.T) {
+	f := newGatewayFixture()
+	if err := f.deliver(t, "alternative.eml"); err != nil {
+		t.Fatal(err)
+	}
+	opened := f.inbound.emails[0].TicketID
+
+	// the reply names a ticket that does not exist, in its subject and in
+	// the notification it replies to, but also references the email that
+	// opened a ticket
+	if err := f.deliver(t, "reply.eml"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.tickets.created) != 1 || len(f.comments.comments) != 1 || f.comments.comments[0].TicketID != opened {
+		t.Fatalf("opened %d tickets and added comments %+v, want one comment on %s", len(f.tickets.created), f.comments.comments, opened)
+	}
+}
+
+func TestDeliverOnce(t *testing.T) {
+	f := newGatewayFixture()
+	for i := 0; i < 2; i++ {
+		if err := f.deliver(t, "alternative.eml"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(f.tickets.created) != 1 || len(f.inbound.emails) != 1 {
+		t.Errorf("opened %d tickets and recorded %d emails, want the redelivered email ignored", len(f.tickets.created), len(f.inbound.emails))
+	}
+}
+
+func TestDeliverRejects(t *testing.T) {
+	f := newGatewayFixture()
+	var rejected *RejectedError
+	if err := f.deliver(t, "html.eml"); !errors.As(err, &rejected) {
+		t.Errorf("email from an unknown sender: got %v, want a RejectedError", err)
+	}
+	if err := f.gateway.Deliver(context.Background(), strings.NewReader("not an email")); !errors.As(err, &rejected) {
+		t.Errorf("unparseable email: got %v, want a RejectedError", err)
+	}
+	if len(f.tickets.created) != 0 || len(f.inbound.emails) != 0 {
+		t.Errorf("opened %d tickets and recorded %d emails for rejected emails", len(f.tickets.created), len(f.inbound.emails))
+	}
+}
+
+func TestTicketTitle(t *testing.T) {
+	tests := map[string]string{
+		"Chiller 2 leaking":                     "Chiller 2 leaking",
+		"Re: RE:Fwd: Chiller 2 leaking":         "Chiller 2 leaking",
+		"AW: WG: Kältemaschine":                 "Kältemaschine",
+		"Re[2]: Chiller 2 leaking":              "Chiller 2 leaking",
+		"Chiller [#T-" + chiller.String() + "]": "Chiller",
+		"Re: ":                                  "(no subject)",
+		"":                                      "(no subject)",
+	}
+	for subject, want := range tests {
+		if got := ticketTitle(subject); got != want {
+			t.Errorf("ticketTitle(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
+
+func TestReplyText(t *testing.T) {
+	tests := []struct {
+		body, want string
+	}{
+		{"Fixed, thanks.\n\n> Is it fixed?", "Fixed, thanks."},
+		{"Fixed.\n\n-----Original Message-----\nFrom: Helpdesk\n\nIs it fixed?", "Fixed."},
+		{"Fixed.\n--\nDana", "Fixed."},
+		{"Fixed.\n\nOn Tue, 10 Mar 2026, Helpdesk wrote:\nIs it fixed?", "Fixed."},
+		{"> Is it fixed?\nYes.", "Yes."},
+	}
+	for _, tc := range tests {
+		if got := replyText(tc.body); got != tc.want {
+			t.Errorf("replyText(%q) = %q, want %q", tc.body, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package mailin
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maildir feeds the emails arriving in a maildir to a Gateway. Processed
+// emails move from new/ to cur/, flagged seen when accepted and flagged for
+// attention when rejected. Emails that fail temporarily stay in new/ and are
+// retried on the next scan.
+type Maildir struct {
+	dir      string
+	gateway  *Gateway
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewMaildir creates a Maildir scanning dir every interval
+func NewMaildir(dir string, gateway *Gateway, interval time.Duration, logger *log.Logger) *Maildir {
+	return &Maildir{
+		dir:      dir,
+		gateway:  gateway,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run scans the maildir immediately and then on every tick until ctx is done
+func (m *Maildir) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.scan(ctx); err != nil {
+			m.logger.Printf("Mail: failed to scan maildir %s: %v", m.dir, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan delivers every email in new/, oldest first
+func (m *Maildir) scan(ctx context.Context) error {
+	entries, err := os.ReadDir(filepath.Join(m.dir, "new"))
+	if err != nil {
+		return err
+	}
+	// Maildir names start with the delivery time
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		m.process(ctx, entry.Name())
+	}
+	return nil
+}
+
+func (m *Maildir) process(ctx context.Context, name string) {
+	path := filepath.Join(m.dir, "new", name)
+	file, err := os.Open(path)
+	if err != nil {
+		m.logger.Printf("Mail: failed to open %s: %v", path, err)
+		return
+	}
+	err = m.gateway.Deliver(ctx, file)
+	file.Close()
+
+	flags := "S"
+	var rejected *RejectedError
+	switch {
+	case errors.As(err, &rejected):
+		m.logger.Printf("Mail: %s: %v", name, err)
+		flags = "F"
+	case err != nil:
+		m.logger.Printf("Mail: failed to process %s, will retry: %v", name, err)
+		return
+	}
+
+	base, _, _ := strings.Cut(name, ":")
+	if err := os.Rename(path, filepath.Join(m.dir, "cur", base+":2,"+flags)); err != nil {
+		m.logger.Printf("Mail: failed to move %s to cur: %v", name, err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package mailin turns emails into tickets. A new email opens a ticket on
+// behalf of the user it is from; a reply to an email about a ticket becomes a
+// comment on that ticket. Emails are read from a maildir or received by a
+// minimal SMTP listener.
+package mailin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMessageSize bounds the size of an email accepted by the gateway
+const maxMessageSize = 10 << 20
+
+// maxParts bounds the number of MIME parts searched for the body
+const maxParts = 100
+
+var errNoTextBody = errors.New("email has no text body")
+
+// Email is the part of an RFC 5322 message the gateway uses
+type Email struct {
+	MessageID string
+	From      string
+	Subject   string
+	// Body is the plain text content, decoded to UTF-8
+	Body string
+	// References holds the Message-IDs the email replies to, from the
+	// In-Reply-To and References headers, most relevant first
+	References []string
+}
+
+// Parse reads an RFC 5322 message. A message without a Message-ID is given
+// one derived from its content, so the same message is recognised when it is
+// delivered twice.
+func Parse(r io.Reader) (*Email, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxMessageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxMessageSize {
+		return nil, fmt.Errorf("email exceeds %d bytes", maxMessageSize)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) == 0 {
+		return nil, errors.New("email has no valid From address")
+	}
+	email := &Email{
+		MessageID:  firstMessageID(msg.Header.Get("Message-Id")),
+		From:       from[0].Address,
+		Subject:    decodeHeader(msg.Header.Get("Subject")),
+		References: messageIDs(msg.Header.Get("In-Reply-To")),
+	}
+	// References lists the thread oldest first; the closest ancestors are
+	// the most relevant
+	references := messageIDs(msg.Header.Get("References"))
+	for i := len(references) - 1; i >= 0; i-- {
+		email.References = append(email.References, references[i])
+	}
+	if email.MessageID == "" {
+		sum := sha256.Sum256(raw)
+		email.MessageID = "<" + hex.EncodeToString(sum[:]) + "@mailin.invalid>"
+	}
+
+	parts := 0
+	body, err := textBody(mail.Header(msg.Header), msg.Body, &parts)
+	if err != nil {
+		return nil, err
+	}
+	email.Body = normalizeNewlines(body)
+	return email, nil
+}
+
+var messageIDPattern = regexp.MustCompile(`<[^<>\s]+>`)
+
+// messageIDs extracts the angle-bracketed Message-IDs of a header
+func messageIDs(value string) []string {
+	return messageIDPattern.FindAllString(value, -1)
+}
+
+func firstMessageID(value string) string {
+	if ids := messageIDs(value); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// decodeHeader decodes RFC 2047 encoded words, keeping the raw value when
+// they cannot be decoded
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+// textBody returns the text of an entity, preferring a text/plain part of a
+// multipart message and falling back to text/html stripped of markup
+func textBody(header mail.Header, body io.Reader, parts *int) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var html string
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			if *parts++; *parts > maxParts {
+				return "", errors.New("email has too many parts")
+			}
+			if isAttachment(part.Header.Get("Content-Disposition")) {
+				continue
+			}
+			text, err := textBody(mail.Header(part.Header), part, parts)
+			switch {
+			case errors.Is(err, errNoTextBody):
+				continue
+			case err != nil:
+				return "", err
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if partType == "text/html" {
+				if html == "" {
+					html = text
+				}
+				continue
+			}
+			return text, nil
+		}
+		if html != "" {
+			return html, nil
+		}
+		return "", errNoTextBody
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", errNoTextBody
+	}
+	content, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return "", err
+	}
+	text := toUTF8(content, params["charset"])
+	if mediaType == "text/html" {
+		text = stripHTML(text)
+	}
+	return text, nil
+}
+
+func isAttachment(disposition string) bool {
+	kind, _, err := mime.ParseMediaType(disposition)
+	return err == nil && kind == "attachment"
+}
+
+// transferDecoder undoes a Content-Transfer-Encoding
+func transferDecoder(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineSkipper{r})
+	}
+	return r
+}
+
+// newlineSkipper drops the line breaks of base64 content
+type newlineSkipper struct {
+	r io.Reader
+}
+
+func (s newlineSkipper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// toUTF8 converts text in charset to UTF-8. Only UTF-8, US-ASCII and
+// ISO-8859-1 are understood; invalid sequences are replaced.
+func toUTF8(content []byte, charset string) string {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+	if utf8.Valid(content) {
+		return string(content)
+	}
+	return strings.ToValidUTF8(string(content), "�")
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>|</tr>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+	htmlDropPattern  = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	htmlEntities     = strings.NewReplacer("&nbsp;", " ", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'", "&amp;", "&")
+)
+
+// stripHTML reduces an HTML body to its text
+func stripHTML(html string) string {
+	text := htmlDropPattern.ReplaceAllString(html, "")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	return htmlEntities.Replace(text)
+}
+
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
@@ -0,0 +1,126 @@
+package mailin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func parseFixture(t *testing.T, name string) *Email {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	email, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return email
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		messageID  string
+		from       string
+		subject    string
+		body       string
+		references []string
+	}{
+		{
+			fixture:   "alternative.eml",
+			messageID: "<CAF1x9q@mail.example.com>",
+			from:      "dana@example.com",
+			subject:   "Chiller 2 leaking in the plant röom",
+			body:      "There is water under Chiller 2 and the pressure gauge reads 3\u00a0bar.\nCould someone have a look before the afternoon shift?\n\nDana",
+		},
+		{
+			fixture:   "html.eml",
+			messageID: "<alarm-77@example.com>",
+			from:      "ops@example.com",
+			subject:   "Boiler alarm",
+			body:      "Boiler 1 tripped at 06:40.\nCafé on level 2 & the lobby are cold.",
+		},
+		{
+			fixture:   "quoted-printable.eml",
+			messageID: "<qp-1@mail.example.com>",
+			from:      "dana@example.com",
+			subject:   "Lift 3 door sensor",
+			body:      "The door sensor on lift 3 keeps re-opening the doors on the second floor, so the lift is stuck for minutes at a time. The café staff noticed it first.",
+		},
+		{
+			fixture:   "reply.eml",
+			messageID: "<reply-2@mail.example.com>",
+			from:      "dana@example.com",
+			subject:   "RE: Re: Chiller 2 leaking [#T-0b7e8c1a-3f2d-4d6e-9a51-2c8f4e7d9b10]",
+			body:      "Thanks, the leak has stopped since the valve was closed.\n\nOn Tue, 10 Mar 2026 at 10:02, Helpdesk <helpdesk@tickets.example.com> wrote:\n> Sam Okafor assigned ticket \"Chiller 2 leaking\" to themselves.\n>\n> Reply to this email to comment on the ticket.",
+			references: []string{
+				"<ticket.0b7e8c1a-3f2d-4d6e-9a51-2c8f4e7d9b10.5d1c@tickets.example.com>",
+				"<ticket.0b7e8c1a-3f2d-4d6e-9a51-2c8f4e7d9b10.5d1c@tickets.example.com>",
+				"<CAF1x9q@mail.example.com>",
+			},
+		},
+		{
+			fixture:   "no-subject.eml",
+			messageID: "<nosubject-1@mail.example.com>",
+			from:      "dana@example.com",
+			body:      "The light in meeting room 4 flickers.\n--\nDana Whitfield\nFacilities",
+		},
+		{
+			fixture:    "unknown-thread.eml",
+			messageID:  "<unknown-1@mail.example.com>",
+			from:       "dana@example.com",
+			subject:    "Re: Window latch",
+			body:       "The window latch in room 12 is broken too.\n\n> Is the latch in room 11 broken?",
+			references: []string{"<lost-9@mail.example.com>", "<lost-9@mail.example.com>", "<lost-8@mail.example.com>"},
+		},
+	}
+	for _, tc := range tests {
+		email := parseFixture(t, tc.fixture)
+		if email.MessageID != tc.messageID || email.From != tc.from || email.Subject != tc.subject {
+			t.Errorf("%s: got %s from %q about %q, want %s from %q about %q", tc.fixture, email.MessageID, email.From, email.Subject, tc.messageID, tc.from, tc.subject)
+		}
+		if got := strings.TrimSpace(email.Body); got != tc.body {
+			t.Errorf("%s: body\n%q\nwant\n%q", tc.fixture, got, tc.body)
+		}
+		if strings.Join(email.References, " ") != strings.Join(tc.references, " ") {
+			t.Errorf("%s: references %v, want %v", tc.fixture, email.References, tc.references)
+		}
+	}
+}
+
+func TestParseWithoutMessageID(t *testing.T) {
+	const raw = "From: dana@example.com\r\nSubject: Leak\r\n\r\nWater on the floor.\r\n"
+	first, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(first.MessageID, "@mailin.invalid>") || first.MessageID != again.MessageID {
+		t.Errorf("Message-IDs %s and %s, want the same one derived from the content", first.MessageID, again.MessageID)
+	}
+	if first.Body != "Water on the floor.\n" {
+		t.Errorf("body %q, want CRLF line breaks normalized", first.Body)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := map[string]string{
+		"no From":          "Subject: Leak\n\nWater on the floor.\n",
+		"invalid From":     "From: dana at example\nSubject: Leak\n\nWater on the floor.\n",
+		"attachments only": "From: dana@example.com\nContent-Type: multipart/mixed; boundary=b\n\n--b\nContent-Type: image/jpeg\nContent-Disposition: attachment; filename=leak.jpg\n\nJPEG\n--b--\n",
+		"no text":          "From: dana@example.com\nContent-Type: image/jpeg\n\nJPEG\n",
+		"too large":        "From: dana@example.com\n\n" + strings.Repeat("x", maxMessageSize),
+	}
+	for name, raw := range tests {
+		if _, err := Parse(strings.NewReader(raw)); err == nil {
+			t.Errorf("%s: parsed, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package mailin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	// smtpTimeout bounds how long a client may stay idle
+	smtpTimeout = 5 * time.Minute
+	// maxRecipients bounds the recipients of one message
+	maxRecipients = 100
+)
+
+// SMTPServer is a minimal SMTP listener handing every received message to a
+// Gateway. It does not authenticate clients or relay mail, so it should only
+// be reachable by the organisation's mail server.
+type SMTPServer struct {
+	addr    string
+	domain  string
+	gateway *Gateway
+	logger  *log.Logger
+}
+
+// NewSMTPServer creates an SMTPServer listening on addr that greets clients
+// as domain
+func NewSMTPServer(addr, domain string, gateway *Gateway, logger *log.Logger) *SMTPServer {
+	return &SMTPServer{
+		addr:    addr,
+		domain:  domain,
+		gateway: gateway,
+		logger:  logger,
+	}
+}
+
+// ListenAndServe accepts connections until ctx is done
+func (s *SMTPServer) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+		go s.serve(ctx, conn)
+	}
+}
+
+// smtpSession is the state of one SMTP conversation
+type smtpSession struct {
+	conn       net.Conn
+	text       *textproto.Conn
+	from       string
+	recipients int
+}
+
+func (s *SMTPServer) serve(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	session := &smtpSession{conn: conn, text: textproto.NewConn(conn)}
+	session.reply(220, s.domain+" ESMTP ready")
+
+	for {
+		conn.SetDeadline(time.Now().Add(smtpTimeout))
+		line, err := session.text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			session.reset()
+			session.reply(250, s.domain)
+		case "EHLO":
+			session.reset()
+			session.reply(250, s.domain, fmt.Sprintf("SIZE %d", maxMessageSize), "8BITMIME")
+		case "MAIL":
+			if !strings.HasPrefix(strings.ToUpper(arg), "FROM:") {
+				session.reply(501, "syntax: MAIL FROM:<address>")
+				continue
+			}
+			session.reset()
+			session.from = strings.TrimSpace(arg[len("FROM:"):])
+			session.reply(250, "OK")
+		case "RCPT":
+			switch {
+			case session.from == "":
+				session.reply(503, "need MAIL before RCPT")
+			case !strings.HasPrefix(strings.ToUpper(arg), "TO:"):
+				session.reply(501, "syntax: RCPT TO:<address>")
+			case session.recipients >= maxRecipients:
+				session.reply(452, "too many recipients")
+			default:
+				session.recipients++
+				session.reply(250, "OK")
+			}
+		case "DATA":
+			if session.recipients == 0 {
+				session.reply(503, "need RCPT before DATA")
+				continue
+			}
+			session.reply(354, "end data with <CR><LF>.<CR><LF>")
+			if !s.receive(ctx, session) {
+				return
+			}
+			session.reset()
+		case "RSET":
+			session.reset()
+			session.reply(250, "OK")
+		case "NOOP":
+			session.reply(250, "OK")
+		case "VRFY":
+			session.reply(252, "cannot verify user")
+		case "QUIT":
+			session.reply(221, "bye")
+			return
+		default:
+			session.reply(502, "command not implemented")
+		}
+	}
+}
+
+// receive reads a message and replies with the outcome of delivering it. It
+// reports whether the connection can be used further.
+func (s *SMTPServer) receive(ctx context.Context, session *smtpSession) bool {
+	data := session.text.DotReader()
+	err := s.gateway.Deliver(ctx, data)
+	// Drain whatever the gateway did not read so the client sees a reply
+	// to the whole message
+	if _, drainErr := io.Copy(io.Discard, data); drainErr != nil {
+		return false
+	}
+
+	var rejected *RejectedError
+	switch {
+	case err == nil:
+		session.reply(250, "OK: message accepted")
+	case errors.As(err, &rejected):
+		s.logger.Printf("Mail: rejected message from %s: %v", session.from, err)
+		session.reply(550, rejected.Reason)
+	default:
+		s.logger.Printf("Mail: failed to process message from %s: %v", session.from, err)
+		session.reply(451, "temporary failure, try again later")
+	}
+	return true
+}
+
+func (session *smtpSession) reset() {
+	session.from = ""
+	session.recipients = 0
+}
+
+// reply writes a single or multiline reply
+func (session *smtpSession) reply(code int, lines ...string) {
+	w := bufio.NewWriter(session.conn)
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		fmt.Fprintf(w, "%d%s%s\r\n", code, separator, line)
+	}
+	w.Flush()
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AssetStatusChange records an asset moving from one status to another and
+// what caused it. The first change of an asset, with no FromStatus, records
+// the status it was created in.
+type AssetStatusChange struct {
+	Base
+	AssetID    uuid.UUID    `gorm:"type:uuid;not null"`
+	FromStatus *AssetStatus `gorm:"type:asset_status"`
+	ToStatus   AssetStatus  `gorm:"type:asset_status;not null"`
+	ChangedAt  time.Time    `gorm:"not null"`
+	// ChangedByID is the user who made the change, nil for the system
+	ChangedByID *uuid.UUID `gorm:"type:uuid"`
+
+	// Cause
+	TicketID            *uuid.UUID `gorm:"type:uuid"`
+	MaintenanceRecordID *uuid.UUID `gorm:"type:uuid"`
+	Reason              string     `gorm:"not null;default:''"`
+	// Automatic is set when ticket coupling rules made the change
+	Automatic bool `gorm:"not null;default:false"`
+
+	// Relations
+	ChangedBy         *User
+	Ticket            *Ticket
+	MaintenanceRecord *MaintenanceRecord
+}
+
+// IsDown reports whether an asset in this status counts as unavailable
+func (s AssetStatus) IsDown() bool {
+	return s == AssetStatusOutOfService
+}
+
+// AssetAvailability is how long an asset was available over a period.
+// Time before the asset existed or after it was decommissioned is not
+// tracked.
+type AssetAvailability struct {
+	From     time.Time
+	To       time.Time
+	Tracked  time.Duration
+	Downtime time.Duration
+}
+
+// Availability returns the percentage of tracked time the asset was up, or
+// 100 when no time was tracked
+func (a AssetAvailability) Availability() float64 {
+	if a.Tracked <= 0 {
+		return 100
+	}
+	return float64(a.Tracked-a.Downtime) / float64(a.Tracked) * 100
+}
+
+// DowntimePercentage returns the percentage of tracked time the asset was
+// down
+func (a AssetAvailability) DowntimePercentage() float64 {
+	return 100 - a.Availability()
+}
+
+// AvailabilityOver computes the availability over [from, to) from an asset's
+// status changes in order. changes must include the last change at or
+// before from, if any.
+func AvailabilityOver(changes []*AssetStatusChange, from, to time.Time) AssetAvailability {
+	result := AssetAvailability{From: from, To: to}
+	for i, change := range changes {
+		start := change.ChangedAt
+		end := to
+		if i+1 < len(changes) {
+			end = changes[i+1].ChangedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) || change.ToStatus == AssetStatusDecommissioned {
+			continue
+		}
+		result.Tracked += end.Sub(start)
+		if change.ToStatus.IsDown() {
+			result.Downtime += end.Sub(start)
+		}
+	}
+	return result
+}
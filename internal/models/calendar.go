@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/calendar"
+	"gorm.io/datatypes"
+)
+
+// BusinessCalendar sets the working hours and holidays of the sites listed
+// in Locations. SLA due dates and maintenance due dates of assets at those
+// locations only count working time. The default calendar applies to every
+// location without its own calendar; without one, time is counted 24/7.
+type BusinessCalendar struct {
+	Base
+	Name         string                             `gorm:"not null"`
+	TimeZone     string                             `gorm:"not null"`
+	WorkingHours datatypes.JSONSlice[WorkingPeriod] `gorm:"not null"`
+	Holidays     datatypes.JSONSlice[Holiday]       `gorm:"not null"`
+	Locations    datatypes.JSONSlice[string]        `gorm:"not null"`
+	IsDefault    bool                               `gorm:"not null;default:false"`
+}
+
+// WorkingPeriod is a span of working hours on a weekday, as HH:MM local
+// times. End may be 24:00.
+type WorkingPeriod struct {
+	Weekday Weekday `json:"weekday"`
+	Start   string  `json:"start"`
+	End     string  `json:"end"`
+}
+
+// Holiday is a non-working day, as YYYY-MM-DD
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type Weekday string
+
+const (
+	WeekdayMonday    Weekday = "MONDAY"
+	WeekdayTuesday   Weekday = "TUESDAY"
+	WeekdayWednesday Weekday = "WEDNESDAY"
+	WeekdayThursday  Weekday = "THURSDAY"
+	WeekdayFriday    Weekday = "FRIDAY"
+	WeekdaySaturday  Weekday = "SATURDAY"
+	WeekdaySunday    Weekday = "SUNDAY"
+)
+
+var weekdays = map[Weekday]time.Weekday{
+	WeekdayMonday:    time.Monday,
+	WeekdayTuesday:   time.Tuesday,
+	WeekdayWednesday: time.Wednesday,
+	WeekdayThursday:  time.Thursday,
+	WeekdayFriday:    time.Friday,
+	WeekdaySaturday:  time.Saturday,
+	WeekdaySunday:    time.Sunday,
+}
+
+// Calendar builds the calendar used to count working time, reporting an
+// error when the time zone, hours or holidays are invalid
+func (c *BusinessCalendar) Calendar() (*calendar.Calendar, error) {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil || c.TimeZone == "" {
+		return nil, fmt.Errorf("unknown time zone %q", c.TimeZone)
+	}
+	week := make(map[time.Weekday][]calendar.Period)
+	for _, p := range c.WorkingHours {
+		day, ok := weekdays[p.Weekday]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", p.Weekday)
+		}
+		start, err := parseClock(p.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(p.End)
+		if err != nil {
+			return nil, err
+		}
+		week[day] = append(week[day], calendar.Period{Start: start, End: end})
+	}
+	holidays := make([]calendar.Date, 0, len(c.Holidays))
+	for _, h := range c.Holidays {
+		date, err := calendar.ParseDate(h.Date)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, date)
+	}
+	return calendar.New(location, week, holidays)
+}
+
+// parseClock parses an HH:MM time of day into an offset from midnight
+func parseClock(s string) (time.Duration, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(s, "%d:%d", &hours, &minutes); err != nil || len(s) != 5 ||
+		hours < 0 || minutes < 0 || minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time of day %q: use HH:MM", s)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
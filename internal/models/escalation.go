@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationRule escalates tickets matching a condition, optionally narrowed
+// to a priority. A rule fires at most once per ticket and applies each of its
+// actions that is set.
+type EscalationRule struct {
+	Base
+	Name      string              `gorm:"not null"`
+	Condition EscalationCondition `gorm:"type:escalation_condition;not null"`
+	Priority  *TicketPriority     `gorm:"type:ticket_priority"`
+	// AfterMinutes is how long a ticket must have been unassigned or not
+	// updated; SLA conditions fire as soon as they hold
+	AfterMinutes int  `gorm:"not null;default:0"`
+	Active       bool `gorm:"not null;default:true"`
+
+	// Actions
+	BumpPriority bool
+	EscalateToID *uuid.UUID `gorm:"type:uuid"`
+	Comment      string     `gorm:"not null;default:''"`
+	Notify       bool
+
+	CreatedByID uuid.UUID `gorm:"type:uuid;not null"`
+
+	// Relations
+	EscalateTo *User
+	CreatedBy  User
+}
+
+// After is how long the condition must have held
+func (r EscalationRule) After() time.Duration {
+	return time.Duration(r.AfterMinutes) * time.Minute
+}
+
+// TicketEscalation records that a rule escalated a ticket
+type TicketEscalation struct {
+	Base
+	TicketID    uuid.UUID `gorm:"type:uuid;not null"`
+	RuleID      uuid.UUID `gorm:"type:uuid;not null"`
+	EscalatedAt time.Time `gorm:"not null"`
+}
+
+// EscalationCondition selects the tickets an escalation rule applies to
+type EscalationCondition string
+
+const (
+	// EscalationConditionUnassigned matches OPEN tickets nobody was assigned
+	// to within AfterMinutes of their creation
+	EscalationConditionUnassigned EscalationCondition = "UNASSIGNED"
+	// EscalationConditionNoUpdate matches OPEN or IN_PROGRESS tickets not
+	// updated for AfterMinutes
+	EscalationConditionNoUpdate EscalationCondition = "NO_UPDATE"
+	// EscalationConditionSLAAtRisk matches tickets at risk of breaching
+	// their SLA
+	EscalationConditionSLAAtRisk EscalationCondition = "SLA_AT_RISK"
+	// EscalationConditionSLABreached matches active tickets that breached
+	// their SLA
+	EscalationConditionSLABreached EscalationCondition = "SLA_BREACHED"
+)
+
+// IsTimed reports whether the condition waits AfterMinutes before matching
+func (c EscalationCondition) IsTimed() bool {
+	return c == EscalationConditionUnassigned || c == EscalationConditionNoUpdate
+}
+
+// Raised returns the next higher priority, or p when it is the highest
+func (p TicketPriority) Raised() TicketPriority {
+	switch p {
+	case TicketPriorityLow:
+		return TicketPriorityMedium
+	case TicketPriorityMedium:
+		return TicketPriorityHigh
+	default:
+		return TicketPriorityCritical
+	}
+}
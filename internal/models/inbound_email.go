@@ -0,0 +1,21 @@
+package models
+
+import "github.com/google/uuid"
+
+// InboundEmail records an email received by the inbound mail gateway and the
+// ticket it opened, or the comment it added to one
+type InboundEmail struct {
+	Base
+	MessageID   string    `gorm:"not null;unique"`
+	FromAddress string    `gorm:"not null"`
+	Subject     string    `gorm:"not null"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null"`
+	TicketID    uuid.UUID `gorm:"type:uuid;not null"`
+	// CommentID is set when the email was a reply to an existing ticket
+	CommentID *uuid.UUID `gorm:"type:uuid"`
+
+	// Relations
+	User    User
+	Ticket  Ticket
+	Comment *Comment
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Value implements driver.Valuer so JSONB is stored as a jsonb column
+func (j JSONB) Value() (driver.Value, error) {
+	return datatypes.JSON(j).Value()
+}
+
+// Scan implements sql.Scanner
+func (j *JSONB) Scan(value interface{}) error {
+	return (*datatypes.JSON)(j).Scan(value)
+}
+
+// GormDataType returns the generic GORM data type
+func (JSONB) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType returns the database specific column type
+func (JSONB) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	return datatypes.JSON{}.GormDBDataType(db, field)
+}
+
+// MarshalJSON returns the raw JSON document
+func (j JSONB) MarshalJSON() ([]byte, error) {
+	return datatypes.JSON(j).MarshalJSON()
+}
+
+// UnmarshalJSON stores a copy of the raw JSON document
+func (j *JSONB) UnmarshalJSON(b []byte) error {
+	return (*datatypes.JSON)(j).UnmarshalJSON(b)
+}
+
+// MarshalGQL implements graphql.Marshaler for the JSON scalar
+func (j JSONB) MarshalGQL(w io.Writer) {
+	if len(j) == 0 {
+		_, _ = io.WriteString(w, "null")
+		return
+	}
+	_, _ = w.Write(j)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler for the JSON scalar
+func (j *JSONB) UnmarshalGQL(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	*j = JSONB(b)
+	return nil
+}
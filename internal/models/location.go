@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Location is a place in the site → building → floor → room hierarchy that
+// assets are installed at
+type Location struct {
+	Base
+	Name     string       `gorm:"not null"`
+	Kind     LocationKind `gorm:"type:location_kind;not null"`
+	ParentID *uuid.UUID   `gorm:"type:uuid"`
+
+	// Relations
+	Parent *Location
+}
+
+// LocationPath joins the names of a location's ancestors, root first, and
+// its own name
+func LocationPath(ancestors []*Location) string {
+	names := make([]string, len(ancestors))
+	for i, location := range ancestors {
+		names[i] = location.Name
+	}
+	return strings.Join(names, " / ")
+}
+
+// LocationKind is the level of a location in the hierarchy
+type LocationKind string
+
+const (
+	LocationKindSite     LocationKind = "SITE"
+	LocationKindBuilding LocationKind = "BUILDING"
+	LocationKindFloor    LocationKind = "FLOOR"
+	LocationKindRoom     LocationKind = "ROOM"
+)
+
+// ParentKind returns the kind a location of this kind must be placed in, or
+// false for sites, which are the roots of the hierarchy
+func (k LocationKind) ParentKind() (LocationKind, bool) {
+	switch k {
+	case LocationKindBuilding:
+		return LocationKindSite, true
+	case LocationKindFloor:
+		return LocationKindBuilding, true
+	case LocationKindRoom:
+		return LocationKindFloor, true
+	}
+	return "", false
+}
@@ -0,0 +1,36 @@
+package models
+
+// AssetTypeSchema is the JSON Schema the metadata of every asset of a type
+// must satisfy
+type AssetTypeSchema struct {
+	Base
+	Type   AssetType `gorm:"type:asset_type;not null"`
+	Schema JSONB     `gorm:"not null"`
+}
+
+// MetadataOperator compares the metadata value at a path with a filter value
+type MetadataOperator string
+
+const (
+	// MetadataOperatorEquals matches a scalar value, or any element of an
+	// array holding it
+	MetadataOperatorEquals MetadataOperator = "EQUALS"
+	// MetadataOperatorContains matches a value containing the filter value,
+	// as the JSONB @> operator does
+	MetadataOperatorContains MetadataOperator = "CONTAINS"
+	// MetadataOperatorExists matches any value at the path
+	MetadataOperatorExists MetadataOperator = "EXISTS"
+	MetadataOperatorGT     MetadataOperator = "GT"
+	MetadataOperatorGTE    MetadataOperator = "GTE"
+	MetadataOperatorLT     MetadataOperator = "LT"
+	MetadataOperatorLTE    MetadataOperator = "LTE"
+)
+
+// MetadataFilter matches assets by the value at a path in their metadata
+type MetadataFilter struct {
+	// Path is the sequence of object keys leading to the value
+	Path     []string
+	Operator MetadataOperator
+	// Value is the JSON value compared with, unset for EXISTS
+	Value JSONB
+}
@@ -31,11 +31,22 @@ type Ticket struct {
 	AssetID     *uuid.UUID  `gorm:"type:uuid"`
 	ResolvedAt  *time.Time
 
+	// SLA tracking; due dates are nil when no SLAPolicy applies
+	SLAPolicyID     *uuid.UUID `gorm:"type:uuid"`
+	ResponseDueAt   *time.Time
+	ResolutionDueAt *time.Time
+	RespondedAt     *time.Time
+	// SLAPausedAt is set while the ticket waits on its requester
+	SLAPausedAt *time.Time
+	// SLAPausedSeconds is the time spent waiting on the requester so far
+	SLAPausedSeconds int `gorm:"not null;default:0"`
+
 	// Relations
 	AssignedTo *User
 	CreatedBy  User
 	Asset      *Asset
 	Comments   []Comment
+	SLAPolicy  *SLAPolicy
 }
 
 // Asset represents a physical item that needs maintenance
@@ -50,24 +61,54 @@ type Asset struct {
 	LastMaintenanceDate *time.Time
 	NextMaintenanceDate *time.Time
 	Metadata          JSONB
+	// LocationID places the asset in the location hierarchy; Location
+	// remains the free-text label calendars are matched on
+	LocationID *uuid.UUID `gorm:"type:uuid"`
+	// ParentID is the asset this one is a component of
+	ParentID *uuid.UUID `gorm:"type:uuid"`
 
 	// Relations
 	MaintenanceHistory []MaintenanceRecord
 	Tickets           []Ticket
+	Place             *Location `gorm:"foreignKey:LocationID"`
+	Parent            *Asset
 }
 
 // User represents a system user
 type User struct {
 	Base
-	Email string     `gorm:"not null;unique"`
-	Name  string     `gorm:"not null"`
-	Role  UserRole   `gorm:"type:user_role;not null"`
+	Email        string   `gorm:"not null;unique"`
+	Name         string   `gorm:"not null"`
+	Role         UserRole `gorm:"type:user_role;not null"`
+	PasswordHash string   `gorm:"not null;default:''" json:"-"`
+
+	// NotificationPreferences chooses which emails the user receives
+	NotificationPreferences NotificationPreferences `gorm:"embedded;embeddedPrefix:notify_"`
 
 	// Relations
 	AssignedTickets []Ticket
 	CreatedTickets  []Ticket
 }
 
+// NotificationPreferences selects the email notifications a user receives
+type NotificationPreferences struct {
+	OnAssignment         bool `gorm:"not null"`
+	OnResolution         bool `gorm:"not null"`
+	OnMaintenanceOverdue bool `gorm:"not null"`
+	OnEscalation         bool `gorm:"not null"`
+}
+
+// DefaultNotificationPreferences returns the preferences of new users: every
+// notification enabled
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{
+		OnAssignment:         true,
+		OnResolution:         true,
+		OnMaintenanceOverdue: true,
+		OnEscalation:         true,
+	}
+}
+
 // MaintenanceSchedule represents a planned maintenance activity
 type MaintenanceSchedule struct {
 	Base
@@ -78,10 +119,12 @@ type MaintenanceSchedule struct {
 	AssignedToID uuid.UUID           `gorm:"type:uuid;not null"`
 	Status       MaintenanceStatus   `gorm:"type:maintenance_status;not null"`
 	Notes        string
+	TicketID     *uuid.UUID          `gorm:"type:uuid"`
 
 	// Relations
 	Asset      Asset
 	AssignedTo User
+	Ticket     *Ticket
 }
 
 // MaintenanceRecord represents a completed maintenance activity
@@ -120,6 +163,31 @@ type Part struct {
 	MinimumQuantity int      `gorm:"not null"`
 	Location       string    `gorm:"not null"`
 	LastRestocked  time.Time `gorm:"not null"`
+	ReorderTicketID *uuid.UUID `gorm:"type:uuid"`
+
+	// Relations
+	ReorderTicket *Ticket
+}
+
+// BelowMinimum reports whether the part's stock is under its minimum quantity
+func (p Part) BelowMinimum() bool {
+	return p.Quantity < p.MinimumQuantity
+}
+
+// StockMovement records a change to the stock of a part
+type StockMovement struct {
+	Base
+	PartID              uuid.UUID           `gorm:"type:uuid;not null;index"`
+	ActorID             *uuid.UUID          `gorm:"type:uuid"`
+	Reason              StockMovementReason `gorm:"type:stock_movement_reason;not null"`
+	Change              int                 `gorm:"not null"`
+	QuantityAfter       int                 `gorm:"not null"`
+	MaintenanceRecordID *uuid.UUID          `gorm:"type:uuid"`
+	Note                string
+
+	// Relations
+	Part  Part
+	Actor *User
 }
 
 // Comment represents a comment on a ticket
@@ -128,12 +196,61 @@ type Comment struct {
 	TicketID uuid.UUID `gorm:"type:uuid;not null"`
 	UserID   uuid.UUID `gorm:"type:uuid;not null"`
 	Content  string    `gorm:"not null"`
+	Internal bool      `gorm:"not null;default:false"`
+	// System comments are added by escalation rules on behalf of the user
+	// who configured the rule
+	System   bool      `gorm:"not null;default:false"`
+	Edited   bool      `gorm:"not null;default:false"`
+	EditedAt *time.Time
+
+	// Relations
+	Ticket    Ticket
+	User      User
+	Mentions  []User `gorm:"many2many:comment_mentions"`
+	Revisions []CommentRevision
+}
+
+// CommentRevision keeps the previous content of an edited comment
+type CommentRevision struct {
+	Base
+	CommentID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	EditedByID uuid.UUID `gorm:"type:uuid;not null"`
+	Content    string    `gorm:"not null"`
+
+	// Relations
+	EditedBy User
+}
+
+// TicketEvent records a change to a single field of a ticket
+type TicketEvent struct {
+	Base
+	TicketID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ActorID  *uuid.UUID `gorm:"type:uuid"`
+	Field    string     `gorm:"not null"`
+	OldValue *string
+	NewValue *string
 
 	// Relations
 	Ticket Ticket
-	User   User
+	Actor  *User
+}
+
+// TimelineEntry is an item of a ticket timeline: a TicketEvent or a Comment
+type TimelineEntry interface {
+	IsTimelineEntry()
+	OccurredAt() time.Time
 }
 
+func (TicketEvent) IsTimelineEntry() {}
+
+// OccurredAt returns when the change was made
+func (e TicketEvent) OccurredAt() time.Time { return e.CreatedAt }
+
+func (Comment) IsTimelineEntry() {}
+
+// OccurredAt returns when the comment was posted
+func (c Comment) OccurredAt() time.Time { return c.CreatedAt }
+
 // Enums
 type TicketStatus string
 type TicketPriority string
@@ -143,11 +260,13 @@ type UserRole string
 type MaintenanceFrequency string
 type MaintenanceStatus string
 type MaintenanceType string
+type StockMovementReason string
 
 const (
 	// TicketStatus
 	TicketStatusOpen        TicketStatus = "OPEN"
 	TicketStatusInProgress  TicketStatus = "IN_PROGRESS"
+	TicketStatusWaiting     TicketStatus = "WAITING"
 	TicketStatusResolved    TicketStatus = "RESOLVED"
 	TicketStatusClosed      TicketStatus = "CLOSED"
 	TicketStatusCancelled   TicketStatus = "CANCELLED"
@@ -198,6 +317,12 @@ const (
 	MaintenanceTypeCorrective     MaintenanceType = "CORRECTIVE"
 	MaintenanceTypePredictive     MaintenanceType = "PREDICTIVE"
 	MaintenanceTypeConditionBased MaintenanceType = "CONDITION_BASED"
+
+	// StockMovementReason
+	StockMovementReasonInitial     StockMovementReason = "INITIAL"
+	StockMovementReasonRestock     StockMovementReason = "RESTOCK"
+	StockMovementReasonAdjustment  StockMovementReason = "ADJUSTMENT"
+	StockMovementReasonConsumption StockMovementReason = "CONSUMPTION"
 )
 
 // Filter types for repositories
@@ -207,20 +332,59 @@ type TicketFilter struct {
 	AssignedToID *uuid.UUID
 	CreatedByID  *uuid.UUID
 	AssetID      *uuid.UUID
+	// PlaceID matches tickets on assets anywhere under the location
+	PlaceID      *uuid.UUID
+	SLA          *SLAState
+	// SLAAsOf is the time the SLA filter is evaluated at
+	SLAAsOf time.Time
+	// Metadata matches tickets on assets whose metadata satisfies every filter
+	Metadata []MetadataFilter
 }
 
 type AssetFilter struct {
 	Type     *AssetType
 	Status   *AssetStatus
 	Location *string
+	// PlaceID matches assets anywhere under the location
+	PlaceID  *uuid.UUID
+	// ParentID matches the components of an asset, at any depth
+	ParentID *uuid.UUID
+	// Metadata matches assets whose metadata satisfies every filter
+	Metadata []MetadataFilter
 }
 
 type UserFilter struct {
-	Role *UserRole
+	Role  *UserRole
+	Email *string
 }
 
 type MaintenanceScheduleFilter struct {
 	AssetID      *uuid.UUID
 	AssignedToID *uuid.UUID
 	Status       *MaintenanceStatus
-} 
\ No newline at end of file
+}
+
+type PartFilter struct {
+	Location     *string
+	BelowMinimum *bool
+}
+
+// Next returns the time the following occurrence is due after from
+func (f MaintenanceFrequency) Next(from time.Time) time.Time {
+	switch f {
+	case MaintenanceFrequencyDaily:
+		return from.AddDate(0, 0, 1)
+	case MaintenanceFrequencyWeekly:
+		return from.AddDate(0, 0, 7)
+	case MaintenanceFrequencyMonthly:
+		return from.AddDate(0, 1, 0)
+	case MaintenanceFrequencyQuarterly:
+		return from.AddDate(0, 3, 0)
+	case MaintenanceFrequencyBiannual:
+		return from.AddDate(0, 6, 0)
+	case MaintenanceFrequencyAnnual:
+		return from.AddDate(1, 0, 0)
+	default:
+		return from
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification records an email sent to a user about an event
+type Notification struct {
+	Base
+	EventID uuid.UUID `gorm:"type:uuid;not null"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null"`
+	// Kind names the template the email was rendered from
+	Kind    string    `gorm:"not null"`
+	Email   string    `gorm:"not null"`
+	Subject string    `gorm:"not null"`
+	SentAt  time.Time `gorm:"not null"`
+
+	// Relations
+	User User
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OutboxEvent is a domain event waiting to be dispatched. It is written in
+// the same transaction as the change it describes, so it exists exactly when
+// that change committed.
+type OutboxEvent struct {
+	Base
+	Type          string         `gorm:"not null"`
+	Payload       datatypes.JSON `gorm:"type:jsonb;not null"`
+	Attempts      int            `gorm:"not null;default:0"`
+	NextAttemptAt time.Time      `gorm:"not null;index"`
+	SentAt        *time.Time
+	LastError     string
+	// DeliveredTo names the handlers that already handled the event
+	DeliveredTo datatypes.JSONSlice[string] `gorm:"type:jsonb;not null;default:'[]'"`
+	// DeadAt is when dispatch gave up on the event
+	DeadAt *time.Time
+}
@@ -0,0 +1,48 @@
+package models
+
+// SortField is a column list queries can be ordered by
+type SortField string
+
+// SortDirection is the direction of an ordering
+type SortDirection string
+
+const (
+	// SortField
+	SortFieldCreatedAt SortField = "CREATED_AT"
+	SortFieldUpdatedAt SortField = "UPDATED_AT"
+	SortFieldPriority  SortField = "PRIORITY"
+	SortFieldNextDue   SortField = "NEXT_DUE"
+
+	// SortDirection
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+// OrderBy selects the ordering of a list
+type OrderBy struct {
+	Field     SortField
+	Direction *SortDirection
+}
+
+// PageArgs selects a page of a list using an opaque cursor
+type PageArgs struct {
+	First   *int
+	After   *string
+	OrderBy *OrderBy
+}
+
+// PageInfo describes the position of a page within the full list
+type PageInfo struct {
+	HasNextPage     bool
+	HasPreviousPage bool
+	StartCursor     *string
+	EndCursor       *string
+}
+
+// Page is one page of a list with the cursor of each item
+type Page[T any] struct {
+	Items      []T
+	Cursors    []string
+	PageInfo   PageInfo
+	TotalCount int64
+}
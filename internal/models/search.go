@@ -0,0 +1,35 @@
+package models
+
+// SearchableType is a kind of record covered by full-text search
+type SearchableType string
+
+const (
+	SearchableTypeTicket  SearchableType = "TICKET"
+	SearchableTypeComment SearchableType = "COMMENT"
+	SearchableTypeAsset   SearchableType = "ASSET"
+)
+
+// SearchResult is a record matched by full-text search: a Ticket, Comment or Asset
+type SearchResult interface {
+	IsSearchResult()
+}
+
+func (Ticket) IsSearchResult()  {}
+func (Comment) IsSearchResult() {}
+func (Asset) IsSearchResult()   {}
+
+// SearchHit is a ranked search match with the matching text highlighted
+type SearchHit struct {
+	Type      SearchableType
+	Rank      float64
+	Highlight string
+	Item      SearchResult
+}
+
+// SearchQuery selects what a full-text search looks for
+type SearchQuery struct {
+	Query           string
+	Types           []SearchableType
+	IncludeInternal bool
+	Limit           int
+}
@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// SLAAtRiskFraction is the share of an SLA target left when a ticket becomes
+// at risk of breaching it
+const SLAAtRiskFraction = 0.25
+
+// SLAPolicy sets how soon tickets of a priority must be responded to and
+// resolved. A policy for the ticket's asset type takes precedence over the
+// policy for every asset type, which has no AssetType.
+type SLAPolicy struct {
+	Base
+	Name              string         `gorm:"not null"`
+	Priority          TicketPriority `gorm:"type:ticket_priority;not null"`
+	AssetType         *AssetType     `gorm:"type:asset_type"`
+	ResponseMinutes   int            `gorm:"not null"`
+	ResolutionMinutes int            `gorm:"not null"`
+}
+
+// ResponseTarget is the time allowed until the first response
+func (p SLAPolicy) ResponseTarget() time.Duration {
+	return time.Duration(p.ResponseMinutes) * time.Minute
+}
+
+// ResolutionTarget is the time allowed until resolution
+func (p SLAPolicy) ResolutionTarget() time.Duration {
+	return time.Duration(p.ResolutionMinutes) * time.Minute
+}
+
+// SLAState selects tickets by how they stand against their SLA
+type SLAState string
+
+const (
+	SLAStateAtRisk   SLAState = "AT_RISK"
+	SLAStateBreached SLAState = "BREACHED"
+)
+
+// SLAPaused reports whether the ticket's SLA clock is stopped
+func (t *Ticket) SLAPaused() bool {
+	return t.SLAPausedAt != nil
+}
+
+// slaNow is the time SLA targets are measured at: the SLA clock stops while
+// the ticket is paused
+func (t *Ticket) slaNow(now time.Time) time.Time {
+	if t.SLAPausedAt != nil {
+		return *t.SLAPausedAt
+	}
+	return now
+}
+
+// ResponseBreached reports whether the first response came, or is still
+// missing, after it was due
+func (t *Ticket) ResponseBreached(now time.Time) bool {
+	return missed(t.ResponseDueAt, t.RespondedAt, t.slaNow(now))
+}
+
+// ResolutionBreached reports whether the ticket was, or still is not,
+// resolved after it was due. Cancelled tickets no longer have a target.
+func (t *Ticket) ResolutionBreached(now time.Time) bool {
+	if t.Status == TicketStatusCancelled {
+		return false
+	}
+	return missed(t.ResolutionDueAt, t.ResolvedAt, t.slaNow(now))
+}
+
+// SLABreached reports whether either SLA target was missed
+func (t *Ticket) SLABreached(now time.Time) bool {
+	return t.ResponseBreached(now) || t.ResolutionBreached(now)
+}
+
+// SLAAtRisk reports whether a pending target has less than SLAAtRiskFraction
+// of its time left, without being breached yet. It needs SLAPolicy loaded.
+func (t *Ticket) SLAAtRisk(now time.Time) bool {
+	if t.SLAPolicy == nil || t.SLAPaused() || !t.Status.IsActive() || t.SLABreached(now) {
+		return false
+	}
+	return (t.RespondedAt == nil && dueWithin(t.ResponseDueAt, t.SLAPolicy.ResponseTarget(), now)) ||
+		(t.ResolvedAt == nil && dueWithin(t.ResolutionDueAt, t.SLAPolicy.ResolutionTarget(), now))
+}
+
+// missed reports whether something due was done late, or is not done by now
+func missed(due, done *time.Time, now time.Time) bool {
+	if due == nil {
+		return false
+	}
+	if done != nil {
+		return done.After(*due)
+	}
+	return now.After(*due)
+}
+
+// dueWithin reports whether less than SLAAtRiskFraction of target is left
+// until due
+func dueWithin(due *time.Time, target time.Duration, now time.Time) bool {
+	if due == nil {
+		return false
+	}
+	margin := time.Duration(float64(target) * SLAAtRiskFraction)
+	return !now.Before(due.Add(-margin))
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTicketSLA(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		when := created.Add(time.Duration(minutes) * time.Minute)
+		return &when
+	}
+	// an hour to respond and four to resolve, so a ticket is at risk in
+	// the last 15 minutes before its response is due and the last hour
+	// before its resolution is due
+	policy := &SLAPolicy{ResponseMinutes: 60, ResolutionMinutes: 240}
+	tests := []struct {
+		name   string
+		ticket Ticket
+		// now is in minutes after the ticket was created
+		now                          int
+		response, resolution, atRisk bool
+	}{
+		{name: "fresh", ticket: Ticket{Status: TicketStatusOpen}, now: 30},
+		{name: "response at risk", ticket: Ticket{Status: TicketStatusOpen}, now: 45, atRisk: true},
+		{name: "response due now", ticket: Ticket{Status: TicketStatusOpen}, now: 60, atRisk: true},
+		{name: "response breached", ticket: Ticket{Status: TicketStatusOpen}, now: 61, response: true},
+		{name: "responded in time", ticket: Ticket{Status: TicketStatusInProgress, RespondedAt: at(50)}, now: 90},
+		{name: "responded late", ticket: Ticket{Status: TicketStatusInProgress, RespondedAt: at(70)}, now: 90, response: true},
+		{name: "resolution at risk", ticket: Ticket{Status: TicketStatusInProgress, RespondedAt: at(10)}, now: 180, atRisk: true},
+		{name: "resolution breached", ticket: Ticket{Status: TicketStatusInProgress, RespondedAt: at(10)}, now: 241, resolution: true},
+		{name: "resolved in time", ticket: Ticket{Status: TicketStatusResolved, RespondedAt: at(10), ResolvedAt: at(200)}, now: 300},
+		{name: "resolved late", ticket: Ticket{Status: TicketStatusClosed, RespondedAt: at(10), ResolvedAt: at(250)}, now: 300, resolution: true},
+		{name: "resolved near the due date", ticket: Ticket{Status: TicketStatusResolved, RespondedAt: at(10), ResolvedAt: at(230)}, now: 235},
+		{name: "cancelled after the due date", ticket: Ticket{Status: TicketStatusCancelled, RespondedAt: at(10)}, now: 300},
+		{name: "cancelled without a response", ticket: Ticket{Status: TicketStatusCancelled}, now: 300, response: true},
+		{name: "paused before the response was due", ticket: Ticket{Status: TicketStatusWaiting, SLAPausedAt: at(50)}, now: 120},
+		{name: "paused after the response was due", ticket: Ticket{Status: TicketStatusWaiting, SLAPausedAt: at(70)}, now: 120, response: true},
+		{name: "paused near the resolution due date", ticket: Ticket{Status: TicketStatusWaiting, RespondedAt: at(10), SLAPausedAt: at(230)}, now: 600},
+	}
+	for _, tc := range tests {
+		ticket := tc.ticket
+		ticket.CreatedAt = created
+		ticket.ResponseDueAt, ticket.ResolutionDueAt = at(60), at(240)
+		ticket.SLAPolicy = policy
+		now := *at(tc.now)
+		if got := ticket.ResponseBreached(now); got != tc.response {
+			t.Errorf("%s: ResponseBreached = %v, want %v", tc.name, got, tc.response)
+		}
+		if got := ticket.ResolutionBreached(now); got != tc.resolution {
+			t.Errorf("%s: ResolutionBreached = %v, want %v", tc.name, got, tc.resolution)
+		}
+		if got := ticket.SLABreached(now); got != (tc.response || tc.resolution) {
+			t.Errorf("%s: SLABreached = %v", tc.name, got)
+		}
+		if got := ticket.SLAAtRisk(now); got != tc.atRisk {
+			t.Errorf("%s: SLAAtRisk = %v, want %v", tc.name, got, tc.atRisk)
+		}
+	}
+}
+
+func TestTicketSLAWithoutPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ticket := Ticket{Status: TicketStatusOpen}
+	if ticket.SLABreached(now) || ticket.SLAAtRisk(now) {
+		t.Error("a ticket without due dates is breached or at risk")
+	}
+	// due dates without the policy loaded cannot tell how much time is left
+	due := now.Add(time.Minute)
+	ticket.ResponseDueAt = &due
+	if ticket.SLAAtRisk(now) {
+		t.Error("at risk without its policy loaded")
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+// ticketTransitions is the ticket status workflow: each status maps to the
+// statuses a ticket may move to from it. A ticket is started before it can be
+// resolved, so an OPEN ticket cannot be resolved directly.
+var ticketTransitions = map[TicketStatus][]TicketStatus{
+	TicketStatusOpen:       {TicketStatusInProgress, TicketStatusWaiting, TicketStatusCancelled},
+	TicketStatusInProgress: {TicketStatusOpen, TicketStatusWaiting, TicketStatusResolved, TicketStatusCancelled},
+	TicketStatusWaiting:    {TicketStatusOpen, TicketStatusInProgress, TicketStatusResolved, TicketStatusCancelled},
+	TicketStatusResolved:   {TicketStatusClosed, TicketStatusOpen},
+	TicketStatusClosed:     {TicketStatusOpen},
+	TicketStatusCancelled:  {TicketStatusOpen},
+}
+
+// CanTransitionTo reports whether a ticket may move from s to the given status
+func (s TicketStatus) CanTransitionTo(to TicketStatus) bool {
+	for _, next := range ticketTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the status is one in which work is still expected
+func (s TicketStatus) IsActive() bool {
+	return s == TicketStatusOpen || s == TicketStatusInProgress || s == TicketStatusWaiting
+}
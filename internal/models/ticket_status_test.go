@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestCanTransitionTo(t *testing.T) {
+	statuses := []TicketStatus{TicketStatusOpen, TicketStatusInProgress, TicketStatusWaiting, TicketStatusResolved, TicketStatusClosed, TicketStatusCancelled}
+	allowed := map[TicketStatus][]TicketStatus{
+		TicketStatusOpen:       {TicketStatusInProgress, TicketStatusWaiting, TicketStatusCancelled},
+		TicketStatusInProgress: {TicketStatusOpen, TicketStatusWaiting, TicketStatusResolved, TicketStatusCancelled},
+		TicketStatusWaiting:    {TicketStatusOpen, TicketStatusInProgress, TicketStatusResolved, TicketStatusCancelled},
+		TicketStatusResolved:   {TicketStatusClosed, TicketStatusOpen},
+		TicketStatusClosed:     {TicketStatusOpen},
+		TicketStatusCancelled:  {TicketStatusOpen},
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := false
+			for _, next := range allowed[from] {
+				want = want || next == to
+			}
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: got %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
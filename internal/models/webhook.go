@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Webhook is an endpoint notified of the events it subscribes to
+type Webhook struct {
+	Base
+	URL         string `gorm:"not null"`
+	Secret      string `gorm:"not null"`
+	Description string
+	// EventTypes holds the subscribed event types, e.g. "ticket.created"
+	EventTypes  datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"`
+	Active      bool                        `gorm:"not null"`
+	CreatedByID uuid.UUID                   `gorm:"type:uuid;not null"`
+
+	// Relations
+	CreatedBy User
+}
+
+// Subscribes reports whether the webhook wants events of the given type
+func (w Webhook) Subscribes(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is the outcome of delivering an event to a webhook
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery records sending one event to one webhook, including every
+// retry. Redelivering creates a new delivery of the same payload.
+type WebhookDelivery struct {
+	Base
+	WebhookID uuid.UUID `gorm:"type:uuid;not null;index"`
+	// EventID is the ID of the outbox event delivered
+	EventID        uuid.UUID             `gorm:"type:uuid;not null"`
+	EventType      string                `gorm:"not null"`
+	Payload        JSONB                 `gorm:"type:jsonb;not null"`
+	Status         WebhookDeliveryStatus `gorm:"type:webhook_delivery_status;not null"`
+	Attempts       int                   `gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus *int
+	ResponseBody   string
+	Error          string
+	DeliveredAt    *time.Time
+	RedeliveryOfID *uuid.UUID `gorm:"type:uuid"`
+
+	// Relations
+	Webhook      Webhook
+	RedeliveryOf *WebhookDelivery
+}
+
+// WebhookDeliveryFilter narrows the delivery log of a webhook
+type WebhookDeliveryFilter struct {
+	Status *WebhookDeliveryStatus
+}
@@ -0,0 +1,67 @@
+// Package notify emails users about events that concern them, such as a
+// ticket being assigned to them
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a plain text email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// TicketID is the ticket the message is about, if any; replies to the
+	// message are threaded onto it
+	TicketID *uuid.UUID
+}
+
+// format renders the message as an RFC 5322 email sent by from at date
+func (m Message) format(from string, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	if m.TicketID != nil {
+		fmt.Fprintf(&buf, "Message-ID: %s\r\n", ticketMessageID(*m.TicketID, domain(from)))
+	} else {
+		fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domain(from))
+	}
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write(bytes.ReplaceAll([]byte(m.Body), []byte("\n"), []byte("\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// domain returns the domain of an email address, used to build message IDs
+func domain(address string) string {
+	for i := len(address) - 1; i >= 0; i-- {
+		if address[i] == '@' {
+			return trimAngle(address[i+1:])
+		}
+	}
+	return "localhost"
+}
+
+func trimAngle(s string) string {
+	if n := len(s); n > 0 && s[n-1] == '>' {
+		return s[:n-1]
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/outbox"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Notifier emails users about outbox events that concern them, honouring
+// their notification preferences:
+//   - the assignee when a ticket is assigned to them
+//   - the creator when their ticket is resolved
+//   - the assignee of a maintenance schedule when it becomes overdue
+//   - the assignee of an escalated ticket, or the user who configured the
+//     escalation rule when the ticket is unassigned
+//
+// Each user is emailed about an event at most once.
+type Notifier struct {
+	sender           Sender
+	clock            clock.Clock
+	ticketRepo       repository.TicketRepository
+	userRepo         repository.UserRepository
+	scheduleRepo     repository.MaintenanceScheduleRepository
+	ruleRepo         repository.EscalationRuleRepository
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotifier creates a Notifier sending email through sender
+func NewNotifier(sender Sender, clk clock.Clock, ticketRepo repository.TicketRepository, userRepo repository.UserRepository, scheduleRepo repository.MaintenanceScheduleRepository, ruleRepo repository.EscalationRuleRepository, notificationRepo repository.NotificationRepository) *Notifier {
+	return &Notifier{
+		sender:           sender,
+		clock:            clk,
+		ticketRepo:       ticketRepo,
+		userRepo:         userRepo,
+		scheduleRepo:     scheduleRepo,
+		ruleRepo:         ruleRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+var _ outbox.Handler = (*Notifier)(nil)
+
+func (n *Notifier) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	event := msg.Event
+	var err error
+	switch {
+	case event.Type == events.TicketAssigned && event.TicketID != nil && event.AssigneeID != nil:
+		err = n.ticketAssigned(ctx, msg.ID, *event.TicketID, *event.AssigneeID)
+	case event.Type == events.TicketStatusChanged && event.TicketID != nil &&
+		event.Status == string(models.TicketStatusResolved):
+		err = n.ticketResolved(ctx, msg.ID, *event.TicketID)
+	case event.Type == events.MaintenanceOverdue && event.ScheduleID != nil:
+		err = n.maintenanceOverdue(ctx, msg.ID, *event.ScheduleID)
+	case event.Type == events.TicketEscalated && event.TicketID != nil && event.RuleID != nil:
+		err = n.ticketEscalated(ctx, msg.ID, *event.TicketID, *event.RuleID)
+	}
+	// Records deleted since the event have nobody left to notify
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (n *Notifier) ticketAssigned(ctx context.Context, eventID, ticketID, assigneeID uuid.UUID) error {
+	ticket, err := n.ticketRepo.GetByID(ticketID)
+	if err != nil {
+		return err
+	}
+	// Skip assignments already superseded by a reassignment
+	if ticket.AssignedTo == nil || ticket.AssignedTo.ID != assigneeID {
+		return nil
+	}
+	user := ticket.AssignedTo
+	if !user.NotificationPreferences.OnAssignment {
+		return nil
+	}
+	return n.notify(ctx, eventID, KindTicketAssigned, templateData{User: user, Ticket: ticket})
+}
+
+func (n *Notifier) ticketResolved(ctx context.Context, eventID, ticketID uuid.UUID) error {
+	ticket, err := n.ticketRepo.GetByID(ticketID)
+	if err != nil {
+		return err
+	}
+	user := &ticket.CreatedBy
+	if !user.NotificationPreferences.OnResolution {
+		return nil
+	}
+	return n.notify(ctx, eventID, KindTicketResolved, templateData{User: user, Ticket: ticket})
+}
+
+func (n *Notifier) maintenanceOverdue(ctx context.Context, eventID, scheduleID uuid.UUID) error {
+	schedule, err := n.scheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule.Status != models.MaintenanceStatusOverdue {
+		return nil
+	}
+	user := &schedule.AssignedTo
+	if !user.NotificationPreferences.OnMaintenanceOverdue {
+		return nil
+	}
+	return n.notify(ctx, eventID, KindMaintenanceOverdue, templateData{User: user, Schedule: schedule})
+}
+
+func (n *Notifier) ticketEscalated(ctx context.Context, eventID, ticketID, ruleID uuid.UUID) error {
+	ticket, err := n.ticketRepo.GetByID(ticketID)
+	if err != nil {
+		return err
+	}
+	rule, err := n.ruleRepo.GetByID(ruleID)
+	if err != nil {
+		return err
+	}
+	user := ticket.AssignedTo
+	if user == nil {
+		user = &rule.CreatedBy
+	}
+	if !user.NotificationPreferences.OnEscalation {
+		return nil
+	}
+	return n.notify(ctx, eventID, KindTicketEscalated, templateData{User: user, Ticket: ticket, Rule: rule})
+}
+
+// notify emails data.User about an event unless they already were
+func (n *Notifier) notify(ctx context.Context, eventID uuid.UUID, kind Kind, data templateData) error {
+	sent, err := n.notificationRepo.Sent(eventID, data.User.ID)
+	if err != nil || sent {
+		return err
+	}
+	msg, err := render(kind, data)
+	if err != nil {
+		return err
+	}
+	if err := n.sender.Send(ctx, msg); err != nil {
+		return err
+	}
+	return n.notificationRepo.Create(&models.Notification{
+		EventID: eventID,
+		UserID:  data.User.ID,
+		Kind:    string(kind),
+		Email:   msg.To,
+		Subject: msg.Subject,
+		SentAt:  n.clock.Now(),
+	})
+}
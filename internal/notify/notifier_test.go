@@ -0,0 +1,430 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/outbox"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// The fakes below implement only what the notifier uses; the embedded
+// interfaces are nil, so any other call panics.
+
+type fakeTicketRepo struct {
+	repository.TicketRepository
+	tickets map[uuid.UUID]*models.Ticket
+}
+
+func (r *fakeTicketRepo) GetByID(id uuid.UUID) (*models.Ticket, error) {
+	ticket, ok := r.tickets[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return ticket, nil
+}
+
+type fakeScheduleRepo struct {
+	repository.MaintenanceScheduleRepository
+	schedules map[uuid.UUID]*models.MaintenanceSchedule
+}
+
+func (r *fakeScheduleRepo) GetByID(id uuid.UUID) (*models.MaintenanceSchedule, error) {
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return schedule, nil
+}
+
+type fakeRuleRepo struct {
+	repository.EscalationRuleRepository
+	rules map[uuid.UUID]*models.EscalationRule
+}
+
+func (r *fakeRuleRepo) GetByID(id uuid.UUID) (*models.EscalationRule, error) {
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return rule, nil
+}
+
+type fakeNotificationRepo struct {
+	notifications []*models.Notification
+}
+
+func (r *fakeNotificationRepo) Create(notification *models.Notification) error {
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+func (r *fakeNotificationRepo) Sent(eventID, userID uuid.UUID) (bool, error) {
+	for _, n := range r.notifications {
+		if n.EventID == eventID && n.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newUser(name, email string) *models.User {
+	return &models.User{Base: models.Base{ID: uuid.New()}, Name: name, Email: email, NotificationPreferences: models.DefaultNotificationPreferences()}
+}
+
+// notifierFixture is a notifier writing emails to a directory, with a ticket
+// on a chiller reported by Dana and assigned to Sam, a maintenance schedule
+// for the chiller and an escalation rule set up by Priya
+type notifierFixture struct {
+	notifier      *Notifier
+	dir           string
+	notifications *fakeNotificationRepo
+	dana, sam     *models.User
+	priya         *models.User
+	ticket        *models.Ticket
+	schedule      *models.MaintenanceSchedule
+	rule          *models.EscalationRule
+}
+
+func newNotifierFixture(t *testing.T) *notifierFixture {
+	t.Helper()
+	f := &notifierFixture{
+		dir:           t.TempDir(),
+		notifications: &fakeNotificationRepo{},
+		dana:          newUser("Dana Whitfield", "dana@example.com"),
+		sam:           newUser("Sam Okafor", "sam@example.com"),
+		priya:         newUser("Priya Raman", "priya@example.com"),
+	}
+	chiller := &models.Asset{Base: models.Base{ID: uuid.New()}, Name: "Chiller 2", Location: "Plant room"}
+	f.ticket = &models.Ticket{
+		Base:         models.Base{ID: uuid.New()},
+		Title:        "Chiller 2 leaking",
+		Description:  "Water under the chiller.",
+		Status:       models.TicketStatusInProgress,
+		Priority:     models.TicketPriorityHigh,
+		CreatedByID:  f.dana.ID,
+		CreatedBy:    *f.dana,
+		AssignedToID: &f.sam.ID,
+		AssignedTo:   f.sam,
+		AssetID:      &chiller.ID,
+		Asset:        chiller,
+	}
+	f.schedule = &models.MaintenanceSchedule{
+		Base:         models.Base{ID: uuid.New()},
+		AssetID:      chiller.ID,
+		Asset:        *chiller,
+		Frequency:    models.MaintenanceFrequencyMonthly,
+		NextDue:      time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+		AssignedToID: f.sam.ID,
+		AssignedTo:   *f.sam,
+		Status:       models.MaintenanceStatusOverdue,
+		Notes:        "Check the refrigerant level",
+	}
+	f.rule = &models.EscalationRule{
+		Base:      models.Base{ID: uuid.New()},
+		Name:      "Unassigned for an hour",
+		CreatedBy: *f.priya,
+		Comment:   "Please pick this up.",
+	}
+
+	sender, err := NewFileSender(f.dir, "Helpdesk <helpdesk@tickets.example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.notifier = NewNotifier(sender, clock.NewFake(time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)),
+		&fakeTicketRepo{tickets: map[uuid.UUID]*models.Ticket{f.ticket.ID: f.ticket}},
+		nil,
+		&fakeScheduleRepo{schedules: map[uuid.UUID]*models.MaintenanceSchedule{f.schedule.ID: f.schedule}},
+		&fakeRuleRepo{rules: map[uuid.UUID]*models.EscalationRule{f.rule.ID: f.rule}},
+		f.notifications)
+	return f
+}
+
+func (f *notifierFixture) handle(t *testing.T, id uuid.UUID, event events.Event) {
+	t.Helper()
+	if err := f.notifier.HandleEvent(context.Background(), outbox.Message{ID: id, Event: event, Attempt: 1}); err != nil {
+		t.Fatalf("HandleEvent(%s): %v", event.Type, err)
+	}
+}
+
+// sentEmail is an email the file sender wrote, decoded
+type sentEmail struct {
+	to, subject, body string
+	header            mail.Header
+}
+
+func (f *notifierFixture) sent(t *testing.T) []sentEmail {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(f.dir, "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent []sentEmail
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(msg.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, sentEmail{
+			to:      msg.Header.Get("To"),
+			subject: subject,
+			body:    strings.ReplaceAll(decodeQuotedPrintable(t, body), "\r\n", "\n"),
+			header:  msg.Header,
+		})
+	}
+	return sent
+}
+
+func decodeQuotedPrintable(t *testing.T, body []byte) string {
+	t.Helper()
+	decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(decoded)
+}
+
+func TestNotifierEmails(t *testing.T) {
+	tests := []struct {
+		name  string
+		event func(f *notifierFixture) events.Event
+		to    string
+		// subject and body are formats filled in with the ticket ID
+		subject string
+		body    []string
+	}{
+		{
+			name: "assignment",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.sam.ID}
+			},
+			to:      "sam@example.com",
+			subject: "[HIGH] Ticket assigned to you: Chiller 2 leaking [#T-%s]",
+			body: []string{
+				"Hello Sam Okafor,\n\nA ticket has been assigned to you.\n",
+				"Priority: HIGH\nStatus:   IN_PROGRESS\nAsset:    Chiller 2 (Plant room)\nTicket:   %s\n\nWater under the chiller.\n",
+			},
+		},
+		{
+			name: "resolution",
+			event: func(f *notifierFixture) events.Event {
+				f.ticket.Status = models.TicketStatusResolved
+				return events.Event{Type: events.TicketStatusChanged, TicketID: &f.ticket.ID, Status: string(models.TicketStatusResolved)}
+			},
+			to:      "dana@example.com",
+			subject: "Your ticket was resolved: Chiller 2 leaking [#T-%s]",
+			body: []string{
+				"Hello Dana Whitfield,\n\nThe ticket you reported has been resolved.\n",
+				"Title:    Chiller 2 leaking\nResolved by: Sam Okafor\nTicket:   %s\n",
+			},
+		},
+		{
+			name: "overdue maintenance",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.MaintenanceOverdue, ScheduleID: &f.schedule.ID, AssetID: &f.schedule.AssetID}
+			},
+			to:      "sam@example.com",
+			subject: "Maintenance overdue: Chiller 2",
+			body: []string{
+				"Hello Sam Okafor,\n\nScheduled MONTHLY maintenance of an asset assigned to you is overdue.\n",
+				"Asset:    Chiller 2 (Plant room)\nDue:      2026-03-10 09:00\nNotes:    Check the refrigerant level\n",
+			},
+		},
+		{
+			name: "escalation of an assigned ticket",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.TicketEscalated, TicketID: &f.ticket.ID, RuleID: &f.rule.ID}
+			},
+			to:      "sam@example.com",
+			subject: "[HIGH] Ticket escalated: Chiller 2 leaking [#T-%s]",
+			body: []string{
+				"Hello Sam Okafor,\n\nA ticket was escalated by the rule \"Unassigned for an hour\".\n",
+				"Assignee: Sam Okafor\nAsset:    Chiller 2 (Plant room)\nTicket:   %s\n\nPlease pick this up.\n",
+			},
+		},
+		{
+			name: "escalation of an unassigned ticket",
+			event: func(f *notifierFixture) events.Event {
+				f.ticket.AssignedToID, f.ticket.AssignedTo = nil, nil
+				return events.Event{Type: events.TicketEscalated, TicketID: &f.ticket.ID, RuleID: &f.rule.ID}
+			},
+			to:      "priya@example.com",
+			subject: "[HIGH] Ticket escalated: Chiller 2 leaking [#T-%s]",
+			body: []string{
+				"Hello Priya Raman,\n",
+				"Assignee: nobody\n",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newNotifierFixture(t)
+			eventID := uuid.New()
+			f.handle(t, eventID, tc.event(f))
+
+			sent := f.sent(t)
+			if len(sent) != 1 {
+				t.Fatalf("sent %d emails, want 1", len(sent))
+			}
+			email := sent[0]
+			if email.to != tc.to {
+				t.Errorf("sent to %s, want %s", email.to, tc.to)
+			}
+			if want := fill(tc.subject, f.ticket.ID); email.subject != want {
+				t.Errorf("subject %q, want %q", email.subject, want)
+			}
+			for _, part := range tc.body {
+				if want := fill(part, f.ticket.ID); !strings.Contains(email.body, want) {
+					t.Errorf("body\n%s\ndoes not contain\n%s", email.body, want)
+				}
+			}
+			if len(f.notifications.notifications) != 1 {
+				t.Fatalf("recorded %d notifications, want 1", len(f.notifications.notifications))
+			}
+			if n := f.notifications.notifications[0]; n.EventID != eventID || n.Email != tc.to || n.Subject != email.subject {
+				t.Errorf("recorded %+v, want the email sent for the event", n)
+			}
+		})
+	}
+}
+
+// fill puts the ticket ID into a format that has a verb for it
+func fill(format string, ticketID uuid.UUID) string {
+	return strings.ReplaceAll(format, "%s", ticketID.String())
+}
+
+func TestNotifierThreadsTicketEmails(t *testing.T) {
+	f := newNotifierFixture(t)
+	f.handle(t, uuid.New(), events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.sam.ID})
+	sent := f.sent(t)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sent))
+	}
+	if id, ok := ParseTicketMessageID(sent[0].header.Get("Message-Id")); !ok || id != f.ticket.ID {
+		t.Errorf("Message-ID %s does not name ticket %s", sent[0].header.Get("Message-Id"), f.ticket.ID)
+	}
+	if id, ok := ParseTicketToken(sent[0].subject); !ok || id != f.ticket.ID {
+		t.Errorf("subject %q does not carry the token of ticket %s", sent[0].subject, f.ticket.ID)
+	}
+}
+
+func TestNotifierSkips(t *testing.T) {
+	tests := []struct {
+		name  string
+		event func(f *notifierFixture) events.Event
+	}{
+		{
+			name: "superseded assignment",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.dana.ID}
+			},
+		},
+		{
+			name: "assignment notifications off",
+			event: func(f *notifierFixture) events.Event {
+				f.sam.NotificationPreferences.OnAssignment = false
+				return events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.sam.ID}
+			},
+		},
+		{
+			name: "other status change",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.TicketStatusChanged, TicketID: &f.ticket.ID, Status: string(models.TicketStatusWaiting)}
+			},
+		},
+		{
+			name: "resolution notifications off",
+			event: func(f *notifierFixture) events.Event {
+				f.ticket.CreatedBy.NotificationPreferences.OnResolution = false
+				return events.Event{Type: events.TicketStatusChanged, TicketID: &f.ticket.ID, Status: string(models.TicketStatusResolved)}
+			},
+		},
+		{
+			name: "maintenance no longer overdue",
+			event: func(f *notifierFixture) events.Event {
+				f.schedule.Status = models.MaintenanceStatusCompleted
+				return events.Event{Type: events.MaintenanceOverdue, ScheduleID: &f.schedule.ID}
+			},
+		},
+		{
+			name: "escalation notifications off",
+			event: func(f *notifierFixture) events.Event {
+				f.sam.NotificationPreferences.OnEscalation = false
+				return events.Event{Type: events.TicketEscalated, TicketID: &f.ticket.ID, RuleID: &f.rule.ID}
+			},
+		},
+		{
+			name: "deleted ticket",
+			event: func(f *notifierFixture) events.Event {
+				id := uuid.New()
+				return events.Event{Type: events.TicketAssigned, TicketID: &id, AssigneeID: &f.sam.ID}
+			},
+		},
+		{
+			name: "event nobody is notified of",
+			event: func(f *notifierFixture) events.Event {
+				return events.Event{Type: events.TicketCreated, TicketID: &f.ticket.ID}
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newNotifierFixture(t)
+			f.handle(t, uuid.New(), tc.event(f))
+			if sent := f.sent(t); len(sent) != 0 {
+				t.Errorf("sent %+v, want no email", sent)
+			}
+		})
+	}
+}
+
+func TestNotifierSendsOncePerEvent(t *testing.T) {
+	f := newNotifierFixture(t)
+	eventID := uuid.New()
+	event := events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.sam.ID}
+	f.handle(t, eventID, event)
+	// the event is redelivered, e.g. after another handler failed
+	f.handle(t, eventID, event)
+	if sent := f.sent(t); len(sent) != 1 {
+		t.Errorf("sent %d emails, want 1", len(sent))
+	}
+}
+
+func TestLogSender(t *testing.T) {
+	var buf bytes.Buffer
+	f := newNotifierFixture(t)
+	f.notifier.sender = NewLogSender(log.New(&buf, "", 0))
+	f.handle(t, uuid.New(), events.Event{Type: events.TicketAssigned, TicketID: &f.ticket.ID, AssigneeID: &f.sam.ID})
+	logged := buf.String()
+	want := "Notify: email to sam@example.com: [HIGH] Ticket assigned to you: Chiller 2 leaking [#T-" + f.ticket.ID.String() + "]\nHello Sam Okafor,"
+	if !strings.HasPrefix(logged, want) {
+		t.Errorf("logged\n%s\nwant it to start with\n%s", logged, want)
+	}
+}
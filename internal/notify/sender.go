@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig holds the settings of an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender address, e.g. "Ticketing <noreply@example.com>"
+	From string
+}
+
+// SMTPSender sends messages through an SMTP relay, upgrading to TLS when the
+// server offers STARTTLS
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+	// envelopeFrom is the bare address of from used in MAIL FROM
+	envelopeFrom string
+}
+
+// NewSMTPSender creates a SMTPSender for the relay described by config
+func NewSMTPSender(config SMTPConfig) (*SMTPSender, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+	sender := &SMTPSender{
+		addr:         net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		from:         from.String(),
+		envelopeFrom: from.Address,
+	}
+	if config.Username != "" {
+		sender.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+	return sender, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := msg.format(s.from, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.envelopeFrom, []string{msg.To}, data)
+}
+
+// FileSender writes each message to a .eml file in a directory instead of
+// sending it, for development and tests
+type FileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates a FileSender writing to dir, creating it if needed
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := msg.format(s.from, now)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405.000000000"), os.Getpid())
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o644)
+}
+
+// LogSender logs messages instead of sending them
+type LogSender struct {
+	logger *log.Logger
+}
+
+// NewLogSender creates a LogSender writing to logger
+func NewLogSender(logger *log.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Printf("Notify: email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
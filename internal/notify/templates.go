@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// Kind identifies a notification and the template it is rendered from
+type Kind string
+
+const (
+	KindTicketAssigned     Kind = "ticket_assigned"
+	KindTicketResolved     Kind = "ticket_resolved"
+	KindMaintenanceOverdue Kind = "maintenance_overdue"
+	KindTicketEscalated    Kind = "ticket_escalated"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// templates holds one template set per kind, each defining "subject" and "body"
+var templates = func() map[Kind]*template.Template {
+	parsed := make(map[Kind]*template.Template)
+	for _, kind := range []Kind{KindTicketAssigned, KindTicketResolved, KindMaintenanceOverdue, KindTicketEscalated} {
+		parsed[kind] = template.Must(template.New(string(kind)+".tmpl").
+			Funcs(template.FuncMap{"token": TicketToken}).
+			ParseFS(templateFiles, "templates/"+string(kind)+".tmpl"))
+	}
+	return parsed
+}()
+
+// templateData is what notification templates render
+type templateData struct {
+	User     *models.User
+	Ticket   *models.Ticket
+	Schedule *models.MaintenanceSchedule
+	Rule     *models.EscalationRule
+}
+
+// render builds the message of a notification kind for data.User
+func render(kind Kind, data templateData) (Message, error) {
+	tmpl, ok := templates[kind]
+	if !ok {
+		return Message{}, fmt.Errorf("no template for notification %q", kind)
+	}
+	var subject, body bytes.Buffer
+	msg := Message{To: data.User.Email}
+	if data.Ticket != nil {
+		msg.TicketID = &data.Ticket.ID
+	}
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, err
+	}
+	msg.Subject = strings.Join(strings.Fields(subject.String()), " ")
+	msg.Body = strings.TrimLeft(body.String(), "\n")
+	return msg, nil
+}
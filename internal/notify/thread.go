@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Emails about a ticket carry its token in the subject and its ID in their
+// Message-ID, so the inbound gateway can thread replies back to the ticket
+// whichever of the two the recipient's mail client keeps.
+
+var (
+	ticketTokenPattern     = regexp.MustCompile(`\[#T-([0-9a-fA-F-]{36})\]`)
+	ticketMessageIDPattern = regexp.MustCompile(`^<?ticket\.([0-9a-fA-F-]{36})\.`)
+)
+
+// TicketToken returns the subject token identifying a ticket
+func TicketToken(id uuid.UUID) string {
+	return fmt.Sprintf("[#T-%s]", id)
+}
+
+// ParseTicketToken returns the ticket whose token appears in subject
+func ParseTicketToken(subject string) (uuid.UUID, bool) {
+	return parseMatch(ticketTokenPattern, subject)
+}
+
+// ticketMessageID returns a unique Message-ID for an email about a ticket
+func ticketMessageID(id uuid.UUID, domain string) string {
+	return fmt.Sprintf("<ticket.%s.%s@%s>", id, uuid.NewString(), domain)
+}
+
+// ParseTicketMessageID returns the ticket an outgoing Message-ID refers to
+func ParseTicketMessageID(messageID string) (uuid.UUID, bool) {
+	return parseMatch(ticketMessageIDPattern, strings.TrimSpace(messageID))
+}
+
+func parseMatch(pattern *regexp.Regexp, s string) (uuid.UUID, bool) {
+	match := pattern.FindStringSubmatch(s)
+	if match == nil {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(match[1])
+	return id, err == nil
+}
@@ -0,0 +1,195 @@
+// Package outbox dispatches the domain events services record in the outbox
+// table. Events are delivered at least once: a handler may see an event again
+// after a crash, and should use Message.ID to ignore repeats.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/changefeed"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+const (
+	batchSize = 50
+	// lease is how long a claimed event is reserved for one dispatcher. A
+	// delivery attempt is cancelled when its lease runs out.
+	lease      = time.Minute
+	minBackoff = 5 * time.Second
+	maxBackoff = time.Hour
+	// maxAttempts is how often an event is tried before it is marked dead,
+	// about ten hours of retries
+	maxAttempts = 20
+)
+
+// Message is an event read from the outbox
+type Message struct {
+	ID         uuid.UUID
+	Event      events.Event
+	RecordedAt time.Time
+	// Attempt counts delivery attempts, starting at 1
+	Attempt int
+}
+
+// errInvalidPayload marks events that can never be delivered
+var errInvalidPayload = errors.New("invalid payload")
+
+// Handler delivers events to one consumer. Returning an error makes the
+// dispatcher retry the event later with that handler; handlers that
+// succeeded are not called again.
+type Handler interface {
+	HandleEvent(ctx context.Context, msg Message) error
+}
+
+// HandlerFunc adapts a function to the Handler interface
+type HandlerFunc func(ctx context.Context, msg Message) error
+
+func (f HandlerFunc) HandleEvent(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Dispatcher delivers pending outbox events to its handlers, retrying
+// failures with exponential backoff until maxAttempts, when the event is
+// marked dead. Several dispatchers may share a database; each event is
+// claimed by one of them at a time.
+type Dispatcher struct {
+	repo     repository.OutboxRepository
+	clock    clock.Clock
+	interval time.Duration
+	logger   *log.Logger
+	handlers []namedHandler
+}
+
+// namedHandler is a registered handler and the name deliveries to it are
+// recorded under
+type namedHandler struct {
+	name    string
+	handler Handler
+}
+
+// NewDispatcher creates a Dispatcher polling for pending events every interval
+func NewDispatcher(repo repository.OutboxRepository, clk clock.Clock, interval time.Duration, logger *log.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		clock:    clk,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Register adds a handler under a name that is unique among the handlers
+// and stays the same across releases, since the events delivered to it are
+// recorded by name. Handlers must be registered before Run.
+func (d *Dispatcher) Register(name string, handler Handler) {
+	d.handlers = append(d.handlers, namedHandler{name: name, handler: handler})
+}
+
+// Run dispatches pending events immediately, then on every tick and whenever
+// wake delivers a change, until ctx is done. wake may be nil.
+func (d *Dispatcher) Run(ctx context.Context, wake <-chan changefeed.Change) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-wake:
+			if !ok {
+				wake = nil
+			}
+		}
+	}
+}
+
+// dispatchPending delivers batches of due events until none are left
+func (d *Dispatcher) dispatchPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := d.repo.ClaimPending(d.clock.Now(), lease, batchSize)
+		if err != nil {
+			d.logger.Printf("Outbox: failed to claim events: %v", err)
+			return
+		}
+		for _, row := range claimed {
+			d.dispatch(ctx, row)
+		}
+		if len(claimed) < batchSize {
+			return
+		}
+	}
+}
+
+// dispatch delivers one event to the handlers it was not delivered to yet
+// and records the outcome
+func (d *Dispatcher) dispatch(ctx context.Context, row *models.OutboxEvent) {
+	delivered, err := d.deliver(ctx, row)
+	if err == nil {
+		if err := d.repo.MarkSent(row.ID, d.clock.Now()); err != nil {
+			d.logger.Printf("Outbox: failed to mark event %s sent: %v", row.ID, err)
+		}
+		return
+	}
+
+	if row.Attempts >= maxAttempts || errors.Is(err, errInvalidPayload) {
+		d.logger.Printf("Outbox: giving up on %s event %s after %d attempt(s): %v", row.Type, row.ID, row.Attempts, err)
+		if err := d.repo.MarkDead(row.ID, d.clock.Now(), err.Error(), delivered); err != nil {
+			d.logger.Printf("Outbox: failed to mark event %s dead: %v", row.ID, err)
+		}
+		return
+	}
+	retryAt := d.clock.Now().Add(backoff(row.Attempts))
+	d.logger.Printf("Outbox: attempt %d of %s event %s failed, retrying at %s: %v",
+		row.Attempts, row.Type, row.ID, retryAt.Format(time.RFC3339), err)
+	if err := d.repo.MarkFailed(row.ID, retryAt, err.Error(), delivered); err != nil {
+		d.logger.Printf("Outbox: failed to record failure of event %s: %v", row.ID, err)
+	}
+}
+
+// deliver hands the event to each handler it was not delivered to yet and
+// returns the names of the handlers it has now been delivered to
+func (d *Dispatcher) deliver(ctx context.Context, row *models.OutboxEvent) ([]string, error) {
+	delivered := append([]string{}, row.DeliveredTo...)
+	msg := Message{ID: row.ID, RecordedAt: row.CreatedAt, Attempt: row.Attempts}
+	if err := json.Unmarshal(row.Payload, &msg.Event); err != nil {
+		return delivered, fmt.Errorf("%w: %v", errInvalidPayload, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+	var errs []error
+	for _, h := range d.handlers {
+		if slices.Contains(delivered, h.name) {
+			continue
+		}
+		if err := h.handler.HandleEvent(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		delivered = append(delivered, h.name)
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// backoff returns the delay before retrying an event that failed attempts times
+func backoff(attempts int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
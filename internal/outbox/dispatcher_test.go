@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+// fakeOutbox records how the dispatcher settles an event; the embedded
+// interface is nil, so any other call panics
+type fakeOutbox struct {
+	repository.OutboxRepository
+	sent, failed, dead bool
+	reason             string
+	deliveredTo        []string
+}
+
+func (r *fakeOutbox) MarkSent(id uuid.UUID, at time.Time) error {
+	r.sent = true
+	return nil
+}
+
+func (r *fakeOutbox) MarkFailed(id uuid.UUID, retryAt time.Time, reason string, deliveredTo []string) error {
+	r.failed, r.reason, r.deliveredTo = true, reason, deliveredTo
+	return nil
+}
+
+func (r *fakeOutbox) MarkDead(id uuid.UUID, at time.Time, reason string, deliveredTo []string) error {
+	r.dead, r.reason, r.deliveredTo = true, reason, deliveredTo
+	return nil
+}
+
+// countingHandler counts its calls and fails while err is set
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) HandleEvent(ctx context.Context, msg Message) error {
+	h.calls++
+	return h.err
+}
+
+func newTestDispatcher(repo repository.OutboxRepository, handlers map[string]*countingHandler) *Dispatcher {
+	d := NewDispatcher(repo, clock.NewFake(time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)), time.Second, log.New(io.Discard, "", 0))
+	for _, name := range []string{"webhooks", "notifications"} {
+		d.Register(name, handlers[name])
+	}
+	return d
+}
+
+func outboxRow(t *testing.T, attempts int, deliveredTo ...string) *models.OutboxEvent {
+	t.Helper()
+	payload, err := json.Marshal(events.Event{Type: events.TicketCreated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.OutboxEvent{
+		Base:        models.Base{ID: uuid.New()},
+		Type:        string(events.TicketCreated),
+		Payload:     payload,
+		Attempts:    attempts,
+		DeliveredTo: deliveredTo,
+	}
+}
+
+func TestDispatchRetriesFailedHandlers(t *testing.T) {
+	handlers := map[string]*countingHandler{
+		"webhooks":      {},
+		"notifications": {err: errors.New("smtp down")},
+	}
+	repo := &fakeOutbox{}
+	d := newTestDispatcher(repo, handlers)
+
+	d.dispatch(context.Background(), outboxRow(t, 1))
+	if !repo.failed || repo.sent || repo.dead {
+		t.Fatalf("settled as sent %v, failed %v, dead %v; want failed", repo.sent, repo.failed, repo.dead)
+	}
+	if !slices.Equal(repo.deliveredTo, []string{"webhooks"}) || repo.reason != "notifications: smtp down" {
+		t.Errorf("recorded delivery to %v because %q, want webhooks only", repo.deliveredTo, repo.reason)
+	}
+
+	// the retry calls only the handler that failed
+	handlers["notifications"].err = nil
+	repo = &fakeOutbox{}
+	d.repo = repo
+	d.dispatch(context.Background(), outboxRow(t, 2, "webhooks"))
+	if !repo.sent {
+		t.Fatalf("retry not marked sent")
+	}
+	if handlers["webhooks"].calls != 1 || handlers["notifications"].calls != 2 {
+		t.Errorf("webhooks called %d times and notifications %d, want 1 and 2", handlers["webhooks"].calls, handlers["notifications"].calls)
+	}
+}
+
+func TestDispatchGivesUp(t *testing.T) {
+	handlers := map[string]*countingHandler{
+		"webhooks":      {err: errors.New("connection refused")},
+		"notifications": {},
+	}
+	repo := &fakeOutbox{}
+	d := newTestDispatcher(repo, handlers)
+
+	d.dispatch(context.Background(), outboxRow(t, maxAttempts-1, "notifications"))
+	if !repo.failed || repo.dead {
+		t.Fatalf("attempt %d marked dead, want it retried", maxAttempts-1)
+	}
+
+	repo = &fakeOutbox{}
+	d.repo = repo
+	d.dispatch(context.Background(), outboxRow(t, maxAttempts, "notifications"))
+	if !repo.dead || repo.failed {
+		t.Fatalf("attempt %d not marked dead", maxAttempts)
+	}
+	if !slices.Equal(repo.deliveredTo, []string{"notifications"}) {
+		t.Errorf("dead event delivered to %v, want notifications", repo.deliveredTo)
+	}
+}
+
+func TestDispatchInvalidPayload(t *testing.T) {
+	handlers := map[string]*countingHandler{"webhooks": {}, "notifications": {}}
+	repo := &fakeOutbox{}
+	d := newTestDispatcher(repo, handlers)
+
+	row := outboxRow(t, 1)
+	row.Payload = []byte("{")
+	d.dispatch(context.Background(), row)
+	if !repo.dead {
+		t.Errorf("an event that cannot be decoded is not marked dead")
+	}
+	if handlers["webhooks"].calls != 0 || handlers["notifications"].calls != 0 {
+		t.Errorf("handlers called with an event that cannot be decoded")
+	}
+}
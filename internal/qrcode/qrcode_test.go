@@ -0,0 +1,452 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// Format information for level M and each mask, from the table in ISO/IEC
+// 18004 Annex C
+var formatWords = [8]int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}
+
+func TestFormatBits(t *testing.T) {
+	for mask, want := range formatWords {
+		if got := formatBits(mask); got != want {
+			t.Errorf("formatBits(%d) = %#05x, want %#05x", mask, got, want)
+		}
+	}
+}
+
+// Version information, from the table in ISO/IEC 18004 Annex D
+var versionWords = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+func TestVersionBits(t *testing.T) {
+	for version, want := range versionWords {
+		if got := versionBits(version); got != want {
+			t.Errorf("versionBits(%d) = %#06x, want %#06x", version, got, want)
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{
+			// The 1-M symbol for "01234567" worked through in ISO/IEC 18004
+			// Annex I
+			name: "ISO 01234567",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			want: []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			name: "1-M HELLO WORLD",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+	for _, tc := range tests {
+		if got := rsRemainder(tc.data, rsDivisor(len(tc.want))); !bytes.Equal(got, tc.want) {
+			t.Errorf("%s: rsRemainder = % X, want % X", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		text    string
+		version int
+	}{
+		{"https://tickets.example.com/scan/7K3M9Q2X", 3},
+		{"", 1},
+		{strings.Repeat("a", 14), 1},
+		{strings.Repeat("b", 15), 2},
+		{"https://tickets.example.com/scan/" + strings.Repeat("Z", 80), 7},
+		{strings.Repeat("é", 70), 8},
+		{strings.Repeat("c", 213), 10},
+	}
+	for _, tc := range tests {
+		code, err := Encode(tc.text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(tc.text), err)
+		}
+		if want := tc.version*4 + 17; code.Size() != want {
+			t.Errorf("Encode(%d bytes) is %d modules wide, want version %d (%d)", len(tc.text), code.Size(), tc.version, want)
+		}
+		got, err := decode(code)
+		if err != nil {
+			t.Fatalf("decoding %d bytes: %v", len(tc.text), err)
+		}
+		if got != tc.text {
+			t.Errorf("decoded %q, want %q", got, tc.text)
+		}
+	}
+}
+
+// TestEveryMask lays a code out with each mask in turn, since Encode only
+// uses the ones that score best for its input
+func TestEveryMask(t *testing.T) {
+	const text = "https://tickets.example.com/scan/7K3M9Q2X"
+	for _, version := range []int{3, 7} {
+		capacity := levelM[version].dataCodewords() * 8
+		codewords := interleave(version, encodeData([]byte(text), 8, capacity))
+		for mask := 0; mask < 8; mask++ {
+			size := version*4 + 17
+			c := &Code{size: size, modules: grid(size), function: grid(size)}
+			c.drawFunctionPatterns(version)
+			c.drawCodewords(codewords)
+			c.applyMask(mask)
+			c.drawFormatBits(mask)
+
+			got, err := decode(c)
+			if err != nil {
+				t.Fatalf("version %d, mask %d: %v", version, mask, err)
+			}
+			if got != text {
+				t.Errorf("version %d, mask %d: decoded %q, want %q", version, mask, got, text)
+			}
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 214)); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("Encode(214 bytes): got %v, want ErrTooLong", err)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode("https://tickets.example.com/scan/7K3M9Q2X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 3
+	data, err := code.PNG(scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	side := (code.Size() + 2*QuietZone) * scale
+	if bounds := img.Bounds(); bounds.Dx() != side || bounds.Dy() != side {
+		t.Fatalf("image is %v, want %dx%d", bounds, side, side)
+	}
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			mx, my := x/scale-QuietZone, y/scale-QuietZone
+			want := mx >= 0 && my >= 0 && mx < code.Size() && my < code.Size() && code.Dark(mx, my)
+			r, _, _, _ := img.At(x, y).RGBA()
+			if dark := r == 0; dark != want {
+				t.Fatalf("pixel %d,%d dark=%v, want %v", x, y, dark, want)
+			}
+		}
+	}
+}
+
+// decode reads a level M byte mode code back from its modules following
+// ISO/IEC 18004 directly, so the encoder's layout is checked against the
+// standard rather than against itself
+func decode(c *Code) (string, error) {
+	size := c.Size()
+	version := (size - 17) / 4
+	dark := c.Dark
+
+	format, err := readFormat(c)
+	if err != nil {
+		return "", err
+	}
+	if level := format >> 3; level != 0 {
+		return "", fmt.Errorf("error correction level %02b, want M (00)", level)
+	}
+	mask := format & 7
+
+	if version >= 7 {
+		var first, second int
+		for i := 0; i < 18; i++ {
+			if dark(size-11+i%3, i/3) {
+				first |= 1 << i
+			}
+			if dark(i/3, size-11+i%3) {
+				second |= 1 << i
+			}
+		}
+		if first != versionWords[version] || second != versionWords[version] {
+			return "", fmt.Errorf("version information %#x and %#x, want %#x", first, second, versionWords[version])
+		}
+	}
+
+	reserved := functionModules(size, version)
+	var bits []bool
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := 0; i < size; i++ {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for _, x := range []int{right, right - 1} {
+				if !reserved[y][x] {
+					bits = append(bits, dark(x, y) != masked(mask, y, x))
+				}
+			}
+		}
+		upward = !upward
+	}
+
+	total := totalCodewords[version]
+	if len(bits)/8 != total {
+		return "", fmt.Errorf("%d codewords in the symbol, want %d", len(bits)/8, total)
+	}
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				codewords[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	data, err := deinterleave(version, codewords)
+	if err != nil {
+		return "", err
+	}
+	return readSegment(version, data)
+}
+
+// readFormat reads both copies of the format information and checks their
+// BCH code, returning the five data bits
+func readFormat(c *Code) (int, error) {
+	size := c.Size()
+	positions := [2][15][2]int{}
+	for i := 0; i < 15; i++ {
+		switch {
+		case i < 6:
+			positions[0][i] = [2]int{8, i}
+		case i < 8:
+			positions[0][i] = [2]int{8, i + 1}
+		case i == 8:
+			positions[0][i] = [2]int{7, 8}
+		default:
+			positions[0][i] = [2]int{14 - i, 8}
+		}
+		if i < 8 {
+			positions[1][i] = [2]int{size - 1 - i, 8}
+		} else {
+			positions[1][i] = [2]int{8, size - 15 + i}
+		}
+	}
+
+	var words [2]int
+	for n, places := range positions {
+		for i, p := range places {
+			if c.Dark(p[0], p[1]) {
+				words[n] |= 1 << i
+			}
+		}
+	}
+	if words[0] != words[1] {
+		return 0, fmt.Errorf("format copies differ: %#x and %#x", words[0], words[1])
+	}
+	if !c.Dark(8, size-8) {
+		return 0, errors.New("dark module is light")
+	}
+
+	word := words[0] ^ 0x5412
+	remainder := word
+	for bit := 14; bit >= 10; bit-- {
+		if remainder&(1<<bit) != 0 {
+			remainder ^= 0x537 << (bit - 10)
+		}
+	}
+	if remainder != 0 {
+		return 0, fmt.Errorf("format information %#x fails its BCH check", words[0])
+	}
+	return word >> 10, nil
+}
+
+// functionModules marks the modules that do not carry codewords: finders with
+// their separators and the format areas, timing patterns, alignment patterns
+// and the version areas
+func functionModules(size, version int) [][]bool {
+	reserved := make([][]bool, size)
+	for y := range reserved {
+		reserved[y] = make([]bool, size)
+		for x := range reserved[y] {
+			reserved[y][x] = x == 6 || y == 6 ||
+				(x <= 8 && y <= 8) || (x >= size-8 && y <= 8) || (x <= 8 && y >= size-8) ||
+				(version >= 7 && ((x >= size-11 && x < size-8 && y < 6) || (y >= size-11 && y < size-8 && x < 6)))
+		}
+	}
+	centres := alignmentCentres[version]
+	for _, cx := range centres {
+		for _, cy := range centres {
+			if (cx == 6 && cy == 6) || (cx == 6 && cy == size-7) || (cx == size-7 && cy == 6) {
+				continue
+			}
+			for y := cy - 2; y <= cy+2; y++ {
+				for x := cx - 2; x <= cx+2; x++ {
+					reserved[y][x] = true
+				}
+			}
+		}
+	}
+	return reserved
+}
+
+// masked reports whether the data mask flips the module at row i, column j
+func masked(mask, i, j int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return (i*j)%2+(i*j)%3 == 0
+	case 6:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	default:
+		return ((i+j)%2+(i*j)%3)%2 == 0
+	}
+}
+
+// Symbol capacities and level M block structures for versions 1 to 10, from
+// ISO/IEC 18004 Tables 1 and 9
+var (
+	totalCodewords   = [...]int{1: 26, 2: 44, 3: 70, 4: 100, 5: 134, 6: 172, 7: 196, 8: 242, 9: 292, 10: 346}
+	alignmentCentres = [...][]int{2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34}, 7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50}}
+	blocksM          = [...]struct {
+		ec   int
+		data []int
+	}{
+		1:  {10, []int{16}},
+		2:  {16, []int{28}},
+		3:  {26, []int{44}},
+		4:  {18, []int{32, 32}},
+		5:  {24, []int{43, 43}},
+		6:  {16, []int{27, 27, 27, 27}},
+		7:  {18, []int{31, 31, 31, 31}},
+		8:  {22, []int{38, 38, 39, 39}},
+		9:  {22, []int{36, 36, 36, 37, 37}},
+		10: {26, []int{43, 43, 43, 43, 44}},
+	}
+)
+
+// deinterleave splits the codewords into blocks, checks each block's error
+// correction by evaluating its syndromes, and returns the data codewords
+func deinterleave(version int, codewords []byte) ([]byte, error) {
+	spec := blocksM[version]
+	blocks := make([][]byte, len(spec.data))
+	longest := 0
+	for _, n := range spec.data {
+		longest = max(longest, n)
+	}
+	next := 0
+	for i := 0; i < longest; i++ {
+		for b, n := range spec.data {
+			if i < n {
+				blocks[b] = append(blocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < spec.ec; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[next])
+			next++
+		}
+	}
+
+	var data []byte
+	for b, block := range blocks {
+		for power := 0; power < spec.ec; power++ {
+			if s := evaluate(block, gfPow(power)); s != 0 {
+				return nil, fmt.Errorf("block %d: syndrome %d is %d", b, power, s)
+			}
+		}
+		data = append(data, block[:spec.data[b]]...)
+	}
+	return data, nil
+}
+
+// readSegment reads the single byte mode segment the encoder writes
+func readSegment(version int, data []byte) (string, error) {
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		return "", fmt.Errorf("mode %04b, want byte mode (0100)", mode)
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	n := read(countBits)
+	if (pos+n*8+7)/8 > len(data) {
+		return "", fmt.Errorf("count %d overruns the data", n)
+	}
+	text := make([]byte, n)
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text), nil
+}
+
+// evaluate computes the polynomial with coefficients p, highest power first,
+// at x in GF(2^8)
+func evaluate(p []byte, x byte) byte {
+	var result byte
+	for _, coef := range p {
+		result = gfTimes(result, x) ^ coef
+	}
+	return result
+}
+
+// gfPow returns 2^n in GF(2^8)
+func gfPow(n int) byte {
+	result := byte(1)
+	for i := 0; i < n; i++ {
+		result = gfTimes(result, 2)
+	}
+	return result
+}
+
+// gfTimes multiplies in GF(2^8) modulo 0x11D by shift and add, independently
+// of the encoder's gfMultiply
+func gfTimes(a, b byte) byte {
+	var result byte
+	for b != 0 {
+		if b&1 != 0 {
+			result ^= a
+		}
+		carry := a&0x80 != 0
+		a <<= 1
+		if carry {
+			a ^= 0x1D
+		}
+		b >>= 1
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// QuietZone is the light border around a code, in modules, that scanners
+// need to find it
+const QuietZone = 4
+
+// PNG renders the code with each module scale pixels wide
+func (c *Code) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	side := (c.size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex((x+QuietZone)*scale+px, (y+QuietZone)*scale+py, 1)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code as a scalable image, one unit per module
+func (c *Code) SVG() []byte {
+	side := c.size + 2*QuietZone
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, side, side)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, side, side)
+	c.Runs(func(x, y, width int) {
+		fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", x+QuietZone, y+QuietZone, width, width)
+	})
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
+
+// Runs calls draw for every horizontal run of dark modules, with the column
+// and row it starts at and its width
+func (c *Code) Runs(draw func(x, y, width int)) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; {
+			if !c.modules[y][x] {
+				x++
+				continue
+			}
+			start := x
+			for x < c.size && c.modules[y][x] {
+				x++
+			}
+			draw(start, y, x-start)
+		}
+	}
+}
@@ -1,17 +1,87 @@
 package repository
 
 import (
+	"bytes"
+	"encoding/json"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/rixtrayker/ticketing-system/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// assetDescendants selects the IDs of the components of an asset at any
+// depth. UNION stops the recursion should parent references ever form a
+// cycle.
+const assetDescendants = `WITH RECURSIVE descendants AS (
+	SELECT id FROM assets WHERE parent_id = ? AND deleted_at IS NULL
+	UNION
+	SELECT a.id FROM assets a JOIN descendants d ON a.parent_id = d.id WHERE a.deleted_at IS NULL
+) SELECT id FROM descendants`
+
+// metadataComparisons maps metadata operators onto JSON path comparisons
+var metadataComparisons = map[models.MetadataOperator]string{
+	models.MetadataOperatorEquals: "==",
+	models.MetadataOperatorGT:     ">",
+	models.MetadataOperatorGTE:    ">=",
+	models.MetadataOperatorLT:     "<",
+	models.MetadataOperatorLTE:    "<=",
+}
+
+// metadataCondition returns the condition on the metadata column of assets
+// matching filter and its argument. CONTAINS uses @> and the other operators
+// a JSON path predicate with @@, both of which the GIN index on metadata
+// serves. Keys and values are JSON encoded into the path, so they cannot
+// change its meaning.
+func metadataCondition(filter models.MetadataFilter) (string, string, error) {
+	if filter.Operator == models.MetadataOperatorContains {
+		document := json.RawMessage(filter.Value)
+		for i := len(filter.Path) - 1; i >= 0; i-- {
+			wrapped, err := json.Marshal(map[string]json.RawMessage{filter.Path[i]: document})
+			if err != nil {
+				return "", "", err
+			}
+			document = wrapped
+		}
+		return "metadata @> ?::jsonb", string(document), nil
+	}
+
+	path := "$"
+	for _, key := range filter.Path {
+		quoted, err := json.Marshal(key)
+		if err != nil {
+			return "", "", err
+		}
+		path += "." + string(quoted)
+	}
+	if filter.Operator == models.MetadataOperatorExists {
+		return "metadata @@ ?::jsonpath", "exists(" + path + ")", nil
+	}
+	var value bytes.Buffer
+	if err := json.Compact(&value, filter.Value); err != nil {
+		return "", "", err
+	}
+	return "metadata @@ ?::jsonpath", path + " " + metadataComparisons[filter.Operator] + " " + value.String(), nil
+}
+
 type AssetRepository interface {
+	WithTx(tx *gorm.DB) AssetRepository
 	Create(asset *models.Asset) error
 	GetByID(id uuid.UUID) (*models.Asset, error)
-	GetAll(filter *models.AssetFilter) ([]*models.Asset, error)
+	// GetForUpdate returns the asset with its row locked until the
+	// transaction ends
+	GetForUpdate(id uuid.UUID) (*models.Asset, error)
+	GetByQRCode(code string) (*models.Asset, error)
+	GetByIDs(ids []uuid.UUID) ([]*models.Asset, error)
+	GetAll(filter *models.AssetFilter, page *models.PageArgs) (*models.Page[*models.Asset], error)
+	// GetChildren returns the assets that are direct components of id
+	GetChildren(id uuid.UUID) ([]*models.Asset, error)
+	// GetDescendants returns the components of id at any depth
+	GetDescendants(id uuid.UUID) ([]*models.Asset, error)
 	Update(asset *models.Asset) error
 	Delete(id uuid.UUID) error
+	UpdateMaintenanceDates(id uuid.UUID, last, next *time.Time) error
 }
 
 type assetRepository struct {
@@ -22,6 +92,10 @@ func NewAssetRepository(db *gorm.DB) AssetRepository {
 	return &assetRepository{db: db}
 }
 
+func (r *assetRepository) WithTx(tx *gorm.DB) AssetRepository {
+	return &assetRepository{db: tx}
+}
+
 func (r *assetRepository) Create(asset *models.Asset) error {
 	return r.db.Create(asset).Error
 }
@@ -35,8 +109,36 @@ func (r *assetRepository) GetByID(id uuid.UUID) (*models.Asset, error) {
 	return &asset, nil
 }
 
-func (r *assetRepository) GetAll(filter *models.AssetFilter) ([]*models.Asset, error) {
+func (r *assetRepository) GetForUpdate(id uuid.UUID) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&asset, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// GetByQRCode returns the asset with the given QR code, ignoring case
+func (r *assetRepository) GetByQRCode(code string) (*models.Asset, error) {
+	var asset models.Asset
+	err := r.db.Preload("MaintenanceHistory").Preload("Tickets").First(&asset, "UPPER(qr_code) = UPPER(?)", code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// GetByIDs returns the assets among ids that exist, in no particular order
+func (r *assetRepository) GetByIDs(ids []uuid.UUID) ([]*models.Asset, error) {
 	var assets []*models.Asset
+	if len(ids) == 0 {
+		return assets, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&assets).Error
+	return assets, err
+}
+
+func (r *assetRepository) GetAll(filter *models.AssetFilter, page *models.PageArgs) (*models.Page[*models.Asset], error) {
 	query := r.db.Model(&models.Asset{})
 
 	if filter != nil {
@@ -49,16 +151,52 @@ func (r *assetRepository) GetAll(filter *models.AssetFilter) ([]*models.Asset, e
 		if filter.Location != nil {
 			query = query.Where("location ILIKE ?", "%"+*filter.Location+"%")
 		}
+		if filter.PlaceID != nil {
+			query = query.Where("location_id IN (?)", gorm.Expr(locationSubtree, *filter.PlaceID))
+		}
+		if filter.ParentID != nil {
+			query = query.Where("id IN (?)", gorm.Expr(assetDescendants, *filter.ParentID))
+		}
+		for _, metadata := range filter.Metadata {
+			condition, arg, err := metadataCondition(metadata)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where(condition, arg)
+		}
 	}
 
-	err := query.Find(&assets).Error
+	return paginate(listQuery[*models.Asset]{
+		filtered:     query,
+		base:         func(a *models.Asset) *models.Base { return &a.Base },
+		defaultOrder: models.OrderBy{Field: models.SortFieldCreatedAt},
+	}, page)
+}
+
+func (r *assetRepository) GetChildren(id uuid.UUID) ([]*models.Asset, error) {
+	var assets []*models.Asset
+	err := r.db.Where("parent_id = ?", id).Order("name").Find(&assets).Error
+	return assets, err
+}
+
+func (r *assetRepository) GetDescendants(id uuid.UUID) ([]*models.Asset, error) {
+	var assets []*models.Asset
+	err := r.db.Where("id IN (?)", gorm.Expr(assetDescendants, id)).Order("name").Find(&assets).Error
 	return assets, err
 }
 
 func (r *assetRepository) Update(asset *models.Asset) error {
-	return r.db.Save(asset).Error
+	return r.db.Omit(clause.Associations).Save(asset).Error
 }
 
 func (r *assetRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Asset{}, id).Error
+}
+
+// UpdateMaintenanceDates sets the asset's last and next maintenance dates
+func (r *assetRepository) UpdateMaintenanceDates(id uuid.UUID, last, next *time.Time) error {
+	return r.db.Model(&models.Asset{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_maintenance_date": last,
+		"next_maintenance_date": next,
+	}).Error
 } 
\ No newline at end of file
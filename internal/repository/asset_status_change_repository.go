@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssetStatusChangeRepository interface {
+	WithTx(tx *gorm.DB) AssetStatusChangeRepository
+	Create(change *models.AssetStatusChange) error
+	// GetByAsset returns the asset's status changes, most recent first
+	GetByAsset(assetID uuid.UUID) ([]*models.AssetStatusChange, error)
+	// Latest returns the asset's most recent status change, or nil when it
+	// has none
+	Latest(assetID uuid.UUID) (*models.AssetStatusChange, error)
+	// GetBetween returns the asset's status changes in (from, to), oldest
+	// first, preceded by the last change at or before from
+	GetBetween(assetID uuid.UUID, from, to time.Time) ([]*models.AssetStatusChange, error)
+}
+
+type assetStatusChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewAssetStatusChangeRepository(db *gorm.DB) AssetStatusChangeRepository {
+	return &assetStatusChangeRepository{db: db}
+}
+
+func (r *assetStatusChangeRepository) WithTx(tx *gorm.DB) AssetStatusChangeRepository {
+	return &assetStatusChangeRepository{db: tx}
+}
+
+func (r *assetStatusChangeRepository) Create(change *models.AssetStatusChange) error {
+	return r.db.Omit(clause.Associations).Create(change).Error
+}
+
+func (r *assetStatusChangeRepository) GetByAsset(assetID uuid.UUID) ([]*models.AssetStatusChange, error) {
+	var changes []*models.AssetStatusChange
+	err := r.db.Preload("ChangedBy").
+		Where("asset_id = ?", assetID).
+		Order("changed_at DESC").Order("created_at DESC").
+		Find(&changes).Error
+	return changes, err
+}
+
+func (r *assetStatusChangeRepository) Latest(assetID uuid.UUID) (*models.AssetStatusChange, error) {
+	var changes []*models.AssetStatusChange
+	err := r.db.Where("asset_id = ?", assetID).
+		Order("changed_at DESC").Order("created_at DESC").
+		Limit(1).
+		Find(&changes).Error
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	return changes[0], nil
+}
+
+func (r *assetStatusChangeRepository) GetBetween(assetID uuid.UUID, from, to time.Time) ([]*models.AssetStatusChange, error) {
+	var initial []*models.AssetStatusChange
+	err := r.db.Where("asset_id = ? AND changed_at <= ?", assetID, from).
+		Order("changed_at DESC").Order("created_at DESC").
+		Limit(1).
+		Find(&initial).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*models.AssetStatusChange
+	err = r.db.Where("asset_id = ? AND changed_at > ? AND changed_at < ?", assetID, from, to).
+		Order("changed_at").Order("created_at").
+		Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(initial, changes...), nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssetTypeSchemaRepository interface {
+	WithTx(tx *gorm.DB) AssetTypeSchemaRepository
+	// GetByType returns the schema for assets of assetType, or nil when
+	// there is none
+	GetByType(assetType models.AssetType) (*models.AssetTypeSchema, error)
+	GetAll() ([]*models.AssetTypeSchema, error)
+	Create(schema *models.AssetTypeSchema) error
+	Update(schema *models.AssetTypeSchema) error
+	Delete(schema *models.AssetTypeSchema) error
+}
+
+type assetTypeSchemaRepository struct {
+	db *gorm.DB
+}
+
+func NewAssetTypeSchemaRepository(db *gorm.DB) AssetTypeSchemaRepository {
+	return &assetTypeSchemaRepository{db: db}
+}
+
+func (r *assetTypeSchemaRepository) WithTx(tx *gorm.DB) AssetTypeSchemaRepository {
+	return &assetTypeSchemaRepository{db: tx}
+}
+
+func (r *assetTypeSchemaRepository) GetByType(assetType models.AssetType) (*models.AssetTypeSchema, error) {
+	var schemas []*models.AssetTypeSchema
+	if err := r.db.Where("type = ?", assetType).Limit(1).Find(&schemas).Error; err != nil {
+		return nil, err
+	}
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	return schemas[0], nil
+}
+
+func (r *assetTypeSchemaRepository) GetAll() ([]*models.AssetTypeSchema, error) {
+	var schemas []*models.AssetTypeSchema
+	err := r.db.Order("type").Find(&schemas).Error
+	return schemas, err
+}
+
+func (r *assetTypeSchemaRepository) Create(schema *models.AssetTypeSchema) error {
+	return r.db.Omit(clause.Associations).Create(schema).Error
+}
+
+func (r *assetTypeSchemaRepository) Update(schema *models.AssetTypeSchema) error {
+	return r.db.Omit(clause.Associations).Save(schema).Error
+}
+
+func (r *assetTypeSchemaRepository) Delete(schema *models.AssetTypeSchema) error {
+	return r.db.Delete(schema).Error
+}
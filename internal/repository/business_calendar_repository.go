@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BusinessCalendarRepository interface {
+	WithTx(tx *gorm.DB) BusinessCalendarRepository
+	Create(calendar *models.BusinessCalendar) error
+	GetByID(id uuid.UUID) (*models.BusinessCalendar, error)
+	GetAll() ([]*models.BusinessCalendar, error)
+	// ForLocation returns the calendar covering location, falling back to
+	// the default calendar, or nil when neither exists
+	ForLocation(location string) (*models.BusinessCalendar, error)
+	// Covering returns the calendars other than excludeID that cover any
+	// of locations
+	Covering(locations []string, excludeID uuid.UUID) ([]*models.BusinessCalendar, error)
+	GetDefault() (*models.BusinessCalendar, error)
+	Update(calendar *models.BusinessCalendar) error
+	Delete(id uuid.UUID) error
+}
+
+type businessCalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessCalendarRepository(db *gorm.DB) BusinessCalendarRepository {
+	return &businessCalendarRepository{db: db}
+}
+
+func (r *businessCalendarRepository) WithTx(tx *gorm.DB) BusinessCalendarRepository {
+	return &businessCalendarRepository{db: tx}
+}
+
+func (r *businessCalendarRepository) Create(calendar *models.BusinessCalendar) error {
+	return r.db.Omit(clause.Associations).Create(calendar).Error
+}
+
+func (r *businessCalendarRepository) GetByID(id uuid.UUID) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	if err := r.db.First(&calendar, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *businessCalendarRepository) GetAll() ([]*models.BusinessCalendar, error) {
+	var calendars []*models.BusinessCalendar
+	err := r.db.Order("is_default DESC").Order("name").Find(&calendars).Error
+	return calendars, err
+}
+
+// ForLocation prefers a calendar listing the location over the default one
+func (r *businessCalendarRepository) ForLocation(location string) (*models.BusinessCalendar, error) {
+	contains, err := json.Marshal([]string{location})
+	if err != nil {
+		return nil, err
+	}
+	var calendar models.BusinessCalendar
+	err = r.db.Where("locations @> ?::jsonb OR is_default", string(contains)).
+		Order("is_default").
+		First(&calendar).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *businessCalendarRepository) Covering(locations []string, excludeID uuid.UUID) ([]*models.BusinessCalendar, error) {
+	var calendars []*models.BusinessCalendar
+	if len(locations) == 0 {
+		return calendars, nil
+	}
+	err := r.db.Where("id <> ?", excludeID).
+		Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(locations) AS location WHERE location IN ?)", locations).
+		Find(&calendars).Error
+	return calendars, err
+}
+
+func (r *businessCalendarRepository) GetDefault() (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	err := r.db.Where("is_default").First(&calendar).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *businessCalendarRepository) Update(calendar *models.BusinessCalendar) error {
+	return r.db.Omit(clause.Associations).Save(calendar).Error
+}
+
+func (r *businessCalendarRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.BusinessCalendar{}, "id = ?", id).Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentRepository interface {
+	WithTx(tx *gorm.DB) CommentRepository
+	Create(comment *models.Comment) error
+	GetByID(id uuid.UUID) (*models.Comment, error)
+	GetByTicketID(ticketID uuid.UUID, includeInternal bool) ([]*models.Comment, error)
+	Update(comment *models.Comment) error
+	Delete(id uuid.UUID) error
+	CreateRevision(revision *models.CommentRevision) error
+	ReplaceMentions(comment *models.Comment, users []*models.User) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) WithTx(tx *gorm.DB) CommentRepository {
+	return &commentRepository{db: tx}
+}
+
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return r.db.Omit(clause.Associations).Create(comment).Error
+}
+
+func (r *commentRepository) GetByID(id uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	err := r.preload(r.db).First(&comment, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) GetByTicketID(ticketID uuid.UUID, includeInternal bool) ([]*models.Comment, error) {
+	var comments []*models.Comment
+	query := r.preload(r.db).Where("ticket_id = ?", ticketID)
+	if !includeInternal {
+		query = query.Where("internal = ?", false)
+	}
+	err := query.Order("created_at ASC").Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) Update(comment *models.Comment) error {
+	return r.db.Omit(clause.Associations).Save(comment).Error
+}
+
+func (r *commentRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Comment{}, id).Error
+}
+
+func (r *commentRepository) CreateRevision(revision *models.CommentRevision) error {
+	return r.db.Omit(clause.Associations).Create(revision).Error
+}
+
+func (r *commentRepository) ReplaceMentions(comment *models.Comment, users []*models.User) error {
+	return r.db.Model(comment).Association("Mentions").Replace(users)
+}
+
+func (r *commentRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").
+		Preload("Mentions").
+		Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Revisions.EditedBy")
+}
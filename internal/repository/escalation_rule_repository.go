@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxEscalationBatch bounds the tickets a rule escalates per pass
+const maxEscalationBatch = 100
+
+type EscalationRuleRepository interface {
+	WithTx(tx *gorm.DB) EscalationRuleRepository
+	Create(rule *models.EscalationRule) error
+	GetByID(id uuid.UUID) (*models.EscalationRule, error)
+	GetAll() ([]*models.EscalationRule, error)
+	GetActive() ([]*models.EscalationRule, error)
+	Update(rule *models.EscalationRule) error
+	Delete(id uuid.UUID) error
+	// Candidates returns the IDs of tickets matching rule at now that it
+	// has not escalated yet, oldest first
+	Candidates(rule *models.EscalationRule, now time.Time) ([]uuid.UUID, error)
+	// RecordEscalation stores that a rule escalated a ticket, reporting
+	// false when it already had
+	RecordEscalation(escalation *models.TicketEscalation) (bool, error)
+}
+
+type escalationRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewEscalationRuleRepository(db *gorm.DB) EscalationRuleRepository {
+	return &escalationRuleRepository{db: db}
+}
+
+func (r *escalationRuleRepository) WithTx(tx *gorm.DB) EscalationRuleRepository {
+	return &escalationRuleRepository{db: tx}
+}
+
+func (r *escalationRuleRepository) Create(rule *models.EscalationRule) error {
+	return r.db.Omit(clause.Associations).Create(rule).Error
+}
+
+func (r *escalationRuleRepository) GetByID(id uuid.UUID) (*models.EscalationRule, error) {
+	var rule models.EscalationRule
+	if err := r.db.Preload("EscalateTo").Preload("CreatedBy").First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *escalationRuleRepository) GetAll() ([]*models.EscalationRule, error) {
+	var rules []*models.EscalationRule
+	err := r.db.Preload("EscalateTo").Preload("CreatedBy").Order("name").Find(&rules).Error
+	return rules, err
+}
+
+func (r *escalationRuleRepository) GetActive() ([]*models.EscalationRule, error) {
+	var rules []*models.EscalationRule
+	err := r.db.Preload("EscalateTo").Preload("CreatedBy").Where("active").Order("created_at").Find(&rules).Error
+	return rules, err
+}
+
+func (r *escalationRuleRepository) Update(rule *models.EscalationRule) error {
+	return r.db.Omit(clause.Associations).Save(rule).Error
+}
+
+func (r *escalationRuleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.EscalationRule{}, "id = ?", id).Error
+}
+
+func (r *escalationRuleRepository) Candidates(rule *models.EscalationRule, now time.Time) ([]uuid.UUID, error) {
+	query := r.db.Model(&models.Ticket{}).
+		Where("NOT EXISTS (SELECT 1 FROM ticket_escalations e WHERE e.ticket_id = tickets.id AND e.rule_id = ?)", rule.ID)
+	if rule.Priority != nil {
+		query = query.Where("priority = ?", *rule.Priority)
+	}
+
+	cutoff := now.Add(-rule.After())
+	switch rule.Condition {
+	case models.EscalationConditionUnassigned:
+		query = query.Where("status = ? AND assigned_to_id IS NULL AND created_at <= ?", models.TicketStatusOpen, cutoff)
+	case models.EscalationConditionNoUpdate:
+		query = query.Where("status IN ? AND updated_at <= ?",
+			[]models.TicketStatus{models.TicketStatusOpen, models.TicketStatusInProgress}, cutoff)
+	case models.EscalationConditionSLAAtRisk:
+		query = filterSLA(query, models.SLAStateAtRisk, now)
+	case models.EscalationConditionSLABreached:
+		query = filterSLA(query.Where("status IN ?", activeTicketStatuses), models.SLAStateBreached, now)
+	default:
+		return nil, nil
+	}
+
+	var ids []uuid.UUID
+	err := query.Order("created_at").Limit(maxEscalationBatch).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *escalationRuleRepository) RecordEscalation(escalation *models.TicketEscalation) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(escalation)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InboundEmailRepository interface {
+	Create(email *models.InboundEmail) error
+	// Received reports whether an email with the Message-ID was processed
+	Received(messageID string) (bool, error)
+	// FindTicket returns the ticket of the first processed email among
+	// messageIDs, or nil when none was processed
+	FindTicket(messageIDs []string) (*uuid.UUID, error)
+}
+
+type inboundEmailRepository struct {
+	db *gorm.DB
+}
+
+func NewInboundEmailRepository(db *gorm.DB) InboundEmailRepository {
+	return &inboundEmailRepository{db: db}
+}
+
+func (r *inboundEmailRepository) Create(email *models.InboundEmail) error {
+	return r.db.Omit(clause.Associations).Create(email).Error
+}
+
+func (r *inboundEmailRepository) Received(messageID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.InboundEmail{}).Where("message_id = ?", messageID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *inboundEmailRepository) FindTicket(messageIDs []string) (*uuid.UUID, error) {
+	if len(messageIDs) == 0 {
+		return nil, nil
+	}
+	var email models.InboundEmail
+	err := r.db.Where("message_id IN ?", messageIDs).Order("created_at DESC").First(&email).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &email.TicketID, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// locationSubtree selects the IDs of a location and of every location under
+// it. UNION rather than UNION ALL stops the recursion should rows ever form
+// a cycle.
+const locationSubtree = `WITH RECURSIVE subtree AS (
+	SELECT id FROM locations WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT l.id FROM locations l JOIN subtree s ON l.parent_id = s.id WHERE l.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+type LocationRepository interface {
+	WithTx(tx *gorm.DB) LocationRepository
+	Create(location *models.Location) error
+	GetByID(id uuid.UUID) (*models.Location, error)
+	// GetChildren returns the locations directly under parentID, or the
+	// sites when it is nil, by name
+	GetChildren(parentID *uuid.UUID) ([]*models.Location, error)
+	// GetAncestors returns the location and the locations above it, root
+	// first
+	GetAncestors(id uuid.UUID) ([]*models.Location, error)
+	// FindChild returns the location named name, ignoring case, directly
+	// under parentID, or nil when there is none
+	FindChild(parentID *uuid.UUID, name string) (*models.Location, error)
+	// InUse reports whether locations or assets are placed in the location
+	InUse(id uuid.UUID) (bool, error)
+	Update(location *models.Location) error
+	Delete(id uuid.UUID) error
+}
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+func NewLocationRepository(db *gorm.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+func (r *locationRepository) WithTx(tx *gorm.DB) LocationRepository {
+	return &locationRepository{db: tx}
+}
+
+func (r *locationRepository) Create(location *models.Location) error {
+	return r.db.Omit(clause.Associations).Create(location).Error
+}
+
+func (r *locationRepository) GetByID(id uuid.UUID) (*models.Location, error) {
+	var location models.Location
+	if err := r.db.First(&location, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) GetChildren(parentID *uuid.UUID) ([]*models.Location, error) {
+	query := r.db.Order("name")
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var locations []*models.Location
+	err := query.Find(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) GetAncestors(id uuid.UUID) ([]*models.Location, error) {
+	var locations []*models.Location
+	err := r.db.Raw(`WITH RECURSIVE ancestors AS (
+		SELECT l.*, 0 AS depth FROM locations l WHERE l.id = ? AND l.deleted_at IS NULL
+		UNION
+		SELECT l.*, a.depth + 1 FROM locations l JOIN ancestors a ON l.id = a.parent_id WHERE l.deleted_at IS NULL
+	)
+	SELECT id, name, kind, parent_id, created_at, updated_at, deleted_at FROM ancestors ORDER BY depth DESC`, id).
+		Scan(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) FindChild(parentID *uuid.UUID, name string) (*models.Location, error) {
+	query := r.db.Where("LOWER(name) = LOWER(?)", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var locations []*models.Location
+	if err := query.Limit(1).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+	return locations[0], nil
+}
+
+func (r *locationRepository) InUse(id uuid.UUID) (bool, error) {
+	var inUse bool
+	err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM locations WHERE parent_id = ? AND deleted_at IS NULL)
+		OR EXISTS (SELECT 1 FROM assets WHERE location_id = ? AND deleted_at IS NULL)`, id, id).
+		Scan(&inUse).Error
+	return inUse, err
+}
+
+func (r *locationRepository) Update(location *models.Location) error {
+	return r.db.Omit(clause.Associations).Save(location).Error
+}
+
+func (r *locationRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Location{}, id).Error
+}
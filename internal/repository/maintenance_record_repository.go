@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MaintenanceRecordRepository interface {
+	WithTx(tx *gorm.DB) MaintenanceRecordRepository
+	Create(record *models.MaintenanceRecord) error
+	CreatePartUsages(usages []*models.PartUsage) error
+	GetByID(id uuid.UUID) (*models.MaintenanceRecord, error)
+	GetByAssetID(assetID uuid.UUID) ([]*models.MaintenanceRecord, error)
+}
+
+type maintenanceRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRecordRepository(db *gorm.DB) MaintenanceRecordRepository {
+	return &maintenanceRecordRepository{db: db}
+}
+
+func (r *maintenanceRecordRepository) WithTx(tx *gorm.DB) MaintenanceRecordRepository {
+	return &maintenanceRecordRepository{db: tx}
+}
+
+func (r *maintenanceRecordRepository) Create(record *models.MaintenanceRecord) error {
+	return r.db.Omit(clause.Associations).Create(record).Error
+}
+
+func (r *maintenanceRecordRepository) CreatePartUsages(usages []*models.PartUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).Create(usages).Error
+}
+
+func (r *maintenanceRecordRepository) GetByID(id uuid.UUID) (*models.MaintenanceRecord, error) {
+	var record models.MaintenanceRecord
+	err := r.db.Preload("Asset").Preload("PerformedBy").Preload("PartsUsed.Part").First(&record, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// GetByAssetID returns the asset's maintenance history, most recent first
+func (r *maintenanceRecordRepository) GetByAssetID(assetID uuid.UUID) ([]*models.MaintenanceRecord, error) {
+	var records []*models.MaintenanceRecord
+	err := r.db.Preload("Asset").Preload("PerformedBy").Preload("PartsUsed.Part").
+		Where("asset_id = ?", assetID).
+		Order("performed_at DESC").
+		Find(&records).Error
+	return records, err
+}
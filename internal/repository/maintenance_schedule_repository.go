@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MaintenanceScheduleRepository interface {
+	WithTx(tx *gorm.DB) MaintenanceScheduleRepository
+	Create(schedule *models.MaintenanceSchedule) error
+	GetByID(id uuid.UUID) (*models.MaintenanceSchedule, error)
+	GetAll(filter *models.MaintenanceScheduleFilter, page *models.PageArgs) (*models.Page[*models.MaintenanceSchedule], error)
+	Update(schedule *models.MaintenanceSchedule) error
+	Delete(id uuid.UUID) error
+	GetActive() ([]*models.MaintenanceSchedule, error)
+	// Claim locks an active schedule's row until the transaction ends and
+	// returns it with its asset and current ticket. It returns nil when the
+	// schedule is no longer active or another transaction holds it.
+	Claim(id uuid.UUID) (*models.MaintenanceSchedule, error)
+	NextDueForAsset(assetID uuid.UUID) (*time.Time, error)
+	// GetActiveForAsset returns the asset's schedules that are not completed
+	// or cancelled
+	GetActiveForAsset(assetID uuid.UUID) ([]*models.MaintenanceSchedule, error)
+}
+
+// activeMaintenanceStatuses are the statuses of schedules the scheduler still tracks
+var activeMaintenanceStatuses = []models.MaintenanceStatus{
+	models.MaintenanceStatusScheduled,
+	models.MaintenanceStatusInProgress,
+	models.MaintenanceStatusOverdue,
+}
+
+type maintenanceScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceScheduleRepository(db *gorm.DB) MaintenanceScheduleRepository {
+	return &maintenanceScheduleRepository{db: db}
+}
+
+func (r *maintenanceScheduleRepository) WithTx(tx *gorm.DB) MaintenanceScheduleRepository {
+	return &maintenanceScheduleRepository{db: tx}
+}
+
+func (r *maintenanceScheduleRepository) Create(schedule *models.MaintenanceSchedule) error {
+	return r.db.Omit(clause.Associations).Create(schedule).Error
+}
+
+func (r *maintenanceScheduleRepository) GetByID(id uuid.UUID) (*models.MaintenanceSchedule, error) {
+	var schedule models.MaintenanceSchedule
+	err := r.db.Preload("Asset").Preload("AssignedTo").Preload("Ticket").First(&schedule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *maintenanceScheduleRepository) GetAll(filter *models.MaintenanceScheduleFilter, page *models.PageArgs) (*models.Page[*models.MaintenanceSchedule], error) {
+	query := r.db.Model(&models.MaintenanceSchedule{})
+
+	if filter != nil {
+		if filter.AssetID != nil {
+			query = query.Where("asset_id = ?", *filter.AssetID)
+		}
+		if filter.AssignedToID != nil {
+			query = query.Where("assigned_to_id = ?", *filter.AssignedToID)
+		}
+		if filter.Status != nil {
+			query = query.Where("status = ?", *filter.Status)
+		}
+	}
+
+	return paginate(listQuery[*models.MaintenanceSchedule]{
+		filtered: query,
+		preload: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Asset").Preload("AssignedTo").Preload("Ticket")
+		},
+		base: func(s *models.MaintenanceSchedule) *models.Base { return &s.Base },
+		columns: map[models.SortField]sortColumn[*models.MaintenanceSchedule]{
+			models.SortFieldNextDue: {"next_due", func(s *models.MaintenanceSchedule) interface{} { return s.NextDue }},
+		},
+		defaultOrder: models.OrderBy{Field: models.SortFieldNextDue, Direction: &ascending},
+	}, page)
+}
+
+func (r *maintenanceScheduleRepository) Update(schedule *models.MaintenanceSchedule) error {
+	return r.db.Omit(clause.Associations).Save(schedule).Error
+}
+
+func (r *maintenanceScheduleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.MaintenanceSchedule{}, id).Error
+}
+
+// GetActive returns the schedules that are not completed or cancelled, with
+// their asset and current ticket
+func (r *maintenanceScheduleRepository) GetActive() ([]*models.MaintenanceSchedule, error) {
+	var schedules []*models.MaintenanceSchedule
+	err := r.db.Preload("Asset").Preload("Ticket").
+		Where("status IN ?", activeMaintenanceStatuses).
+		Order("next_due ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// Claim skips locked rows rather than waiting, so concurrent schedulers each
+// take different schedules
+func (r *maintenanceScheduleRepository) Claim(id uuid.UUID) (*models.MaintenanceSchedule, error) {
+	var schedules []*models.MaintenanceSchedule
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Asset").Preload("Ticket").
+		Where("id = ? AND status IN ?", id, activeMaintenanceStatuses).
+		Limit(1).
+		Find(&schedules).Error
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+	return schedules[0], nil
+}
+
+func (r *maintenanceScheduleRepository) GetActiveForAsset(assetID uuid.UUID) ([]*models.MaintenanceSchedule, error) {
+	var schedules []*models.MaintenanceSchedule
+	err := r.db.Where("asset_id = ? AND status IN ?", assetID, activeMaintenanceStatuses).
+		Order("next_due ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// NextDueForAsset returns the earliest due date among the asset's active
+// schedules, or nil when it has none
+func (r *maintenanceScheduleRepository) NextDueForAsset(assetID uuid.UUID) (*time.Time, error) {
+	var next sql.NullTime
+	err := r.db.Model(&models.MaintenanceSchedule{}).
+		Select("MIN(next_due)").
+		Where("asset_id = ? AND status IN ?", assetID, activeMaintenanceStatuses).
+		Row().Scan(&next)
+	if err != nil || !next.Valid {
+		return nil, err
+	}
+	return &next.Time, nil
+}
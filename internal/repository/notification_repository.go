@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	// Sent reports whether the user was already notified of the event
+	Sent(eventID, userID uuid.UUID) (bool, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create logs a sent notification. A notification already logged for the
+// event and user is kept.
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(notification).Error
+}
+
+func (r *notificationRepository) Sent(eventID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	WithTx(tx *gorm.DB) OutboxRepository
+	Add(published ...events.Event) error
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]*models.OutboxEvent, error)
+	MarkSent(id uuid.UUID, at time.Time) error
+	// MarkFailed records a failed attempt, the handlers the event has been
+	// delivered to so far and when to try again
+	MarkFailed(id uuid.UUID, retryAt time.Time, reason string, deliveredTo []string) error
+	// MarkDead gives up on an event, which is no longer claimed
+	MarkDead(id uuid.UUID, at time.Time, reason string, deliveredTo []string) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) WithTx(tx *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: tx}
+}
+
+// Add stores events for dispatch. Call it on the transaction making the
+// change the events describe.
+func (r *outboxRepository) Add(published ...events.Event) error {
+	if len(published) == 0 {
+		return nil
+	}
+	rows := make([]*models.OutboxEvent, 0, len(published))
+	for _, event := range published {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, &models.OutboxEvent{
+			Type:          string(event.Type),
+			Payload:       payload,
+			NextAttemptAt: time.Now(),
+			DeliveredTo:   datatypes.JSONSlice[string]{},
+		})
+	}
+	return r.db.Create(&rows).Error
+}
+
+// ClaimPending leases up to limit events that are due for an attempt, oldest
+// first. Claimed events are not handed out again until the lease expires, so
+// concurrent dispatchers never see the same event at once, and one whose
+// dispatcher died is retried.
+func (r *outboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]*models.OutboxEvent, error) {
+	var claimed []*models.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?", now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&claimed).Error
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, len(claimed))
+		for i, event := range claimed {
+			ids[i] = event.ID
+			event.Attempts++
+		}
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *outboxRepository) MarkSent(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sent_at": at, "last_error": ""}).Error
+}
+
+func (r *outboxRepository) MarkFailed(id uuid.UUID, retryAt time.Time, reason string, deliveredTo []string) error {
+	return r.db.Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"next_attempt_at": retryAt,
+			"last_error":      reason,
+			"delivered_to":    datatypes.NewJSONSlice(deliveredTo),
+		}).Error
+}
+
+func (r *outboxRepository) MarkDead(id uuid.UUID, at time.Time, reason string, deliveredTo []string) error {
+	return r.db.Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"dead_at":      at,
+			"last_error":   reason,
+			"delivered_to": datatypes.NewJSONSlice(deliveredTo),
+		}).Error
+}
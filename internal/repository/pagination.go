@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPageSize is used when a list query does not ask for a size
+	DefaultPageSize = 20
+	// MaxPageSize is the largest page any list query returns
+	MaxPageSize = 100
+)
+
+var (
+	// ErrInvalidCursor is returned when an after cursor cannot be decoded
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrUnsupportedSort is returned when a list cannot be ordered by the requested field
+	ErrUnsupportedSort = errors.New("unsupported sort field")
+)
+
+// ascending is referenced by lists ordered oldest-first by default
+var ascending = models.SortDirectionAsc
+
+// sortColumn maps a sort field onto a column and reads its value from a row
+type sortColumn[T any] struct {
+	column string
+	value  func(T) interface{}
+}
+
+// listQuery describes how a repository paginates one of its lists
+type listQuery[T any] struct {
+	// filtered selects the rows of the list, without preloads
+	filtered *gorm.DB
+	// preload adds the associations loaded with each row
+	preload func(*gorm.DB) *gorm.DB
+	// base reads the common fields of a row; its ID is the ordering
+	// tie-breaker and its timestamps are always sortable
+	base func(T) *models.Base
+	// columns are the sort fields the list supports besides CREATED_AT and UPDATED_AT
+	columns map[models.SortField]sortColumn[T]
+	// defaultOrder is used when the caller does not choose an ordering
+	defaultOrder models.OrderBy
+}
+
+// column returns the sort column for field
+func (q listQuery[T]) column(field models.SortField) (sortColumn[T], bool) {
+	switch field {
+	case models.SortFieldCreatedAt:
+		return sortColumn[T]{"created_at", func(row T) interface{} { return q.base(row).CreatedAt }}, true
+	case models.SortFieldUpdatedAt:
+		return sortColumn[T]{"updated_at", func(row T) interface{} { return q.base(row).UpdatedAt }}, true
+	}
+	sort, ok := q.columns[field]
+	return sort, ok
+}
+
+// cursor is the decoded form of the opaque cursor string: the sort value
+// and the ID of the last row of a page
+type cursor struct {
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// paginate returns one page of the list using keyset pagination on the sort
+// column and id, so pages stay stable while rows are inserted
+func paginate[T any](q listQuery[T], args *models.PageArgs) (*models.Page[T], error) {
+	if args == nil {
+		args = &models.PageArgs{}
+	}
+	order := q.defaultOrder
+	if args.OrderBy != nil {
+		order.Field = args.OrderBy.Field
+		if args.OrderBy.Direction != nil {
+			order.Direction = args.OrderBy.Direction
+		}
+	}
+	sort, ok := q.column(order.Field)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSort, order.Field)
+	}
+	direction := models.SortDirectionDesc
+	if order.Direction != nil {
+		direction = *order.Direction
+	}
+
+	limit := DefaultPageSize
+	if args.First != nil {
+		limit = *args.First
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	var total int64
+	if err := q.filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	query := q.filtered.Session(&gorm.Session{})
+	if args.After != nil {
+		after, err := decodeCursor(order.Field, *args.After)
+		if err != nil {
+			return nil, err
+		}
+		value, err := cursorValue(order.Field, after.Value)
+		if err != nil {
+			return nil, err
+		}
+		op := "<"
+		if direction == models.SortDirectionAsc {
+			op = ">"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sort.column, op), value, after.ID)
+	}
+	if q.preload != nil {
+		query = q.preload(query)
+	}
+
+	var rows []T
+	err := query.
+		Order(fmt.Sprintf("%s %s, id %s", sort.column, direction, direction)).
+		Limit(limit + 1).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.Page[T]{TotalCount: total}
+	page.PageInfo.HasPreviousPage = args.After != nil
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.PageInfo.HasNextPage = true
+	}
+	page.Items = rows
+	page.Cursors = make([]string, len(rows))
+	for i, row := range rows {
+		page.Cursors[i] = encodeCursor(order.Field, sort.value(row), q.base(row).ID)
+	}
+	if len(rows) > 0 {
+		page.PageInfo.StartCursor = &page.Cursors[0]
+		page.PageInfo.EndCursor = &page.Cursors[len(rows)-1]
+	}
+	return page, nil
+}
+
+func encodeCursor(field models.SortField, value interface{}, id uuid.UUID) string {
+	c := cursor{ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	default:
+		c.Value = fmt.Sprint(v)
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(append([]byte(field+":"), b...))
+}
+
+// decodeCursor decodes a cursor, rejecting cursors issued for another ordering
+func decodeCursor(field models.SortField, s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	prefix, payload, ok := bytes.Cut(raw, []byte(":"))
+	if !ok || models.SortField(prefix) != field {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// cursorValue converts a cursor's sort value back into the column type
+func cursorValue(field models.SortField, value string) (interface{}, error) {
+	switch field {
+	case models.SortFieldCreatedAt, models.SortFieldUpdatedAt, models.SortFieldNextDue:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PartRepository interface {
+	WithTx(tx *gorm.DB) PartRepository
+	Create(part *models.Part) error
+	GetByID(id uuid.UUID) (*models.Part, error)
+	GetAll(filter *models.PartFilter, page *models.PageArgs) (*models.Page[*models.Part], error)
+	Update(part *models.Part) error
+	ChangeQuantity(id uuid.UUID, delta int) (bool, error)
+}
+
+type partRepository struct {
+	db *gorm.DB
+}
+
+func NewPartRepository(db *gorm.DB) PartRepository {
+	return &partRepository{db: db}
+}
+
+func (r *partRepository) WithTx(tx *gorm.DB) PartRepository {
+	return &partRepository{db: tx}
+}
+
+func (r *partRepository) Create(part *models.Part) error {
+	return r.db.Omit(clause.Associations).Create(part).Error
+}
+
+func (r *partRepository) GetByID(id uuid.UUID) (*models.Part, error) {
+	var part models.Part
+	err := r.db.Preload("ReorderTicket").First(&part, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &part, nil
+}
+
+func (r *partRepository) GetAll(filter *models.PartFilter, page *models.PageArgs) (*models.Page[*models.Part], error) {
+	query := r.db.Model(&models.Part{})
+
+	if filter != nil {
+		if filter.Location != nil {
+			query = query.Where("location ILIKE ?", "%"+*filter.Location+"%")
+		}
+		if filter.BelowMinimum != nil {
+			if *filter.BelowMinimum {
+				query = query.Where("quantity < minimum_quantity")
+			} else {
+				query = query.Where("quantity >= minimum_quantity")
+			}
+		}
+	}
+
+	return paginate(listQuery[*models.Part]{
+		filtered: query,
+		preload: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("ReorderTicket")
+		},
+		base:         func(p *models.Part) *models.Base { return &p.Base },
+		defaultOrder: models.OrderBy{Field: models.SortFieldCreatedAt},
+	}, page)
+}
+
+func (r *partRepository) Update(part *models.Part) error {
+	return r.db.Omit(clause.Associations).Save(part).Error
+}
+
+// ChangeQuantity adds delta (negative to take stock out) to a part's quantity
+// in a single conditional update. It reports false, changing nothing, when the
+// part does not exist or the change would make its stock negative.
+func (r *partRepository) ChangeQuantity(id uuid.UUID, delta int) (bool, error) {
+	result := r.db.Model(&models.Part{}).
+		Where("id = ? AND quantity + ? >= 0", id, delta).
+		UpdateColumn("quantity", gorm.Expr("quantity + ?", delta))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// headlineOptions wraps matched terms in <mark> tags for clients to render
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+// searchSources are the ranked subqueries searched for each type. Each selects
+// the record type, id, rank and the text used for the highlight.
+var searchSources = map[models.SearchableType]string{
+	models.SearchableTypeTicket: `SELECT 'TICKET' AS type, t.id, ts_rank(t.search_vector, q.query) AS rank,
+		t.title || ' ' || t.description AS body
+		FROM tickets t, q WHERE t.deleted_at IS NULL AND t.search_vector @@ q.query`,
+	models.SearchableTypeComment: `SELECT 'COMMENT' AS type, c.id, ts_rank(c.search_vector, q.query) AS rank,
+		c.content AS body
+		FROM comments c, q WHERE c.deleted_at IS NULL AND c.search_vector @@ q.query AND (c.internal = FALSE OR @internal)`,
+	models.SearchableTypeAsset: `SELECT 'ASSET' AS type, a.id, ts_rank(a.search_vector, q.query) AS rank,
+		a.name || ' ' || a.location || ' ' || a.qr_code AS body
+		FROM assets a, q WHERE a.deleted_at IS NULL AND a.search_vector @@ q.query`,
+}
+
+// searchOrder is the order subqueries are combined in
+var searchOrder = []models.SearchableType{
+	models.SearchableTypeTicket,
+	models.SearchableTypeComment,
+	models.SearchableTypeAsset,
+}
+
+type SearchRepository interface {
+	Search(query *models.SearchQuery) ([]*models.SearchHit, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+type searchRow struct {
+	Type      models.SearchableType
+	ID        uuid.UUID
+	Rank      float64
+	Highlight string
+}
+
+// Search ranks matching records across the requested types and loads them
+func (r *searchRepository) Search(query *models.SearchQuery) ([]*models.SearchHit, error) {
+	types := query.Types
+	if len(types) == 0 {
+		types = searchOrder
+	}
+	var sources []string
+	for _, t := range searchOrder {
+		if containsType(types, t) {
+			sources = append(sources, searchSources[t])
+		}
+	}
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	sql := `WITH q AS (SELECT websearch_to_tsquery('english', @query) AS query),
+	hits AS (` + strings.Join(sources, "\n\tUNION ALL\n\t") + `
+		ORDER BY rank DESC LIMIT @limit)
+	SELECT hits.type, hits.id, hits.rank, ts_headline('english', hits.body, q.query, @options) AS highlight
+	FROM hits, q
+	ORDER BY hits.rank DESC, hits.id`
+
+	var rows []searchRow
+	err := r.db.Raw(sql, map[string]interface{}{
+		"query":    query.Query,
+		"internal": query.IncludeInternal,
+		"limit":    query.Limit,
+		"options":  headlineOptions,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.load(rows)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]*models.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		item, ok := items[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, &models.SearchHit{
+			Type:      row.Type,
+			Rank:      row.Rank,
+			Highlight: row.Highlight,
+			Item:      item,
+		})
+	}
+	return hits, nil
+}
+
+// load fetches the records behind the search rows keyed by id
+func (r *searchRepository) load(rows []searchRow) (map[uuid.UUID]models.SearchResult, error) {
+	ids := make(map[models.SearchableType][]uuid.UUID)
+	for _, row := range rows {
+		ids[row.Type] = append(ids[row.Type], row.ID)
+	}
+	items := make(map[uuid.UUID]models.SearchResult, len(rows))
+
+	if len(ids[models.SearchableTypeTicket]) > 0 {
+		var tickets []*models.Ticket
+		err := r.db.Preload("AssignedTo").Preload("CreatedBy").Preload("Asset").
+			Find(&tickets, "id IN ?", ids[models.SearchableTypeTicket]).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tickets {
+			items[t.ID] = t
+		}
+	}
+	if len(ids[models.SearchableTypeComment]) > 0 {
+		var comments []*models.Comment
+		err := r.db.Preload("User").Preload("Mentions").
+			Find(&comments, "id IN ?", ids[models.SearchableTypeComment]).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			items[c.ID] = c
+		}
+	}
+	if len(ids[models.SearchableTypeAsset]) > 0 {
+		var assets []*models.Asset
+		if err := r.db.Find(&assets, "id IN ?", ids[models.SearchableTypeAsset]).Error; err != nil {
+			return nil, err
+		}
+		for _, a := range assets {
+			items[a.ID] = a
+		}
+	}
+	return items, nil
+}
+
+func containsType(types []models.SearchableType, t models.SearchableType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
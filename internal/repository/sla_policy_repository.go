@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SLAPolicyRepository interface {
+	WithTx(tx *gorm.DB) SLAPolicyRepository
+	Create(policy *models.SLAPolicy) error
+	GetByID(id uuid.UUID) (*models.SLAPolicy, error)
+	GetAll() ([]*models.SLAPolicy, error)
+	// Match returns the policy for tickets of priority on an asset of
+	// assetType, or nil when none applies
+	Match(priority models.TicketPriority, assetType *models.AssetType) (*models.SLAPolicy, error)
+	Update(policy *models.SLAPolicy) error
+	Delete(id uuid.UUID) error
+}
+
+type slaPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewSLAPolicyRepository(db *gorm.DB) SLAPolicyRepository {
+	return &slaPolicyRepository{db: db}
+}
+
+func (r *slaPolicyRepository) WithTx(tx *gorm.DB) SLAPolicyRepository {
+	return &slaPolicyRepository{db: tx}
+}
+
+func (r *slaPolicyRepository) Create(policy *models.SLAPolicy) error {
+	return r.db.Omit(clause.Associations).Create(policy).Error
+}
+
+func (r *slaPolicyRepository) GetByID(id uuid.UUID) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	if err := r.db.First(&policy, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *slaPolicyRepository) GetAll() ([]*models.SLAPolicy, error) {
+	var policies []*models.SLAPolicy
+	err := r.db.Order("priority DESC").Order("asset_type NULLS FIRST").Find(&policies).Error
+	return policies, err
+}
+
+// Match prefers the policy for the asset type over the one for every type
+func (r *slaPolicyRepository) Match(priority models.TicketPriority, assetType *models.AssetType) (*models.SLAPolicy, error) {
+	query := r.db.Where("priority = ?", priority)
+	if assetType != nil {
+		query = query.Where("asset_type = ? OR asset_type IS NULL", *assetType)
+	} else {
+		query = query.Where("asset_type IS NULL")
+	}
+
+	var policy models.SLAPolicy
+	err := query.Order("asset_type NULLS LAST").First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *slaPolicyRepository) Update(policy *models.SLAPolicy) error {
+	return r.db.Omit(clause.Associations).Save(policy).Error
+}
+
+func (r *slaPolicyRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.SLAPolicy{}, "id = ?", id).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockMovementRepository interface {
+	WithTx(tx *gorm.DB) StockMovementRepository
+	Create(movement *models.StockMovement) error
+	GetByPartID(partID uuid.UUID) ([]*models.StockMovement, error)
+}
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+func (r *stockMovementRepository) WithTx(tx *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{db: tx}
+}
+
+func (r *stockMovementRepository) Create(movement *models.StockMovement) error {
+	return r.db.Omit(clause.Associations).Create(movement).Error
+}
+
+// GetByPartID returns the part's stock ledger, most recent first
+func (r *stockMovementRepository) GetByPartID(partID uuid.UUID) ([]*models.StockMovement, error) {
+	var movements []*models.StockMovement
+	err := r.db.Preload("Part").Preload("Actor").
+		Where("part_id = ?", partID).
+		Order("created_at DESC").
+		Find(&movements).Error
+	return movements, err
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketEventRepository interface {
+	WithTx(tx *gorm.DB) TicketEventRepository
+	Create(events ...*models.TicketEvent) error
+	GetByTicketID(ticketID uuid.UUID) ([]*models.TicketEvent, error)
+}
+
+type ticketEventRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketEventRepository(db *gorm.DB) TicketEventRepository {
+	return &ticketEventRepository{db: db}
+}
+
+func (r *ticketEventRepository) WithTx(tx *gorm.DB) TicketEventRepository {
+	return &ticketEventRepository{db: tx}
+}
+
+func (r *ticketEventRepository) Create(events ...*models.TicketEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).Create(events).Error
+}
+
+func (r *ticketEventRepository) GetByTicketID(ticketID uuid.UUID) ([]*models.TicketEvent, error) {
+	var events []*models.TicketEvent
+	err := r.db.Preload("Actor").
+		Where("ticket_id = ?", ticketID).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
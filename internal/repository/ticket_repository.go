@@ -1,19 +1,40 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/rixtrayker/ticketing-system/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketRepository interface {
+	WithTx(tx *gorm.DB) TicketRepository
 	Create(ticket *models.Ticket) error
 	GetByID(id uuid.UUID) (*models.Ticket, error)
-	GetAll(filter *models.TicketFilter) ([]*models.Ticket, error)
+	// GetForUpdate returns the ticket with its row locked until the
+	// transaction ends
+	GetForUpdate(id uuid.UUID) (*models.Ticket, error)
+	GetAll(filter *models.TicketFilter, page *models.PageArgs) (*models.Page[*models.Ticket], error)
 	Update(ticket *models.Ticket) error
+	// MarkResponded records the first response to a ticket; later responses
+	// leave it unchanged
+	MarkResponded(id uuid.UUID, at time.Time) error
+	// GetActiveForAsset returns the asset's tickets that are still being
+	// worked on
+	GetActiveForAsset(assetID uuid.UUID) ([]*models.Ticket, error)
 	Delete(id uuid.UUID) error
 }
 
+// activeTicketStatuses are the statuses in which work on a ticket is still
+// expected
+var activeTicketStatuses = []models.TicketStatus{
+	models.TicketStatusOpen,
+	models.TicketStatusInProgress,
+	models.TicketStatusWaiting,
+}
+
 type ticketRepository struct {
 	db *gorm.DB
 }
@@ -22,22 +43,34 @@ func NewTicketRepository(db *gorm.DB) TicketRepository {
 	return &ticketRepository{db: db}
 }
 
+func (r *ticketRepository) WithTx(tx *gorm.DB) TicketRepository {
+	return &ticketRepository{db: tx}
+}
+
 func (r *ticketRepository) Create(ticket *models.Ticket) error {
 	return r.db.Create(ticket).Error
 }
 
 func (r *ticketRepository) GetByID(id uuid.UUID) (*models.Ticket, error) {
 	var ticket models.Ticket
-	err := r.db.Preload("AssignedTo").Preload("CreatedBy").Preload("Asset").Preload("Comments").First(&ticket, "id = ?", id).Error
+	err := r.db.Preload("AssignedTo").Preload("CreatedBy").Preload("Asset").Preload("SLAPolicy").Preload("Comments.User").First(&ticket, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &ticket, nil
 }
 
-func (r *ticketRepository) GetAll(filter *models.TicketFilter) ([]*models.Ticket, error) {
-	var tickets []*models.Ticket
-	query := r.db.Preload("AssignedTo").Preload("CreatedBy").Preload("Asset")
+func (r *ticketRepository) GetForUpdate(id uuid.UUID) (*models.Ticket, error) {
+	var ticket models.Ticket
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *ticketRepository) GetAll(filter *models.TicketFilter, page *models.PageArgs) (*models.Page[*models.Ticket], error) {
+	query := r.db.Model(&models.Ticket{})
 
 	if filter != nil {
 		if filter.Status != nil {
@@ -55,14 +88,75 @@ func (r *ticketRepository) GetAll(filter *models.TicketFilter) ([]*models.Ticket
 		if filter.AssetID != nil {
 			query = query.Where("asset_id = ?", *filter.AssetID)
 		}
+		if filter.PlaceID != nil {
+			query = query.Where("asset_id IN (SELECT id FROM assets WHERE deleted_at IS NULL AND location_id IN (?))", gorm.Expr(locationSubtree, *filter.PlaceID))
+		}
+		if filter.SLA != nil {
+			query = filterSLA(query, *filter.SLA, filter.SLAAsOf)
+		}
+		for _, metadata := range filter.Metadata {
+			condition, arg, err := metadataCondition(metadata)
+			if err != nil {
+				return nil, err
+			}
+			query = query.Where("asset_id IN (SELECT id FROM assets WHERE deleted_at IS NULL AND "+condition+")", arg)
+		}
 	}
 
-	err := query.Find(&tickets).Error
+	return paginate(listQuery[*models.Ticket]{
+		filtered: query,
+		preload: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("AssignedTo").Preload("CreatedBy").Preload("Asset").Preload("SLAPolicy")
+		},
+		base: func(t *models.Ticket) *models.Base { return &t.Base },
+		columns: map[models.SortField]sortColumn[*models.Ticket]{
+			models.SortFieldPriority: {"priority", func(t *models.Ticket) interface{} { return t.Priority }},
+		},
+		defaultOrder: models.OrderBy{Field: models.SortFieldCreatedAt},
+	}, page)
+}
+
+func (r *ticketRepository) GetActiveForAsset(assetID uuid.UUID) ([]*models.Ticket, error) {
+	var tickets []*models.Ticket
+	err := r.db.Where("asset_id = ? AND status IN ?", assetID, activeTicketStatuses).
+		Order("created_at").
+		Find(&tickets).Error
 	return tickets, err
 }
 
 func (r *ticketRepository) Update(ticket *models.Ticket) error {
-	return r.db.Save(ticket).Error
+	return r.db.Omit(clause.Associations).Save(ticket).Error
+}
+
+func (r *ticketRepository) MarkResponded(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.Ticket{}).
+		Where("id = ? AND responded_at IS NULL", id).
+		Update("responded_at", at).Error
+}
+
+// SQL conditions mirroring models.Ticket's SLA methods. The SLA clock of a
+// paused ticket stopped when it was paused. A change to either side belongs
+// in both, and in the cases of TestTicketSLA.
+const (
+	slaResponseBreached   = "response_due_at IS NOT NULL AND COALESCE(responded_at, sla_paused_at, @now) > response_due_at"
+	slaResolutionBreached = "resolution_due_at IS NOT NULL AND status <> 'CANCELLED' AND COALESCE(resolved_at, sla_paused_at, @now) > resolution_due_at"
+	slaAtRisk             = `sla_paused_at IS NULL AND status IN ('OPEN', 'IN_PROGRESS', 'WAITING') AND EXISTS (
+		SELECT 1 FROM sla_policies p WHERE p.id = tickets.sla_policy_id AND (
+			(responded_at IS NULL AND response_due_at - p.response_minutes * INTERVAL '1 minute' * @fraction <= @now) OR
+			(resolved_at IS NULL AND resolution_due_at - p.resolution_minutes * INTERVAL '1 minute' * @fraction <= @now)))`
+)
+
+// filterSLA narrows query to tickets in the given SLA state at now
+func filterSLA(query *gorm.DB, state models.SLAState, now time.Time) *gorm.DB {
+	args := map[string]interface{}{"now": now, "fraction": models.SLAAtRiskFraction}
+	breached := "((" + slaResponseBreached + ") OR (" + slaResolutionBreached + "))"
+	switch state {
+	case models.SLAStateBreached:
+		return query.Where(breached, args)
+	case models.SLAStateAtRisk:
+		return query.Where("NOT "+breached+" AND "+slaAtRisk, args)
+	}
+	return query
 }
 
 func (r *ticketRepository) Delete(id uuid.UUID) error {
@@ -10,7 +10,8 @@ type UserRepository interface {
 	Create(user *models.User) error
 	GetByID(id uuid.UUID) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
-	GetAll(filter *models.UserFilter) ([]*models.User, error)
+	FindByName(name string) ([]*models.User, error)
+	GetAll(filter *models.UserFilter, page *models.PageArgs) (*models.Page[*models.User], error)
 	Update(user *models.User) error
 	Delete(id uuid.UUID) error
 }
@@ -45,18 +46,29 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) GetAll(filter *models.UserFilter) ([]*models.User, error) {
+func (r *userRepository) FindByName(name string) ([]*models.User, error) {
 	var users []*models.User
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) GetAll(filter *models.UserFilter, page *models.PageArgs) (*models.Page[*models.User], error) {
 	query := r.db.Model(&models.User{})
 
 	if filter != nil {
 		if filter.Role != nil {
 			query = query.Where("role = ?", *filter.Role)
 		}
+		if filter.Email != nil {
+			query = query.Where("email ILIKE ?", "%"+*filter.Email+"%")
+		}
 	}
 
-	err := query.Find(&users).Error
-	return users, err
+	return paginate(listQuery[*models.User]{
+		filtered:     query,
+		base:         func(u *models.User) *models.Base { return &u.Base },
+		defaultOrder: models.OrderBy{Field: models.SortFieldCreatedAt},
+	}, page)
 }
 
 func (r *userRepository) Update(user *models.User) error {
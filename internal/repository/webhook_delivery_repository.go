@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookDeliveryRepository interface {
+	Enqueue(deliveries ...*models.WebhookDelivery) error
+	GetByID(id uuid.UUID) (*models.WebhookDelivery, error)
+	GetByWebhookID(webhookID uuid.UUID, filter *models.WebhookDeliveryFilter, page *models.PageArgs) (*models.Page[*models.WebhookDelivery], error)
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+// Enqueue stores new deliveries. An event already queued for a webhook is
+// skipped, so handing the same outbox event over twice is harmless.
+func (r *webhookDeliveryRepository) Enqueue(deliveries ...*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error
+}
+
+func (r *webhookDeliveryRepository) GetByID(id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Preload("Webhook").Preload("RedeliveryOf").First(&delivery, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetByWebhookID returns the delivery log of a webhook, newest first by default
+func (r *webhookDeliveryRepository) GetByWebhookID(webhookID uuid.UUID, filter *models.WebhookDeliveryFilter, page *models.PageArgs) (*models.Page[*models.WebhookDelivery], error) {
+	query := r.db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+
+	if filter != nil && filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	return paginate(listQuery[*models.WebhookDelivery]{
+		filtered: query,
+		preload: func(db *gorm.DB) *gorm.DB {
+			return db.Preload("Webhook").Preload("RedeliveryOf")
+		},
+		base:         func(d *models.WebhookDelivery) *models.Base { return &d.Base },
+		defaultOrder: models.OrderBy{Field: models.SortFieldCreatedAt},
+	}, page)
+}
+
+// ClaimDue leases up to limit pending deliveries whose next attempt is due,
+// oldest first, counting the attempt about to be made. A claimed delivery is
+// not handed out again until the lease expires.
+func (r *webhookDeliveryRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	var claimed []*models.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&claimed).Error
+		if err != nil || len(claimed) == 0 {
+			return err
+		}
+		ids := make([]uuid.UUID, len(claimed))
+		for i, delivery := range claimed {
+			ids[i] = delivery.ID
+			delivery.Attempts++
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"attempts":        gorm.Expr("attempts + 1"),
+				"next_attempt_at": now.Add(lease),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (r *webhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	return r.db.Omit(clause.Associations).Save(delivery).Error
+}
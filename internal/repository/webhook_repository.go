@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	Create(webhook *models.Webhook) error
+	GetByID(id uuid.UUID) (*models.Webhook, error)
+	GetAll() ([]*models.Webhook, error)
+	GetSubscribed(eventType string) ([]*models.Webhook, error)
+	Update(webhook *models.Webhook) error
+	Delete(id uuid.UUID) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(webhook *models.Webhook) error {
+	return r.db.Omit(clause.Associations).Create(webhook).Error
+}
+
+func (r *webhookRepository) GetByID(id uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.db.Preload("CreatedBy").First(&webhook, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) GetAll() ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := r.db.Preload("CreatedBy").Order("created_at ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetSubscribed returns the active webhooks subscribed to eventType
+func (r *webhookRepository) GetSubscribed(eventType string) ([]*models.Webhook, error) {
+	contains, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []*models.Webhook
+	err = r.db.Where("active AND event_types @> ?::jsonb", string(contains)).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) Update(webhook *models.Webhook) error {
+	return r.db.Omit(clause.Associations).Save(webhook).Error
+}
+
+func (r *webhookRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Webhook{}, "id = ?", id).Error
+}
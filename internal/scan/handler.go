@@ -0,0 +1,258 @@
+// Package scan serves the HTTP side of asset QR codes: code images, printable
+// label sheets and the landing route a scanned code points at.
+package scan
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/labels"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/qrcode"
+	"github.com/rixtrayker/ticketing-system/internal/service"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultScale = 8
+	maxScale     = 40
+	// maxLabels bounds the assets on one label sheet request
+	maxLabels = 200
+)
+
+//go:embed landing.html
+var landingHTML string
+
+// landingPage signs a user in and reports a problem with the scanned asset
+// through the scan route and the GraphQL API. It renders the scanned code.
+var landingPage = template.Must(template.New("landing").Parse(landingHTML))
+
+// Handler serves the QR code routes. Every route requires an authenticated
+// user, resolved by the auth middleware in front of it, except that a
+// browser opening the scan route without one gets the landing page.
+type Handler struct {
+	assets    service.AssetService
+	publicURL string
+	logger    *log.Logger
+	mux       *http.ServeMux
+	anonymous *http.ServeMux
+}
+
+// NewHandler returns the QR code routes. publicURL is the externally
+// reachable address of the server; codes encode the scan route under it.
+func NewHandler(assets service.AssetService, publicURL string, logger *log.Logger) *Handler {
+	h := &Handler{
+		assets:    assets,
+		publicURL: strings.TrimRight(publicURL, "/"),
+		logger:    logger,
+		mux:       http.NewServeMux(),
+		anonymous: http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /assets/{id}/qr.png", h.image)
+	h.mux.HandleFunc("GET /assets/{id}/qr.svg", h.image)
+	h.mux.HandleFunc("GET /assets/labels.pdf", h.labelSheet)
+	h.mux.HandleFunc("GET /scan/{code}", h.scan)
+	h.anonymous.HandleFunc("GET /scan/{code}", h.landing)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := auth.UserFromContext(r.Context()); ok {
+		h.mux.ServeHTTP(w, r)
+		return
+	}
+	// a phone's browser sends no bearer token, so a scanned code opens a
+	// page that signs in and calls the scan route itself
+	if _, pattern := h.anonymous.Handler(r); pattern != "" && strings.Contains(r.Header.Get("Accept"), "text/html") {
+		h.anonymous.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "Authentication required", http.StatusUnauthorized)
+}
+
+// ScanURL returns the address a printed code for the asset points at
+func (h *Handler) ScanURL(asset *models.Asset) string {
+	return h.publicURL + "/scan/" + url.PathEscape(asset.QRCode)
+}
+
+// image serves an asset's QR code as PNG, with an optional scale of pixels
+// per module, or as SVG
+func (h *Handler) image(w http.ResponseWriter, r *http.Request) {
+	asset, ok := h.asset(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	code, err := qrcode.Encode(h.ScanURL(asset))
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if strings.HasSuffix(r.URL.Path, ".svg") {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(code.SVG())
+		return
+	}
+
+	scale := defaultScale
+	if value := r.URL.Query().Get("scale"); value != "" {
+		scale, err = strconv.Atoi(value)
+		if err != nil || scale < 1 || scale > maxScale {
+			http.Error(w, fmt.Sprintf("scale must be between 1 and %d", maxScale), http.StatusBadRequest)
+			return
+		}
+	}
+	png, err := code.PNG(scale)
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// labelSheet serves a PDF of labels for the assets listed in repeated or
+// comma separated id parameters, in the order given
+func (h *Handler) labelSheet(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+	for _, param := range r.URL.Query()["id"] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			id, err := uuid.Parse(value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid id %q", value), http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxLabels {
+		http.Error(w, fmt.Sprintf("between 1 and %d asset ids are required", maxLabels), http.StatusBadRequest)
+		return
+	}
+
+	assets, err := h.assets.GetAssetsByIDs(ids)
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	sheet := make([]labels.Label, len(assets))
+	for i, asset := range assets {
+		code, err := qrcode.Encode(h.ScanURL(asset))
+		if err != nil {
+			h.fail(w, err)
+			return
+		}
+		sheet[i] = labels.Label{
+			Title: asset.Name,
+			Lines: []string{asset.Location, asset.QRCode},
+			Code:  code,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="asset-labels.pdf"`)
+	if err := labels.Write(w, sheet); err != nil {
+		h.logger.Printf("Error writing label sheet: %v", err)
+	}
+}
+
+// ScanResult is the scan landing response: the scanned asset and a
+// CreateTicketInput pre-filled for reporting a problem with it
+type ScanResult struct {
+	Asset             ScannedAsset              `json:"asset"`
+	CreateTicketInput service.CreateTicketInput `json:"createTicketInput"`
+}
+
+// ScannedAsset is the summary of an asset shown after a scan
+type ScannedAsset struct {
+	ID       uuid.UUID          `json:"id"`
+	Name     string             `json:"name"`
+	Type     models.AssetType   `json:"type"`
+	Status   models.AssetStatus `json:"status"`
+	Location string             `json:"location"`
+	QRCode   string             `json:"qrCode"`
+}
+
+// scan resolves a scanned code to its asset and a ticket input the client
+// can show as a report form and send to the createTicket mutation
+func (h *Handler) scan(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	asset, err := h.assets.GetAssetByQRCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, fmt.Sprintf("no asset with QR code %q", code), http.StatusNotFound)
+			return
+		}
+		h.fail(w, err)
+		return
+	}
+
+	result := ScanResult{
+		Asset: ScannedAsset{
+			ID:       asset.ID,
+			Name:     asset.Name,
+			Type:     asset.Type,
+			Status:   asset.Status,
+			Location: asset.Location,
+			QRCode:   asset.QRCode,
+		},
+		CreateTicketInput: service.CreateTicketInput{
+			Title:       "Problem with " + asset.Name,
+			Description: fmt.Sprintf("Reported by scanning asset %s at %s.", asset.QRCode, asset.Location),
+			Priority:    models.TicketPriorityMedium,
+			AssetID:     &asset.ID,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// landing serves the page a scanned code opens in a browser. It does not look
+// the code up, so it tells anonymous users nothing about the asset.
+func (h *Handler) landing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := landingPage.Execute(w, r.PathValue("code")); err != nil {
+		h.logger.Printf("Error writing scan landing page: %v", err)
+	}
+}
+
+// asset loads the asset named by a path id, writing the error response when
+// that fails
+func (h *Handler) asset(w http.ResponseWriter, value string) (*models.Asset, bool) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id %q", value), http.StatusBadRequest)
+		return nil, false
+	}
+	asset, err := h.assets.GetAsset(id)
+	if err != nil {
+		h.fail(w, err)
+		return nil, false
+	}
+	return asset, true
+}
+
+// fail writes the response for a service error
+func (h *Handler) fail(w http.ResponseWriter, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	h.logger.Printf("Error serving QR code route: %v", err)
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
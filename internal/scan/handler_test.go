@@ -0,0 +1,46 @@
+package scan
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnonymousScan(t *testing.T) {
+	h := NewHandler(nil, "https://tickets.example.com", log.New(io.Discard, "", 0))
+	tests := []struct {
+		name, path, accept string
+		status             int
+	}{
+		{"browser scanning a code", "/scan/AST-7K2M9QX4TD", "text/html,application/xhtml+xml,*/*;q=0.8", http.StatusOK},
+		{"API client without a token", "/scan/AST-7K2M9QX4TD", "application/json", http.StatusUnauthorized},
+		{"browser loading a QR code image", "/assets/0b7e8c1a-3f2d-4d6e-9a51-2c8f4e7d9b10/qr.png", "text/html", http.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		r.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, w.Code, tc.status)
+		}
+	}
+}
+
+func TestLandingPageEscapesCode(t *testing.T) {
+	h := NewHandler(nil, "https://tickets.example.com", log.New(io.Discard, "", 0))
+	r := httptest.NewRequest(http.MethodGet, "/scan/%3C%2Fscript%3E%22", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.Contains(body, `const code = "\u003c/script\u003e\"";`) {
+		t.Errorf("status %d, want the code in the page as a JavaScript string:\n%s", w.Code, body)
+	}
+	if strings.Count(body, "</script>") != 1 {
+		t.Error("the code closes the page's script")
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// Escalator periodically applies the escalation rules in the background
+type Escalator struct {
+	escalation service.EscalationService
+	interval   time.Duration
+	logger     *log.Logger
+}
+
+// NewEscalator creates an Escalator evaluating the escalation rules every interval
+func NewEscalator(escalation service.EscalationService, interval time.Duration, logger *log.Logger) *Escalator {
+	return &Escalator{
+		escalation: escalation,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Run evaluates the rules immediately and then on every tick until ctx is done
+func (e *Escalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Escalator) tick() {
+	run, err := e.escalation.ProcessEscalations()
+	if err != nil {
+		e.logger.Printf("Escalator: %v", err)
+	}
+	if run != nil && run.Escalated > 0 {
+		e.logger.Printf("Escalator: %d tickets escalated", run.Escalated)
+	}
+}
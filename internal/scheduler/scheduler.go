@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/service"
+)
+
+// Scheduler periodically runs the maintenance service in the background
+type Scheduler struct {
+	maintenance service.MaintenanceService
+	interval    time.Duration
+	logger      *log.Logger
+}
+
+// New creates a Scheduler processing maintenance schedules every interval
+func New(maintenance service.MaintenanceService, interval time.Duration, logger *log.Logger) *Scheduler {
+	return &Scheduler{
+		maintenance: maintenance,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Run processes the schedules immediately and then on every tick until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick() {
+	run, err := s.maintenance.ProcessSchedules()
+	if err != nil {
+		s.logger.Printf("Maintenance scheduler: %v", err)
+	}
+	if run != nil && (run.TicketsCreated+run.MarkedOverdue+run.Completed+run.Skipped) > 0 {
+		s.logger.Printf("Maintenance scheduler: %d tickets created, %d overdue, %d completed, %d skipped",
+			run.TicketsCreated, run.MarkedOverdue, run.Completed, run.Skipped)
+	}
+}
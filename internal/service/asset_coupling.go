@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AssetCouplingRules configures how tickets drive the status of their asset.
+// Statuses maps a ticket priority to the status an active ticket of that
+// priority puts its asset in; priorities not listed leave the asset alone.
+type AssetCouplingRules struct {
+	Statuses map[models.TicketPriority]models.AssetStatus
+}
+
+// ParseAssetCouplingRules parses rules written as comma separated
+// PRIORITY=STATUS pairs, e.g. "CRITICAL=OUT_OF_SERVICE,HIGH=MAINTENANCE_NEEDED".
+// "off" disables coupling.
+func ParseAssetCouplingRules(value string) (AssetCouplingRules, error) {
+	rules := AssetCouplingRules{Statuses: make(map[models.TicketPriority]models.AssetStatus)}
+	if strings.EqualFold(strings.TrimSpace(value), "off") {
+		return rules, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		priority, status, ok := strings.Cut(pair, "=")
+		if !ok {
+			return rules, fmt.Errorf("asset coupling rule %q is not PRIORITY=STATUS", pair)
+		}
+		p := models.TicketPriority(strings.ToUpper(strings.TrimSpace(priority)))
+		switch p {
+		case models.TicketPriorityLow, models.TicketPriorityMedium, models.TicketPriorityHigh, models.TicketPriorityCritical:
+		default:
+			return rules, fmt.Errorf("asset coupling rule %q: unknown priority %s", pair, p)
+		}
+		st := models.AssetStatus(strings.ToUpper(strings.TrimSpace(status)))
+		if st != models.AssetStatusMaintenanceNeeded && st != models.AssetStatusOutOfService {
+			return rules, fmt.Errorf("asset coupling rule %q: status must be MAINTENANCE_NEEDED or OUT_OF_SERVICE", pair)
+		}
+		rules.Statuses[p] = st
+	}
+	return rules, nil
+}
+
+// assetCoupler keeps the status of assets in line with their active tickets
+// according to the coupling rules. It only ever raises a status set by hand;
+// it lowers a status again only when it set that status itself, so resolving
+// the last CRITICAL ticket brings the asset back to OPERATIONAL unless
+// someone changed the status in the meantime.
+type assetCoupler struct {
+	rules      AssetCouplingRules
+	ticketRepo repository.TicketRepository
+	assetRepo  repository.AssetRepository
+	statusRepo repository.AssetStatusChangeRepository
+}
+
+func newAssetCoupler(rules AssetCouplingRules, ticketRepo repository.TicketRepository, assetRepo repository.AssetRepository, statusRepo repository.AssetStatusChangeRepository) *assetCoupler {
+	return &assetCoupler{rules: rules, ticketRepo: ticketRepo, assetRepo: assetRepo, statusRepo: statusRepo}
+}
+
+// apply re-evaluates the asset of a ticket that was just written in tx, and
+// the asset it was moved off if any, recording each status change with the
+// ticket as its cause. It returns the events to publish once tx commits.
+func (c *assetCoupler) apply(tx *gorm.DB, ticket *models.Ticket, previousAssetID *uuid.UUID, actorID *uuid.UUID, now time.Time) ([]events.Event, error) {
+	if len(c.rules.Statuses) == 0 {
+		return nil, nil
+	}
+	assetIDs := []*uuid.UUID{ticket.AssetID}
+	if !sameID(ticket.AssetID, previousAssetID) {
+		assetIDs = append(assetIDs, previousAssetID)
+	}
+	var published []events.Event
+	for _, assetID := range assetIDs {
+		if assetID == nil {
+			continue
+		}
+		changed, err := c.couple(tx, *assetID, ticket, actorID, now)
+		if err != nil {
+			return nil, err
+		}
+		published = append(published, changed...)
+	}
+	return published, nil
+}
+
+// couple locks the asset before reading its active tickets, so transactions
+// opening and resolving its tickets concurrently are serialized and the last
+// one to commit sees every ticket
+func (c *assetCoupler) couple(tx *gorm.DB, assetID uuid.UUID, trigger *models.Ticket, actorID *uuid.UUID, now time.Time) ([]events.Event, error) {
+	asset, err := c.assetRepo.WithTx(tx).GetForUpdate(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Status == models.AssetStatusDecommissioned {
+		return nil, nil
+	}
+	active, err := c.ticketRepo.WithTx(tx).GetActiveForAsset(assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, cause := models.AssetStatusOperational, (*models.Ticket)(nil)
+	for _, ticket := range active {
+		if status, ok := c.rules.Statuses[ticket.Priority]; ok && assetStatusSeverity(status) > assetStatusSeverity(target) {
+			target, cause = status, ticket
+		}
+	}
+
+	switch {
+	case assetStatusSeverity(target) > assetStatusSeverity(asset.Status):
+	case assetStatusSeverity(target) < assetStatusSeverity(asset.Status):
+		latest, err := c.statusRepo.WithTx(tx).Latest(assetID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || !latest.Automatic {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	reason := "no open ticket calls for another status"
+	if cause != nil {
+		reason = fmt.Sprintf("open %s ticket", cause.Priority)
+	} else {
+		cause = trigger
+	}
+	previous := asset.Status
+	asset.Status = target
+	if err := c.assetRepo.WithTx(tx).Update(asset); err != nil {
+		return nil, err
+	}
+	change := newAssetStatusChange(asset, &previous, now, actorID, &AssetStatusCause{TicketID: &cause.ID, Reason: reason})
+	change.Automatic = true
+	if err := c.statusRepo.WithTx(tx).Create(change); err != nil {
+		return nil, err
+	}
+	return assetStatusEvents(asset, previous), nil
+}
+
+// assetStatusSeverity orders the statuses coupling moves between
+func assetStatusSeverity(status models.AssetStatus) int {
+	switch status {
+	case models.AssetStatusMaintenanceNeeded:
+		return 1
+	case models.AssetStatusOutOfService:
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+func (r *fakeAssetRepo) GetForUpdate(id uuid.UUID) (*models.Asset, error) {
+	return r.GetByID(id)
+}
+
+func (r *fakeAssetRepo) Update(asset *models.Asset) error {
+	stored := *asset
+	r.assets[asset.ID] = &stored
+	return nil
+}
+
+func (r *fakeTicketRepo) GetActiveForAsset(assetID uuid.UUID) ([]*models.Ticket, error) {
+	var active []*models.Ticket
+	for _, ticket := range r.tickets {
+		if sameID(ticket.AssetID, &assetID) && ticket.Status.IsActive() {
+			copied := *ticket
+			active = append(active, &copied)
+		}
+	}
+	return active, nil
+}
+
+type fakeStatusRepo struct {
+	repository.AssetStatusChangeRepository
+	changes []*models.AssetStatusChange
+}
+
+func (r *fakeStatusRepo) WithTx(*gorm.DB) repository.AssetStatusChangeRepository { return r }
+
+func (r *fakeStatusRepo) Create(change *models.AssetStatusChange) error {
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *fakeStatusRepo) Latest(assetID uuid.UUID) (*models.AssetStatusChange, error) {
+	for i := len(r.changes) - 1; i >= 0; i-- {
+		if r.changes[i].AssetID == assetID {
+			return r.changes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func TestParseAssetCouplingRules(t *testing.T) {
+	tests := []struct {
+		value string
+		want  map[models.TicketPriority]models.AssetStatus
+		// err is part of the expected error, empty when the value parses
+		err string
+	}{
+		{"off", map[models.TicketPriority]models.AssetStatus{}, ""},
+		{" Off ", map[models.TicketPriority]models.AssetStatus{}, ""},
+		{"", map[models.TicketPriority]models.AssetStatus{}, ""},
+		{
+			"critical=out_of_service, HIGH = MAINTENANCE_NEEDED,",
+			map[models.TicketPriority]models.AssetStatus{
+				models.TicketPriorityCritical: models.AssetStatusOutOfService,
+				models.TicketPriorityHigh:     models.AssetStatusMaintenanceNeeded,
+			},
+			"",
+		},
+		{"CRITICAL", nil, "is not PRIORITY=STATUS"},
+		{"CRITICAL:OUT_OF_SERVICE", nil, "is not PRIORITY=STATUS"},
+		{"URGENT=OUT_OF_SERVICE", nil, "unknown priority URGENT"},
+		{"HIGH=OPERATIONAL", nil, "status must be MAINTENANCE_NEEDED or OUT_OF_SERVICE"},
+		{"HIGH=DECOMMISSIONED", nil, "status must be MAINTENANCE_NEEDED or OUT_OF_SERVICE"},
+	}
+	for _, tc := range tests {
+		rules, err := ParseAssetCouplingRules(tc.value)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%q: got %v, want an error containing %q", tc.value, err, tc.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.value, err)
+			continue
+		}
+		if len(rules.Statuses) != len(tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.value, rules.Statuses, tc.want)
+		}
+		for priority, status := range tc.want {
+			if rules.Statuses[priority] != status {
+				t.Errorf("%q: %s maps to %q, want %q", tc.value, priority, rules.Statuses[priority], status)
+			}
+		}
+	}
+}
+
+func TestCouple(t *testing.T) {
+	rules := AssetCouplingRules{Statuses: map[models.TicketPriority]models.AssetStatus{
+		models.TicketPriorityCritical: models.AssetStatusOutOfService,
+		models.TicketPriorityHigh:     models.AssetStatusMaintenanceNeeded,
+	}}
+	tests := []struct {
+		name   string
+		status models.AssetStatus
+		// automatic is whether coupling made the asset's latest status change
+		automatic bool
+		tickets   map[models.TicketPriority]models.TicketStatus
+		want      models.AssetStatus
+	}{
+		{
+			name:    "raise",
+			status:  models.AssetStatusOperational,
+			tickets: map[models.TicketPriority]models.TicketStatus{models.TicketPriorityCritical: models.TicketStatusOpen},
+			want:    models.AssetStatusOutOfService,
+		},
+		{
+			name:    "raise a manual status",
+			status:  models.AssetStatusMaintenanceNeeded,
+			tickets: map[models.TicketPriority]models.TicketStatus{models.TicketPriorityCritical: models.TicketStatusInProgress},
+			want:    models.AssetStatusOutOfService,
+		},
+		{
+			name:    "priority without a rule",
+			status:  models.AssetStatusOperational,
+			tickets: map[models.TicketPriority]models.TicketStatus{models.TicketPriorityLow: models.TicketStatusOpen},
+			want:    models.AssetStatusOperational,
+		},
+		{
+			name:      "lower when automatic",
+			status:    models.AssetStatusOutOfService,
+			automatic: true,
+			tickets:   map[models.TicketPriority]models.TicketStatus{models.TicketPriorityCritical: models.TicketStatusResolved},
+			want:      models.AssetStatusOperational,
+		},
+		{
+			name:      "lower to the next rule",
+			status:    models.AssetStatusOutOfService,
+			automatic: true,
+			tickets: map[models.TicketPriority]models.TicketStatus{
+				models.TicketPriorityCritical: models.TicketStatusCancelled,
+				models.TicketPriorityHigh:     models.TicketStatusWaiting,
+			},
+			want: models.AssetStatusMaintenanceNeeded,
+		},
+		{
+			name:    "keep manual",
+			status:  models.AssetStatusOutOfService,
+			tickets: map[models.TicketPriority]models.TicketStatus{models.TicketPriorityCritical: models.TicketStatusResolved},
+			want:    models.AssetStatusOutOfService,
+		},
+		{
+			name:    "decommissioned",
+			status:  models.AssetStatusDecommissioned,
+			tickets: map[models.TicketPriority]models.TicketStatus{models.TicketPriorityCritical: models.TicketStatusOpen},
+			want:    models.AssetStatusDecommissioned,
+		},
+	}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			asset := &models.Asset{Base: models.Base{ID: uuid.New()}, Status: tc.status}
+			assets := &fakeAssetRepo{assets: map[uuid.UUID]*models.Asset{asset.ID: asset}}
+			tickets := &fakeTicketRepo{tickets: map[uuid.UUID]*models.Ticket{}}
+			var trigger *models.Ticket
+			for priority, status := range tc.tickets {
+				trigger = &models.Ticket{Priority: priority, Status: status, AssetID: &asset.ID}
+				tickets.Create(trigger)
+			}
+			statuses := &fakeStatusRepo{changes: []*models.AssetStatusChange{
+				{AssetID: asset.ID, ToStatus: tc.status, Automatic: tc.automatic},
+			}}
+			coupler := newAssetCoupler(rules, tickets, assets, statuses)
+
+			published, err := coupler.couple(nil, asset.ID, trigger, nil, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := assets.assets[asset.ID].Status; got != tc.want {
+				t.Fatalf("asset is %s, want %s", got, tc.want)
+			}
+			if tc.want == tc.status {
+				if len(statuses.changes) != 1 || len(published) != 0 {
+					t.Fatalf("recorded %d changes and %d events for an unchanged asset", len(statuses.changes)-1, len(published))
+				}
+				return
+			}
+			if len(statuses.changes) != 2 {
+				t.Fatalf("recorded %d status changes, want 1", len(statuses.changes)-1)
+			}
+			change := statuses.changes[1]
+			if !change.Automatic || change.ToStatus != tc.want || *change.FromStatus != tc.status || change.TicketID == nil {
+				t.Errorf("recorded %+v, want an automatic change from %s to %s caused by a ticket", change, tc.status, tc.want)
+			}
+			if len(published) != 1 || published[0].Status != string(tc.want) {
+				t.Errorf("published %+v, want one asset status event", published)
+			}
+		})
+	}
+}
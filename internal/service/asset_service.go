@@ -0,0 +1,467 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type AssetService interface {
+	CreateAsset(actor *models.User, input *CreateAssetInput) (*models.Asset, error)
+	UpdateAsset(actor *models.User, id uuid.UUID, input *UpdateAssetInput) (*models.Asset, error)
+	// DecommissionAsset retires an asset for good once no work on it is
+	// pending
+	DecommissionAsset(actor *models.User, id uuid.UUID, reason string) (*models.Asset, error)
+	DeleteAsset(actor *models.User, id uuid.UUID) error
+	GetAsset(id uuid.UUID) (*models.Asset, error)
+	GetAssetByQRCode(code string) (*models.Asset, error)
+	GetAssetsByIDs(ids []uuid.UUID) ([]*models.Asset, error)
+	// GetAssetChildren returns the direct components of an asset
+	GetAssetChildren(id uuid.UUID) ([]*models.Asset, error)
+	// GetAssetDescendants returns the components of an asset at any depth
+	GetAssetDescendants(id uuid.UUID) ([]*models.Asset, error)
+	// GetAssetStatusHistory returns an asset's status changes, most recent
+	// first
+	GetAssetStatusHistory(id uuid.UUID) ([]*models.AssetStatusChange, error)
+	// GetAssetAvailability computes an asset's downtime over [from, to);
+	// the part of the period in the future is not counted
+	GetAssetAvailability(id uuid.UUID, from, to time.Time) (*models.AssetAvailability, error)
+	GetAssets(filter *models.AssetFilter, page *models.PageArgs) (*models.Page[*models.Asset], error)
+}
+
+type assetService struct {
+	tx           repository.Transactor
+	clock        clock.Clock
+	assetRepo    repository.AssetRepository
+	locationRepo repository.LocationRepository
+	statusRepo   repository.AssetStatusChangeRepository
+	ticketRepo   repository.TicketRepository
+	scheduleRepo repository.MaintenanceScheduleRepository
+	recordRepo   repository.MaintenanceRecordRepository
+	schemaRepo   repository.AssetTypeSchemaRepository
+	events       eventSink
+}
+
+func NewAssetService(tx repository.Transactor, clk clock.Clock, assetRepo repository.AssetRepository, locationRepo repository.LocationRepository, statusRepo repository.AssetStatusChangeRepository, ticketRepo repository.TicketRepository, scheduleRepo repository.MaintenanceScheduleRepository, recordRepo repository.MaintenanceRecordRepository, schemaRepo repository.AssetTypeSchemaRepository, outboxRepo repository.OutboxRepository, publisher events.Publisher) AssetService {
+	return &assetService{
+		tx:           tx,
+		clock:        clk,
+		assetRepo:    assetRepo,
+		locationRepo: locationRepo,
+		statusRepo:   statusRepo,
+		ticketRepo:   ticketRepo,
+		scheduleRepo: scheduleRepo,
+		recordRepo:   recordRepo,
+		schemaRepo:   schemaRepo,
+		events:       newEventSink(outboxRepo, publisher),
+	}
+}
+
+func (s *assetService) CreateAsset(actor *models.User, input *CreateAssetInput) (*models.Asset, error) {
+	if err := Authorize(actor, ActionManageAssets); err != nil {
+		return nil, err
+	}
+	asset := &models.Asset{
+		Name:         input.Name,
+		Type:         input.Type,
+		Status:       models.AssetStatusOperational,
+		Location:     strings.TrimSpace(input.Location),
+		QRCode:       newQRCode(),
+		PurchaseDate: input.PurchaseDate,
+		Metadata:     input.Metadata,
+		LocationID:   input.PlaceID,
+		ParentID:     input.ParentID,
+	}
+	if err := s.place(asset, nil); err != nil {
+		return nil, err
+	}
+	if err := checkMetadata(s.schemaRepo, asset); err != nil {
+		return nil, err
+	}
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.assetRepo.WithTx(tx).Create(asset); err != nil {
+			return err
+		}
+		return s.statusRepo.WithTx(tx).Create(newAssetStatusChange(asset, nil, s.clock.Now(), &actor.ID, nil))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (s *assetService) UpdateAsset(actor *models.User, id uuid.UUID, input *UpdateAssetInput) (*models.Asset, error) {
+	if err := Authorize(actor, ActionUpdateAsset); err != nil {
+		return nil, err
+	}
+	return s.change(id, actor, input.StatusCause, func(_ *gorm.DB, asset *models.Asset) error {
+		placedAt := asset.LocationID
+		if input.Name != nil {
+			asset.Name = *input.Name
+		}
+		if input.Type != nil {
+			asset.Type = *input.Type
+		}
+		if input.Status != nil && *input.Status != asset.Status {
+			if err := s.checkStatusChange(asset, *input.Status, input.StatusCause); err != nil {
+				return err
+			}
+			asset.Status = *input.Status
+		}
+		if input.Location != nil {
+			asset.Location = *input.Location
+		}
+		if input.Metadata != nil {
+			asset.Metadata = input.Metadata
+		}
+		if input.PlaceID != nil {
+			asset.LocationID = input.PlaceID
+		}
+		if input.ParentID != nil {
+			asset.ParentID = input.ParentID
+		}
+		if input.PlaceID != nil || input.ParentID != nil {
+			if input.Location != nil {
+				placedAt = nil
+			}
+			if err := s.place(asset, placedAt); err != nil {
+				return err
+			}
+		}
+		if input.Type != nil || input.Metadata != nil {
+			return checkMetadata(s.schemaRepo, asset)
+		}
+		return nil
+	})
+}
+
+func (s *assetService) DecommissionAsset(actor *models.User, id uuid.UUID, reason string) (*models.Asset, error) {
+	if err := Authorize(actor, ActionManageAssets); err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	return s.change(id, actor, &AssetStatusCause{Reason: reason}, func(tx *gorm.DB, asset *models.Asset) error {
+		if asset.Status == models.AssetStatusDecommissioned {
+			return &ValidationError{Message: "asset is already decommissioned"}
+		}
+		tickets, err := s.ticketRepo.WithTx(tx).GetActiveForAsset(id)
+		if err != nil {
+			return err
+		}
+		if len(tickets) > 0 {
+			return &ValidationError{Message: fmt.Sprintf("cannot decommission an asset with %d open ticket(s); resolve or cancel them first", len(tickets))}
+		}
+		schedules, err := s.scheduleRepo.WithTx(tx).GetActiveForAsset(id)
+		if err != nil {
+			return err
+		}
+		if len(schedules) > 0 {
+			return &ValidationError{Message: fmt.Sprintf("cannot decommission an asset with %d active maintenance schedule(s); cancel them first", len(schedules))}
+		}
+		asset.Status = models.AssetStatusDecommissioned
+		return nil
+	})
+}
+
+// change applies edit to the asset read with its row locked and writes it in
+// the same transaction, together with the status change and its event when
+// edit changed the status. It returns the asset as reloaded after the commit.
+func (s *assetService) change(id uuid.UUID, actor *models.User, cause *AssetStatusCause, edit func(tx *gorm.DB, asset *models.Asset) error) (*models.Asset, error) {
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		asset, err := s.assetRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "asset", ID: id}
+			}
+			return err
+		}
+		previous := asset.Status
+		if err := edit(tx, asset); err != nil {
+			return err
+		}
+		if err := s.assetRepo.WithTx(tx).Update(asset); err != nil {
+			return err
+		}
+		if asset.Status != previous {
+			change := newAssetStatusChange(asset, &previous, s.clock.Now(), &actor.ID, cause)
+			if err := s.statusRepo.WithTx(tx).Create(change); err != nil {
+				return err
+			}
+		}
+		published = assetStatusEvents(asset, previous)
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+	return s.assetRepo.GetByID(id)
+}
+
+// checkStatusChange validates a status change made through UpdateAsset and
+// its cause, which must concern the asset
+func (s *assetService) checkStatusChange(asset *models.Asset, status models.AssetStatus, cause *AssetStatusCause) error {
+	if asset.Status == models.AssetStatusDecommissioned {
+		return &ValidationError{Message: "a decommissioned asset cannot change status"}
+	}
+	if status == models.AssetStatusDecommissioned {
+		return &ValidationError{Message: "use decommissionAsset to decommission an asset"}
+	}
+	if cause == nil {
+		return nil
+	}
+	if cause.TicketID != nil {
+		ticket, err := s.ticketRepo.GetByID(*cause.TicketID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "ticket", ID: *cause.TicketID}
+			}
+			return err
+		}
+		if ticket.AssetID == nil || *ticket.AssetID != asset.ID {
+			return &ValidationError{Message: "the ticket causing a status change must be about the asset"}
+		}
+	}
+	if cause.MaintenanceRecordID != nil {
+		record, err := s.recordRepo.GetByID(*cause.MaintenanceRecordID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "maintenance record", ID: *cause.MaintenanceRecordID}
+			}
+			return err
+		}
+		if record.AssetID != asset.ID {
+			return &ValidationError{Message: "the maintenance record causing a status change must be for the asset"}
+		}
+	}
+	return nil
+}
+
+func (s *assetService) DeleteAsset(actor *models.User, id uuid.UUID) error {
+	if err := Authorize(actor, ActionManageAssets); err != nil {
+		return err
+	}
+	if _, err := s.getAsset(id); err != nil {
+		return err
+	}
+	return s.assetRepo.Delete(id)
+}
+
+func (s *assetService) GetAsset(id uuid.UUID) (*models.Asset, error) {
+	return s.assetRepo.GetByID(id)
+}
+
+// GetAssetByQRCode looks an asset up by the code printed on its label. Codes
+// are matched case-insensitively since they are often typed in by hand.
+func (s *assetService) GetAssetByQRCode(code string) (*models.Asset, error) {
+	return s.assetRepo.GetByQRCode(strings.TrimSpace(code))
+}
+
+// GetAssetsByIDs loads the given assets in the order requested, reporting a
+// NotFoundError for the first one that does not exist
+func (s *assetService) GetAssetsByIDs(ids []uuid.UUID) ([]*models.Asset, error) {
+	assets, err := s.assetRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.Asset, len(assets))
+	for _, asset := range assets {
+		byID[asset.ID] = asset
+	}
+	result := make([]*models.Asset, 0, len(ids))
+	for _, id := range ids {
+		asset, ok := byID[id]
+		if !ok {
+			return nil, &NotFoundError{Resource: "asset", ID: id}
+		}
+		result = append(result, asset)
+	}
+	return result, nil
+}
+
+func (s *assetService) GetAssetChildren(id uuid.UUID) ([]*models.Asset, error) {
+	return s.assetRepo.GetChildren(id)
+}
+
+func (s *assetService) GetAssetDescendants(id uuid.UUID) ([]*models.Asset, error) {
+	return s.assetRepo.GetDescendants(id)
+}
+
+func (s *assetService) GetAssetStatusHistory(id uuid.UUID) ([]*models.AssetStatusChange, error) {
+	return s.statusRepo.GetByAsset(id)
+}
+
+func (s *assetService) GetAssetAvailability(id uuid.UUID, from, to time.Time) (*models.AssetAvailability, error) {
+	if !to.After(from) {
+		return nil, &ValidationError{Message: "the end of the period must be after its start"}
+	}
+	if _, err := s.getAsset(id); err != nil {
+		return nil, err
+	}
+	end := to
+	if now := s.clock.Now(); end.After(now) {
+		end = now
+	}
+	var changes []*models.AssetStatusChange
+	if end.After(from) {
+		var err error
+		if changes, err = s.statusRepo.GetBetween(id, from, end); err != nil {
+			return nil, err
+		}
+	}
+	availability := models.AvailabilityOver(changes, from, end)
+	availability.To = to
+	return &availability, nil
+}
+
+func (s *assetService) GetAssets(filter *models.AssetFilter, page *models.PageArgs) (*models.Page[*models.Asset], error) {
+	if filter != nil {
+		if err := checkMetadataFilters(filter.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return s.assetRepo.GetAll(filter, page)
+}
+
+// getAsset loads an asset, reporting a NotFoundError when it does not exist
+func (s *assetService) getAsset(id uuid.UUID) (*models.Asset, error) {
+	asset, err := s.assetRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "asset", ID: id}
+		}
+		return nil, err
+	}
+	return asset, nil
+}
+
+// place checks the asset's location and parent. A component without a
+// location of its own is placed where its parent is, and an asset without a
+// free-text location is labelled with its location's path. When the asset
+// moves off previousPlace and its label is that place's path, the label is
+// regenerated for the new place.
+func (s *assetService) place(asset *models.Asset, previousPlace *uuid.UUID) error {
+	if asset.ParentID != nil {
+		if *asset.ParentID == asset.ID {
+			return &ValidationError{Message: "an asset cannot be its own parent"}
+		}
+		parent, err := s.getAsset(*asset.ParentID)
+		if err != nil {
+			return err
+		}
+		if asset.ID != uuid.Nil {
+			descendants, err := s.assetRepo.GetDescendants(asset.ID)
+			if err != nil {
+				return err
+			}
+			for _, descendant := range descendants {
+				if descendant.ID == parent.ID {
+					return &ValidationError{Message: "an asset cannot be a component of its own component"}
+				}
+			}
+		}
+		if asset.LocationID == nil {
+			asset.LocationID = parent.LocationID
+		}
+	}
+
+	if asset.LocationID != nil {
+		path, err := s.locationRepo.GetAncestors(*asset.LocationID)
+		if err != nil {
+			return err
+		}
+		if len(path) == 0 {
+			return &NotFoundError{Resource: "location", ID: *asset.LocationID}
+		}
+		if previousPlace != nil && *previousPlace != *asset.LocationID {
+			previousPath, err := s.locationRepo.GetAncestors(*previousPlace)
+			if err != nil {
+				return err
+			}
+			if len(previousPath) > 0 && asset.Location == models.LocationPath(previousPath) {
+				asset.Location = ""
+			}
+		}
+		if asset.Location == "" {
+			asset.Location = models.LocationPath(path)
+		}
+	}
+	if asset.Location == "" {
+		return &ValidationError{Message: "an asset needs a location or a place"}
+	}
+	return nil
+}
+
+// qrCodeAlphabet is Crockford's base32: no I, L, O or U, so printed codes
+// cannot be misread
+const qrCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newQRCode returns a random asset code such as AST-7K2M9QX4TD, short enough
+// to read off a label and type in when a scan fails
+func newQRCode() string {
+	b := make([]byte, 10)
+	rand.Read(b)
+	for i := range b {
+		b[i] = qrCodeAlphabet[b[i]%32]
+	}
+	return "AST-" + string(b)
+}
+
+// newAssetStatusChange records the asset's current status, reached from
+// previous (nil when the asset was just created)
+func newAssetStatusChange(asset *models.Asset, previous *models.AssetStatus, at time.Time, actorID *uuid.UUID, cause *AssetStatusCause) *models.AssetStatusChange {
+	change := &models.AssetStatusChange{
+		AssetID:     asset.ID,
+		FromStatus:  previous,
+		ToStatus:    asset.Status,
+		ChangedAt:   at,
+		ChangedByID: actorID,
+	}
+	if cause != nil {
+		change.TicketID = cause.TicketID
+		change.MaintenanceRecordID = cause.MaintenanceRecordID
+		change.Reason = cause.Reason
+	}
+	return change
+}
+
+// Input types for service layer
+type CreateAssetInput struct {
+	Name         string           `json:"name"`
+	Type         models.AssetType `json:"type"`
+	Location     string           `json:"location"`
+	PurchaseDate time.Time        `json:"purchaseDate"`
+	Metadata     models.JSONB     `json:"metadata,omitempty"`
+	PlaceID      *uuid.UUID       `json:"placeId,omitempty"`
+	ParentID     *uuid.UUID       `json:"parentId,omitempty"`
+}
+
+type UpdateAssetInput struct {
+	Name     *string             `json:"name,omitempty"`
+	Type     *models.AssetType   `json:"type,omitempty"`
+	Status   *models.AssetStatus `json:"status,omitempty"`
+	Location *string             `json:"location,omitempty"`
+	Metadata models.JSONB        `json:"metadata,omitempty"`
+	PlaceID  *uuid.UUID          `json:"placeId,omitempty"`
+	ParentID *uuid.UUID          `json:"parentId,omitempty"`
+	// StatusCause explains a change of Status
+	StatusCause *AssetStatusCause `json:"statusCause,omitempty"`
+}
+
+// AssetStatusCause is what led to an asset status change: a ticket or
+// maintenance record about the asset and/or a free-text reason
+type AssetStatusCause struct {
+	Reason              string     `json:"reason"`
+	TicketID            *uuid.UUID `json:"ticketId,omitempty"`
+	MaintenanceRecordID *uuid.UUID `json:"maintenanceRecordId,omitempty"`
+}
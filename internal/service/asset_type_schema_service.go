@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rixtrayker/ticketing-system/internal/jsonschema"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+type AssetTypeSchemaService interface {
+	// SetAssetTypeSchema sets the JSON Schema the metadata of assets of a
+	// type must satisfy, replacing any previous one
+	SetAssetTypeSchema(actor *models.User, assetType models.AssetType, schema models.JSONB) (*models.AssetTypeSchema, error)
+	DeleteAssetTypeSchema(actor *models.User, assetType models.AssetType) error
+	// GetAssetTypeSchema returns the schema for a type, or nil when assets
+	// of the type take any metadata
+	GetAssetTypeSchema(assetType models.AssetType) (*models.AssetTypeSchema, error)
+	GetAssetTypeSchemas() ([]*models.AssetTypeSchema, error)
+}
+
+type assetTypeSchemaService struct {
+	schemaRepo repository.AssetTypeSchemaRepository
+}
+
+func NewAssetTypeSchemaService(schemaRepo repository.AssetTypeSchemaRepository) AssetTypeSchemaService {
+	return &assetTypeSchemaService{schemaRepo: schemaRepo}
+}
+
+// SetAssetTypeSchema checks the schema compiles and describes objects. Assets
+// stored before it are not revalidated until their metadata or type changes.
+func (s *assetTypeSchemaService) SetAssetTypeSchema(actor *models.User, assetType models.AssetType, schema models.JSONB) (*models.AssetTypeSchema, error) {
+	if err := Authorize(actor, ActionManageSchemas); err != nil {
+		return nil, err
+	}
+	compiled, err := jsonschema.Compile(schema)
+	if err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("invalid schema: %v", err)}
+	}
+	if !slices.Equal(compiled.Types(), []string{"object"}) {
+		return nil, &ValidationError{Message: `a metadata schema must have "type": "object"`}
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, schema); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.schemaRepo.GetByType(assetType)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		existing.Schema = models.JSONB(compact.Bytes())
+		if err := s.schemaRepo.Update(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	created := &models.AssetTypeSchema{Type: assetType, Schema: models.JSONB(compact.Bytes())}
+	if err := s.schemaRepo.Create(created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// DeleteAssetTypeSchema lets assets of the type take any metadata again
+func (s *assetTypeSchemaService) DeleteAssetTypeSchema(actor *models.User, assetType models.AssetType) error {
+	if err := Authorize(actor, ActionManageSchemas); err != nil {
+		return err
+	}
+	existing, err := s.schemaRepo.GetByType(assetType)
+	if err != nil || existing == nil {
+		return err
+	}
+	return s.schemaRepo.Delete(existing)
+}
+
+func (s *assetTypeSchemaService) GetAssetTypeSchema(assetType models.AssetType) (*models.AssetTypeSchema, error) {
+	return s.schemaRepo.GetByType(assetType)
+}
+
+func (s *assetTypeSchemaService) GetAssetTypeSchemas() ([]*models.AssetTypeSchema, error) {
+	return s.schemaRepo.GetAll()
+}
+
+// checkMetadata validates an asset's metadata against the schema for its
+// type, if any. Missing metadata is validated as an empty object, so
+// required properties are enforced.
+func checkMetadata(schemaRepo repository.AssetTypeSchemaRepository, asset *models.Asset) error {
+	schema, err := schemaRepo.GetByType(asset.Type)
+	if err != nil || schema == nil {
+		return err
+	}
+	compiled, err := jsonschema.Compile(schema.Schema)
+	if err != nil {
+		return err
+	}
+	metadata := []byte(asset.Metadata)
+	if len(bytes.TrimSpace(metadata)) == 0 || string(bytes.TrimSpace(metadata)) == "null" {
+		metadata = []byte("{}")
+	}
+	if err := compiled.Validate(metadata); err != nil {
+		var problems jsonschema.Errors
+		if errors.As(err, &problems) {
+			return &ValidationError{Message: fmt.Sprintf("metadata does not match the %s schema: %v", asset.Type, problems)}
+		}
+		return err
+	}
+	return nil
+}
+
+// checkMetadataFilters verifies each filter has a path and a value the
+// operator can compare with
+func checkMetadataFilters(filters []models.MetadataFilter) error {
+	for _, filter := range filters {
+		if len(filter.Path) == 0 || slices.Contains(filter.Path, "") {
+			return &ValidationError{Message: "a metadata filter path must be dot separated keys, e.g. hvac.tonnage"}
+		}
+		var value any
+		if len(filter.Value) > 0 {
+			if err := json.Unmarshal(filter.Value, &value); err != nil {
+				return &ValidationError{Message: "a metadata filter value must be JSON"}
+			}
+		}
+
+		switch filter.Operator {
+		case models.MetadataOperatorExists:
+			if len(filter.Value) > 0 {
+				return &ValidationError{Message: "an EXISTS metadata filter takes no value"}
+			}
+		case models.MetadataOperatorContains:
+			if len(filter.Value) == 0 {
+				return &ValidationError{Message: "a CONTAINS metadata filter needs a value"}
+			}
+		case models.MetadataOperatorEquals:
+			if len(filter.Value) == 0 {
+				return &ValidationError{Message: "an EQUALS metadata filter needs a value"}
+			}
+			switch value.(type) {
+			case map[string]any, []any:
+				return &ValidationError{Message: "an EQUALS metadata filter compares with a string, number, boolean or null; use CONTAINS for objects and arrays"}
+			}
+		case models.MetadataOperatorGT, models.MetadataOperatorGTE, models.MetadataOperatorLT, models.MetadataOperatorLTE:
+			switch value.(type) {
+			case float64, string:
+			default:
+				return &ValidationError{Message: fmt.Sprintf("a %s metadata filter compares with a number or a string", filter.Operator)}
+			}
+		default:
+			return &ValidationError{Message: fmt.Sprintf("unknown metadata filter operator %s", filter.Operator)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type fakeSchemaRepo struct {
+	repository.AssetTypeSchemaRepository
+	schemas map[models.AssetType]*models.AssetTypeSchema
+}
+
+func (r *fakeSchemaRepo) WithTx(*gorm.DB) repository.AssetTypeSchemaRepository { return r }
+
+func (r *fakeSchemaRepo) GetByType(assetType models.AssetType) (*models.AssetTypeSchema, error) {
+	return r.schemas[assetType], nil
+}
+
+func (r *fakeSchemaRepo) Create(schema *models.AssetTypeSchema) error {
+	schema.ID = uuid.New()
+	r.schemas[schema.Type] = schema
+	return nil
+}
+
+func (r *fakeSchemaRepo) Update(schema *models.AssetTypeSchema) error {
+	r.schemas[schema.Type] = schema
+	return nil
+}
+
+func TestSetAssetTypeSchema(t *testing.T) {
+	actor := &models.User{Role: admin}
+	tests := []struct {
+		name   string
+		schema string
+		// want is part of the expected validation message, empty when the
+		// schema is saved
+		want string
+	}{
+		{"object schema", `{"type": "object", "required": ["tonnage"], "properties": {"tonnage": {"type": "number"}}}`, ""},
+		{"unknown keyword", `{"type": "object", "properties": {"tonnage": {"type": "number", "minimun": 1}}}`, "keyword is not supported"},
+		{"reference", `{"type": "object", "$ref": "#/$defs/hvac"}`, "keyword is not supported"},
+		{"not an object schema", `{"type": "array"}`, `"type": "object"`},
+		{"no type", `{"properties": {}}`, `"type": "object"`},
+		{"invalid JSON", `{"type": "object"`, "not valid JSON"},
+	}
+	for _, tc := range tests {
+		repo := &fakeSchemaRepo{schemas: map[models.AssetType]*models.AssetTypeSchema{}}
+		s := NewAssetTypeSchemaService(repo)
+		_, err := s.SetAssetTypeSchema(actor, models.AssetTypeHVAC, models.JSONB(tc.schema))
+		if tc.want == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tc.name, err)
+			}
+			if repo.schemas[models.AssetTypeHVAC] == nil {
+				t.Errorf("%s: schema not saved", tc.name)
+			}
+			continue
+		}
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) || !strings.Contains(invalid.Message, tc.want) {
+			t.Errorf("%s: got %v, want a validation error containing %q", tc.name, err, tc.want)
+		}
+		if repo.schemas[models.AssetTypeHVAC] != nil {
+			t.Errorf("%s: rejected schema was saved", tc.name)
+		}
+	}
+}
+
+func TestSetAssetTypeSchemaRequiresAdmin(t *testing.T) {
+	repo := &fakeSchemaRepo{schemas: map[models.AssetType]*models.AssetTypeSchema{}}
+	s := NewAssetTypeSchemaService(repo)
+	actor := &models.User{Role: manager}
+	if _, err := s.SetAssetTypeSchema(actor, models.AssetTypeHVAC, models.JSONB(`{"type": "object"}`)); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("got %v, want ErrForbidden", err)
+	}
+}
+
+func TestCheckMetadata(t *testing.T) {
+	repo := &fakeSchemaRepo{schemas: map[models.AssetType]*models.AssetTypeSchema{
+		models.AssetTypeHVAC: {Type: models.AssetTypeHVAC, Schema: models.JSONB(`{"type": "object", "required": ["tonnage"], "properties": {"tonnage": {"type": "number", "minimum": 0.5}}}`)},
+	}}
+	tests := []struct {
+		assetType models.AssetType
+		metadata  string
+		valid     bool
+	}{
+		{models.AssetTypeHVAC, `{"tonnage": 5}`, true},
+		{models.AssetTypeHVAC, `{"tonnage": 0}`, false},
+		{models.AssetTypeHVAC, ``, false},
+		{models.AssetTypeHVAC, `null`, false},
+		{models.AssetTypePlumbing, ``, true},
+		{models.AssetTypePlumbing, `{"anything": [1, 2]}`, true},
+	}
+	for _, tc := range tests {
+		asset := &models.Asset{Type: tc.assetType, Metadata: models.JSONB(tc.metadata)}
+		err := checkMetadata(repo, asset)
+		if tc.valid && err != nil {
+			t.Errorf("%s metadata %q: %v", tc.assetType, tc.metadata, err)
+		}
+		var invalid *ValidationError
+		if !tc.valid && !errors.As(err, &invalid) {
+			t.Errorf("%s metadata %q: got %v, want a validation error", tc.assetType, tc.metadata, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCredentials is returned when an email and password do not match
+	ErrInvalidCredentials = errors.New("invalid email or password")
+)
+
+type AuthService interface {
+	Login(email, password string) (*AuthResult, error)
+	Refresh(refreshToken string) (*AuthResult, error)
+	Authenticate(accessToken string) (*models.User, error)
+	BootstrapAdmin(email, password string) (bool, error)
+}
+
+type authService struct {
+	userRepo repository.UserRepository
+	tokens   *auth.TokenManager
+}
+
+func NewAuthService(userRepo repository.UserRepository, tokens *auth.TokenManager) AuthService {
+	return &authService{
+		userRepo: userRepo,
+		tokens:   tokens,
+	}
+}
+
+func (s *authService) Login(email, password string) (*AuthResult, error) {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if !auth.CheckPassword(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return s.issue(user)
+}
+
+func (s *authService) Refresh(refreshToken string) (*AuthResult, error) {
+	claims, err := s.tokens.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userFromClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user)
+}
+
+func (s *authService) Authenticate(accessToken string) (*models.User, error) {
+	claims, err := s.tokens.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.userFromClaims(claims)
+}
+
+// BootstrapAdmin creates an initial ADMIN account when the users table has no
+// administrator yet. It reports whether a user was created.
+func (s *authService) BootstrapAdmin(email, password string) (bool, error) {
+	role := models.UserRoleAdmin
+	one := 1
+	admins, err := s.userRepo.GetAll(&models.UserFilter{Role: &role}, &models.PageArgs{First: &one})
+	if err != nil {
+		return false, err
+	}
+	if admins.TotalCount > 0 {
+		return false, nil
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	admin := &models.User{
+		Email:                   email,
+		Name:                    "Administrator",
+		Role:                    models.UserRoleAdmin,
+		PasswordHash:            hash,
+		NotificationPreferences: models.DefaultNotificationPreferences(),
+	}
+	if err := s.userRepo.Create(admin); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *authService) userFromClaims(claims *auth.Claims) (*models.User, error) {
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *authService) issue(user *models.User) (*AuthResult, error) {
+	tokens, err := s.tokens.Issue(user)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResult{Tokens: tokens, User: user}, nil
+}
+
+// AuthResult is returned by a successful login or token refresh
+type AuthResult struct {
+	Tokens *auth.TokenPair
+	User   *models.User
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type CalendarService interface {
+	CreateBusinessCalendar(actor *models.User, input *CreateBusinessCalendarInput) (*models.BusinessCalendar, error)
+	UpdateBusinessCalendar(actor *models.User, id uuid.UUID, input *UpdateBusinessCalendarInput) (*models.BusinessCalendar, error)
+	DeleteBusinessCalendar(actor *models.User, id uuid.UUID) error
+	GetBusinessCalendar(id uuid.UUID) (*models.BusinessCalendar, error)
+	GetBusinessCalendars() ([]*models.BusinessCalendar, error)
+	// GetCalendarForLocation returns the calendar time at location is
+	// counted in, or nil when time is counted 24/7
+	GetCalendarForLocation(location string) (*models.BusinessCalendar, error)
+}
+
+type calendarService struct {
+	tx           repository.Transactor
+	calendarRepo repository.BusinessCalendarRepository
+}
+
+func NewCalendarService(tx repository.Transactor, calendarRepo repository.BusinessCalendarRepository) CalendarService {
+	return &calendarService{tx: tx, calendarRepo: calendarRepo}
+}
+
+// CreateBusinessCalendar adds a calendar. Due dates already set keep counting
+// in the calendar they were planned with.
+func (s *calendarService) CreateBusinessCalendar(actor *models.User, input *CreateBusinessCalendarInput) (*models.BusinessCalendar, error) {
+	if err := Authorize(actor, ActionManageCalendars); err != nil {
+		return nil, err
+	}
+	calendar := &models.BusinessCalendar{
+		Name:         strings.TrimSpace(input.Name),
+		TimeZone:     strings.TrimSpace(input.TimeZone),
+		WorkingHours: input.WorkingHours,
+		Holidays:     append([]models.Holiday{}, input.Holidays...),
+		Locations:    cleanLocations(input.Locations),
+		IsDefault:    input.IsDefault,
+	}
+	if err := s.validate(calendar); err != nil {
+		return nil, err
+	}
+	if err := s.save(calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *calendarService) UpdateBusinessCalendar(actor *models.User, id uuid.UUID, input *UpdateBusinessCalendarInput) (*models.BusinessCalendar, error) {
+	if err := Authorize(actor, ActionManageCalendars); err != nil {
+		return nil, err
+	}
+	calendar, err := s.GetBusinessCalendar(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		calendar.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.TimeZone != nil {
+		calendar.TimeZone = strings.TrimSpace(*input.TimeZone)
+	}
+	if input.WorkingHours != nil {
+		calendar.WorkingHours = input.WorkingHours
+	}
+	if input.Holidays != nil {
+		calendar.Holidays = input.Holidays
+	}
+	if input.Locations != nil {
+		calendar.Locations = cleanLocations(input.Locations)
+	}
+	if input.IsDefault != nil {
+		calendar.IsDefault = *input.IsDefault
+	}
+	if err := s.validate(calendar); err != nil {
+		return nil, err
+	}
+	if err := s.save(calendar); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *calendarService) DeleteBusinessCalendar(actor *models.User, id uuid.UUID) error {
+	if err := Authorize(actor, ActionManageCalendars); err != nil {
+		return err
+	}
+	if _, err := s.GetBusinessCalendar(id); err != nil {
+		return err
+	}
+	return s.calendarRepo.Delete(id)
+}
+
+func (s *calendarService) GetBusinessCalendar(id uuid.UUID) (*models.BusinessCalendar, error) {
+	calendar, err := s.calendarRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "business calendar", ID: id}
+		}
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *calendarService) GetBusinessCalendars() ([]*models.BusinessCalendar, error) {
+	return s.calendarRepo.GetAll()
+}
+
+func (s *calendarService) GetCalendarForLocation(location string) (*models.BusinessCalendar, error) {
+	return s.calendarRepo.ForLocation(location)
+}
+
+// save writes the calendar, taking the default over from the previous
+// default calendar when it becomes the default
+func (s *calendarService) save(calendar *models.BusinessCalendar) error {
+	return s.tx.Transaction(func(tx *gorm.DB) error {
+		calendars := s.calendarRepo.WithTx(tx)
+		if calendar.IsDefault {
+			previous, err := calendars.GetDefault()
+			if err != nil {
+				return err
+			}
+			if previous != nil && previous.ID != calendar.ID {
+				previous.IsDefault = false
+				if err := calendars.Update(previous); err != nil {
+					return err
+				}
+			}
+		}
+		if calendar.ID == uuid.Nil {
+			return calendars.Create(calendar)
+		}
+		return calendars.Update(calendar)
+	})
+}
+
+// validate checks the calendar's hours and holidays, and that its name and
+// locations are not taken by another calendar
+func (s *calendarService) validate(calendar *models.BusinessCalendar) error {
+	if calendar.Name == "" {
+		return &ValidationError{Message: "business calendar name cannot be empty"}
+	}
+	if _, err := calendar.Calendar(); err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+
+	existing, err := s.calendarRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID != calendar.ID && strings.EqualFold(other.Name, calendar.Name) {
+			return &ValidationError{Message: "a business calendar with this name already exists"}
+		}
+	}
+	covering, err := s.calendarRepo.Covering(calendar.Locations, calendar.ID)
+	if err != nil {
+		return err
+	}
+	if len(covering) > 0 {
+		return &ValidationError{Message: "a location is already covered by business calendar " + covering[0].Name}
+	}
+	return nil
+}
+
+// cleanLocations trims the locations and drops blanks and duplicates
+func cleanLocations(locations []string) []string {
+	cleaned := make([]string, 0, len(locations))
+	seen := make(map[string]bool)
+	for _, location := range locations {
+		location = strings.TrimSpace(location)
+		if location != "" && !seen[location] {
+			seen[location] = true
+			cleaned = append(cleaned, location)
+		}
+	}
+	return cleaned
+}
+
+// Input types for service layer
+type CreateBusinessCalendarInput struct {
+	Name         string                 `json:"name"`
+	TimeZone     string                 `json:"timeZone"`
+	WorkingHours []models.WorkingPeriod `json:"workingHours"`
+	Holidays     []models.Holiday       `json:"holidays"`
+	Locations    []string               `json:"locations"`
+	IsDefault    bool                   `json:"isDefault"`
+}
+
+type UpdateBusinessCalendarInput struct {
+	Name         *string                `json:"name,omitempty"`
+	TimeZone     *string                `json:"timeZone,omitempty"`
+	WorkingHours []models.WorkingPeriod `json:"workingHours,omitempty"`
+	Holidays     []models.Holiday       `json:"holidays,omitempty"`
+	Locations    []string               `json:"locations,omitempty"`
+	IsDefault    *bool                  `json:"isDefault,omitempty"`
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/calendar"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+// calendarLookup finds the business calendar time is counted in for an asset
+// or location. A nil calendar counts every hour.
+type calendarLookup struct {
+	calendarRepo repository.BusinessCalendarRepository
+	assetRepo    repository.AssetRepository
+}
+
+func newCalendarLookup(calendarRepo repository.BusinessCalendarRepository, assetRepo repository.AssetRepository) *calendarLookup {
+	return &calendarLookup{calendarRepo: calendarRepo, assetRepo: assetRepo}
+}
+
+// forAsset returns the calendar of the asset's location, or the default
+// calendar when there is no asset
+func (l *calendarLookup) forAsset(assetID *uuid.UUID) (*calendar.Calendar, error) {
+	if assetID == nil {
+		return l.forLocation("")
+	}
+	asset, err := l.assetRepo.GetByID(*assetID)
+	if err != nil {
+		return nil, err
+	}
+	return l.forLocation(asset.Location)
+}
+
+// forLocation returns the calendar covering location, or the default calendar
+func (l *calendarLookup) forLocation(location string) (*calendar.Calendar, error) {
+	found, err := l.calendarRepo.ForLocation(location)
+	if err != nil || found == nil {
+		return nil, err
+	}
+	cal, err := found.Calendar()
+	if err != nil {
+		return nil, fmt.Errorf("business calendar %s: %w", found.ID, err)
+	}
+	return cal, nil
+}
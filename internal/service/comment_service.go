@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type CommentService interface {
+	AddComment(actor *models.User, input *AddCommentInput) (*models.Comment, error)
+	EditComment(actor *models.User, id uuid.UUID, content string) (*models.Comment, error)
+	DeleteComment(actor *models.User, id uuid.UUID) error
+	GetComment(viewer *models.User, id uuid.UUID) (*models.Comment, error)
+	GetComments(viewer *models.User, ticketID uuid.UUID) ([]*models.Comment, error)
+}
+
+type commentService struct {
+	tx          repository.Transactor
+	commentRepo repository.CommentRepository
+	ticketRepo  repository.TicketRepository
+	eventRepo   repository.TicketEventRepository
+	userRepo    repository.UserRepository
+	sla         *slaPlanner
+	events      eventSink
+}
+
+func NewCommentService(tx repository.Transactor, commentRepo repository.CommentRepository, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, userRepo repository.UserRepository, assetRepo repository.AssetRepository, slaPolicyRepo repository.SLAPolicyRepository, calendarRepo repository.BusinessCalendarRepository, outboxRepo repository.OutboxRepository, publisher events.Publisher) CommentService {
+	return &commentService{
+		tx:          tx,
+		commentRepo: commentRepo,
+		ticketRepo:  ticketRepo,
+		eventRepo:   eventRepo,
+		userRepo:    userRepo,
+		sla:         newSLAPlanner(slaPolicyRepo, assetRepo, calendarRepo),
+		events:      newEventSink(outboxRepo, publisher),
+	}
+}
+
+func (s *commentService) AddComment(actor *models.User, input *AddCommentInput) (*models.Comment, error) {
+	content, err := validateContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ticketRepo.GetByID(input.TicketID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "ticket", ID: input.TicketID}
+		}
+		return nil, err
+	}
+	if input.Internal {
+		if err := Authorize(actor, ActionViewInternal); err != nil {
+			return nil, err
+		}
+	}
+	mentioned, err := s.resolveMentions(content)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TicketID: input.TicketID,
+		UserID:   actor.ID,
+		Content:  content,
+		Internal: input.Internal,
+	}
+	var published []events.Event
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		comments := s.commentRepo.WithTx(tx)
+		if err := comments.Create(comment); err != nil {
+			return err
+		}
+		if err := comments.ReplaceMentions(comment, mentioned); err != nil {
+			return err
+		}
+		followUp, err := s.followUp(tx, comment, time.Now())
+		if err != nil {
+			return err
+		}
+		added := events.Event{Type: events.CommentAdded, TicketID: &comment.TicketID, CommentID: &comment.ID}
+		published = append([]events.Event{added}, followUp...)
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.commentRepo.GetByID(comment.ID)
+}
+
+// EditComment replaces the content of a comment, keeping the previous
+// content as a revision and relinking mentions
+func (s *commentService) EditComment(actor *models.User, id uuid.UUID, content string) (*models.Comment, error) {
+	content, err := validateContent(content)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(actor, ActionEditComment, comment.UserID); err != nil {
+		return nil, err
+	}
+	if content == comment.Content {
+		return comment, nil
+	}
+	mentioned, err := s.resolveMentions(content)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := &models.CommentRevision{
+		CommentID:  comment.ID,
+		EditedByID: actor.ID,
+		Content:    comment.Content,
+	}
+	now := time.Now()
+	comment.Content = content
+	comment.Edited = true
+	comment.EditedAt = &now
+
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		comments := s.commentRepo.WithTx(tx)
+		if err := comments.CreateRevision(revision); err != nil {
+			return err
+		}
+		if err := comments.Update(comment); err != nil {
+			return err
+		}
+		return comments.ReplaceMentions(comment, mentioned)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.GetByID(id)
+}
+
+func (s *commentService) DeleteComment(actor *models.User, id uuid.UUID) error {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := Authorize(actor, ActionDeleteComment, comment.UserID); err != nil {
+		return err
+	}
+	return s.commentRepo.Delete(id)
+}
+
+// GetComment returns a comment, hiding internal comments from viewers not
+// allowed to see them
+func (s *commentService) GetComment(viewer *models.User, id uuid.UUID) (*models.Comment, error) {
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Internal && !CanViewInternal(viewer) {
+		return nil, &NotFoundError{Resource: "comment", ID: id}
+	}
+	return comment, nil
+}
+
+// GetComments returns the comments of a ticket the viewer is allowed to see;
+// internal comments are only returned to technicians and above
+func (s *commentService) GetComments(viewer *models.User, ticketID uuid.UUID) ([]*models.Comment, error) {
+	return s.commentRepo.GetByTicketID(ticketID, CanViewInternal(viewer))
+}
+
+// followUp applies what a new comment means for its ticket's SLA: a public
+// comment from anyone but the requester is a response, and the requester
+// replying to a ticket waiting on them puts it back in progress. It returns
+// the events to publish for the ticket. The ticket is read with its row
+// locked, so a concurrent change to it is neither overwritten nor missed.
+func (s *commentService) followUp(tx *gorm.DB, comment *models.Comment, now time.Time) ([]events.Event, error) {
+	ticket, err := s.ticketRepo.WithTx(tx).GetForUpdate(comment.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case comment.UserID != ticket.CreatedByID:
+		if comment.Internal || ticket.RespondedAt != nil {
+			return nil, nil
+		}
+		return nil, s.ticketRepo.WithTx(tx).MarkResponded(ticket.ID, now)
+	case ticket.Status == models.TicketStatusWaiting:
+		before := *ticket
+		if err := applyStatus(ticket, models.TicketStatusInProgress, now); err != nil {
+			return nil, err
+		}
+		if err := s.sla.update(&before, ticket, now); err != nil {
+			return nil, err
+		}
+		if err := s.ticketRepo.WithTx(tx).Update(ticket); err != nil {
+			return nil, err
+		}
+		if err := s.eventRepo.WithTx(tx).Create(ticketChanges(&before, ticket, &comment.UserID)...); err != nil {
+			return nil, err
+		}
+		return ticketUpdatedEvents(&before, ticket), nil
+	}
+	return nil, nil
+}
+
+// resolveMentions looks up the users mentioned in content. Mentions that do
+// not match exactly one user are ignored.
+func (s *commentService) resolveMentions(content string) ([]*models.User, error) {
+	var users []*models.User
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range parseMentions(content) {
+		var user *models.User
+		if m.Email != "" {
+			found, err := s.userRepo.GetByEmail(m.Email)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			user = found
+		} else {
+			found, err := s.userRepo.FindByName(m.Name)
+			if err != nil {
+				return nil, err
+			}
+			if len(found) != 1 {
+				continue
+			}
+			user = found[0]
+		}
+
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// CanViewInternal reports whether the user may see internal comments
+func CanViewInternal(user *models.User) bool {
+	return Authorize(user, ActionViewInternal) == nil
+}
+
+func validateContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", &ValidationError{Message: "comment content cannot be empty"}
+	}
+	return content, nil
+}
+
+// Input types for service layer
+type AddCommentInput struct {
+	TicketID uuid.UUID `json:"ticketId"`
+	Content  string    `json:"content"`
+	Internal bool      `json:"internal"`
+}
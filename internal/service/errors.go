@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// NotFoundError reports that a record referenced by an input does not exist
+type NotFoundError struct {
+	Resource string
+	ID       uuid.UUID
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Resource, e.ID)
+}
+
+// Unwrap lets callers match the error with errors.Is(err, gorm.ErrRecordNotFound)
+func (e *NotFoundError) Unwrap() error {
+	return gorm.ErrRecordNotFound
+}
+
+// ValidationError reports input that failed a business rule
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// InvalidTransitionError reports a ticket status change not allowed by the workflow
+type InvalidTransitionError struct {
+	From models.TicketStatus
+	To   models.TicketStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change ticket status from %s to %s", e.From, e.To)
+}
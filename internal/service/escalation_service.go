@@ -0,0 +1,334 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type EscalationService interface {
+	ProcessEscalations() (*EscalationRun, error)
+	CreateEscalationRule(actor *models.User, input *CreateEscalationRuleInput) (*models.EscalationRule, error)
+	UpdateEscalationRule(actor *models.User, id uuid.UUID, input *UpdateEscalationRuleInput) (*models.EscalationRule, error)
+	DeleteEscalationRule(actor *models.User, id uuid.UUID) error
+	GetEscalationRule(id uuid.UUID) (*models.EscalationRule, error)
+	GetEscalationRules() ([]*models.EscalationRule, error)
+}
+
+// EscalationRun summarizes one pass over the escalation rules
+type EscalationRun struct {
+	Escalated int
+}
+
+type escalationService struct {
+	tx          repository.Transactor
+	clock       clock.Clock
+	ruleRepo    repository.EscalationRuleRepository
+	ticketRepo  repository.TicketRepository
+	eventRepo   repository.TicketEventRepository
+	commentRepo repository.CommentRepository
+	userRepo    repository.UserRepository
+	sla         *slaPlanner
+	coupler     *assetCoupler
+	events      eventSink
+}
+
+func NewEscalationService(tx repository.Transactor, clk clock.Clock, ruleRepo repository.EscalationRuleRepository, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, commentRepo repository.CommentRepository, userRepo repository.UserRepository, assetRepo repository.AssetRepository, slaPolicyRepo repository.SLAPolicyRepository, calendarRepo repository.BusinessCalendarRepository, statusRepo repository.AssetStatusChangeRepository, coupling AssetCouplingRules, outboxRepo repository.OutboxRepository, publisher events.Publisher) EscalationService {
+	return &escalationService{
+		tx:          tx,
+		clock:       clk,
+		ruleRepo:    ruleRepo,
+		ticketRepo:  ticketRepo,
+		eventRepo:   eventRepo,
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		sla:         newSLAPlanner(slaPolicyRepo, assetRepo, calendarRepo),
+		coupler:     newAssetCoupler(coupling, ticketRepo, assetRepo, statusRepo),
+		events:      newEventSink(outboxRepo, publisher),
+	}
+}
+
+// ProcessEscalations applies every active rule to the tickets matching it
+// that it has not escalated yet. A failing ticket does not stop the others;
+// all errors are returned joined.
+func (s *escalationService) ProcessEscalations() (*EscalationRun, error) {
+	rules, err := s.ruleRepo.GetActive()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	run := &EscalationRun{}
+	var errs []error
+	for _, rule := range rules {
+		ticketIDs, err := s.ruleRepo.Candidates(rule, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("escalation rule %s: %w", rule.ID, err))
+			continue
+		}
+		for _, ticketID := range ticketIDs {
+			escalated, err := s.escalate(rule, ticketID, now)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("escalation rule %s, ticket %s: %w", rule.ID, ticketID, err))
+				continue
+			}
+			if escalated {
+				run.Escalated++
+			}
+		}
+	}
+	return run, errors.Join(errs...)
+}
+
+// escalate applies the rule's actions to a ticket, recording each in the
+// ticket history as a change made by the system. The ticket is re-read with
+// its row locked, so the actions apply to its current state and concurrent
+// edits are not overwritten. It reports false when the rule had already
+// escalated the ticket or the ticket is no longer active.
+func (s *escalationService) escalate(rule *models.EscalationRule, ticketID uuid.UUID, now time.Time) (bool, error) {
+	escalated := false
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		ticket, err := s.ticketRepo.WithTx(tx).GetForUpdate(ticketID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !ticket.Status.IsActive() {
+			return nil
+		}
+		before := *ticket
+		if rule.BumpPriority {
+			ticket.Priority = ticket.Priority.Raised()
+		}
+		if rule.EscalateToID != nil {
+			ticket.AssignedToID = rule.EscalateToID
+		}
+		if err := s.sla.update(&before, ticket, now); err != nil {
+			return err
+		}
+
+		recorded, err := s.ruleRepo.WithTx(tx).RecordEscalation(&models.TicketEscalation{
+			TicketID:    ticket.ID,
+			RuleID:      rule.ID,
+			EscalatedAt: now,
+		})
+		if err != nil || !recorded {
+			return err
+		}
+		escalated = true
+
+		history := []*models.TicketEvent{escalationEvent(ticket, FieldEscalation, rule.Name)}
+		if changes := ticketChanges(&before, ticket, nil); len(changes) > 0 {
+			if err := s.ticketRepo.WithTx(tx).Update(ticket); err != nil {
+				return err
+			}
+			history = append(history, changes...)
+			published = append(published, ticketUpdatedEvents(&before, ticket)...)
+			coupled, err := s.coupler.apply(tx, ticket, before.AssetID, nil, now)
+			if err != nil {
+				return err
+			}
+			published = append(published, coupled...)
+		}
+		if rule.Comment != "" {
+			comment := &models.Comment{
+				TicketID: ticket.ID,
+				UserID:   rule.CreatedByID,
+				Content:  rule.Comment,
+				Internal: true,
+				System:   true,
+			}
+			if err := s.commentRepo.WithTx(tx).Create(comment); err != nil {
+				return err
+			}
+			history = append(history, escalationEvent(ticket, FieldComment, comment.ID.String()))
+			published = append(published, events.Event{Type: events.CommentAdded, TicketID: &ticket.ID, CommentID: &comment.ID})
+		}
+		if rule.Notify {
+			history = append(history, escalationEvent(ticket, FieldNotification, rule.Name))
+			published = append(published, events.Event{
+				Type:       events.TicketEscalated,
+				TicketID:   &ticket.ID,
+				RuleID:     &rule.ID,
+				AssigneeID: ticket.AssignedToID,
+			})
+		}
+		if err := s.eventRepo.WithTx(tx).Create(history...); err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil || !escalated {
+		return false, err
+	}
+	s.events.publish(published...)
+	return true, nil
+}
+
+func (s *escalationService) CreateEscalationRule(actor *models.User, input *CreateEscalationRuleInput) (*models.EscalationRule, error) {
+	if err := Authorize(actor, ActionManageEscalations); err != nil {
+		return nil, err
+	}
+	rule := &models.EscalationRule{
+		Name:         strings.TrimSpace(input.Name),
+		Condition:    input.Condition,
+		Priority:     input.Priority,
+		AfterMinutes: input.AfterMinutes,
+		Active:       true,
+		BumpPriority: input.BumpPriority,
+		EscalateToID: input.EscalateToID,
+		Comment:      strings.TrimSpace(input.Comment),
+		Notify:       input.Notify,
+		CreatedByID:  actor.ID,
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	return s.ruleRepo.GetByID(rule.ID)
+}
+
+// UpdateEscalationRule changes a rule. Tickets it already escalated are not
+// escalated again.
+func (s *escalationService) UpdateEscalationRule(actor *models.User, id uuid.UUID, input *UpdateEscalationRuleInput) (*models.EscalationRule, error) {
+	if err := Authorize(actor, ActionManageEscalations); err != nil {
+		return nil, err
+	}
+	rule, err := s.GetEscalationRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		rule.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.AfterMinutes != nil {
+		rule.AfterMinutes = *input.AfterMinutes
+	}
+	if input.Active != nil {
+		rule.Active = *input.Active
+	}
+	if input.BumpPriority != nil {
+		rule.BumpPriority = *input.BumpPriority
+	}
+	if input.EscalateToID != nil {
+		rule.EscalateToID = input.EscalateToID
+		rule.EscalateTo = nil
+	}
+	if input.Comment != nil {
+		rule.Comment = strings.TrimSpace(*input.Comment)
+	}
+	if input.Notify != nil {
+		rule.Notify = *input.Notify
+	}
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	return s.ruleRepo.GetByID(id)
+}
+
+func (s *escalationService) DeleteEscalationRule(actor *models.User, id uuid.UUID) error {
+	if err := Authorize(actor, ActionManageEscalations); err != nil {
+		return err
+	}
+	if _, err := s.GetEscalationRule(id); err != nil {
+		return err
+	}
+	return s.ruleRepo.Delete(id)
+}
+
+func (s *escalationService) GetEscalationRule(id uuid.UUID) (*models.EscalationRule, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "escalation rule", ID: id}
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *escalationService) GetEscalationRules() ([]*models.EscalationRule, error) {
+	return s.ruleRepo.GetAll()
+}
+
+// validate checks the rule's condition and that it takes at least one
+// action; tickets are only reassigned to managers
+func (s *escalationService) validate(rule *models.EscalationRule) error {
+	if rule.Name == "" {
+		return &ValidationError{Message: "escalation rule name cannot be empty"}
+	}
+	if rule.AfterMinutes < 0 {
+		return &ValidationError{Message: "afterMinutes cannot be negative"}
+	}
+	if rule.Condition.IsTimed() && rule.AfterMinutes < 1 {
+		return &ValidationError{Message: fmt.Sprintf("%s rules need afterMinutes of at least 1", rule.Condition)}
+	}
+	if !rule.BumpPriority && rule.EscalateToID == nil && rule.Comment == "" && !rule.Notify {
+		return &ValidationError{Message: "an escalation rule needs at least one action"}
+	}
+	if rule.EscalateToID != nil {
+		user, err := s.userRepo.GetByID(*rule.EscalateToID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "user", ID: *rule.EscalateToID}
+			}
+			return err
+		}
+		if user.Role != models.UserRoleManager {
+			return &ValidationError{Message: "tickets can only be escalated to a manager"}
+		}
+	}
+	return nil
+}
+
+// escalationEvent records an escalation action in the ticket history
+func escalationEvent(ticket *models.Ticket, field, value string) *models.TicketEvent {
+	return &models.TicketEvent{
+		TicketID: ticket.ID,
+		Field:    field,
+		NewValue: stringValue(value),
+	}
+}
+
+// Input types for service layer
+type CreateEscalationRuleInput struct {
+	Name         string                     `json:"name"`
+	Condition    models.EscalationCondition `json:"condition"`
+	Priority     *models.TicketPriority     `json:"priority,omitempty"`
+	AfterMinutes int                        `json:"afterMinutes"`
+	Active       *bool                      `json:"active,omitempty"`
+	BumpPriority bool                       `json:"bumpPriority"`
+	EscalateToID *uuid.UUID                 `json:"escalateToId,omitempty"`
+	Comment      string                     `json:"comment"`
+	Notify       bool                       `json:"notify"`
+}
+
+type UpdateEscalationRuleInput struct {
+	Name         *string    `json:"name,omitempty"`
+	AfterMinutes *int       `json:"afterMinutes,omitempty"`
+	Active       *bool      `json:"active,omitempty"`
+	BumpPriority *bool      `json:"bumpPriority,omitempty"`
+	EscalateToID *uuid.UUID `json:"escalateToId,omitempty"`
+	Comment      *string    `json:"comment,omitempty"`
+	Notify       *bool      `json:"notify,omitempty"`
+}
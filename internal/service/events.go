@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// eventSink hands the events of a write to their consumers. Durable consumers
+// read them from the outbox, written inside the write's transaction, so
+// events exist exactly when the write committed. Live subscribers are
+// notified directly once it has.
+type eventSink struct {
+	outbox    repository.OutboxRepository
+	publisher events.Publisher
+}
+
+func newEventSink(outbox repository.OutboxRepository, publisher events.Publisher) eventSink {
+	return eventSink{outbox: outbox, publisher: publisher}
+}
+
+// record adds events to the outbox as part of tx
+func (e eventSink) record(tx *gorm.DB, published ...events.Event) error {
+	return e.outbox.WithTx(tx).Add(published...)
+}
+
+// publish notifies live subscribers of events whose transaction committed
+func (e eventSink) publish(published ...events.Event) {
+	e.publisher.Publish(published...)
+}
+
+// ticketCreatedEvents returns the events published for a new ticket
+func ticketCreatedEvents(ticket *models.Ticket) []events.Event {
+	published := []events.Event{{Type: events.TicketCreated, TicketID: &ticket.ID}}
+	if ticket.AssignedToID != nil {
+		published = append(published, events.Event{
+			Type:       events.TicketAssigned,
+			TicketID:   &ticket.ID,
+			AssigneeID: ticket.AssignedToID,
+		})
+	}
+	return published
+}
+
+// ticketUpdatedEvents returns the events published for a change to a ticket
+func ticketUpdatedEvents(before, after *models.Ticket) []events.Event {
+	published := []events.Event{{Type: events.TicketUpdated, TicketID: &after.ID}}
+	if after.AssignedToID != nil && !sameID(before.AssignedToID, after.AssignedToID) {
+		published = append(published, events.Event{
+			Type:       events.TicketAssigned,
+			TicketID:   &after.ID,
+			AssigneeID: after.AssignedToID,
+		})
+	}
+	if before.Status != after.Status {
+		published = append(published, events.Event{
+			Type:           events.TicketStatusChanged,
+			TicketID:       &after.ID,
+			AssetID:        after.AssetID,
+			PreviousStatus: string(before.Status),
+			Status:         string(after.Status),
+		})
+	}
+	return published
+}
+
+// assetStatusEvents returns the events published when an asset's status
+// changes from previous
+func assetStatusEvents(asset *models.Asset, previous models.AssetStatus) []events.Event {
+	if asset.Status == previous {
+		return nil
+	}
+	return []events.Event{{
+		Type:           events.AssetStatusChanged,
+		AssetID:        &asset.ID,
+		PreviousStatus: string(previous),
+		Status:         string(asset.Status),
+	}}
+}
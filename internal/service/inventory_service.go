@@ -0,0 +1,247 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type InventoryService interface {
+	CreatePart(actor *models.User, input *CreatePartInput) (*models.Part, error)
+	UpdatePart(actor *models.User, id uuid.UUID, input *UpdatePartInput) (*models.Part, error)
+	RestockPart(actor *models.User, id uuid.UUID, quantity int, note string) (*models.Part, error)
+	AdjustStock(actor *models.User, id uuid.UUID, change int, note string) (*models.Part, error)
+	GetPart(id uuid.UUID) (*models.Part, error)
+	GetParts(filter *models.PartFilter, page *models.PageArgs) (*models.Page[*models.Part], error)
+	GetStockMovements(partID uuid.UUID) ([]*models.StockMovement, error)
+}
+
+type inventoryService struct {
+	tx           repository.Transactor
+	clock        clock.Clock
+	partRepo     repository.PartRepository
+	movementRepo repository.StockMovementRepository
+	stock        *stockKeeper
+	events       eventSink
+}
+
+func NewInventoryService(tx repository.Transactor, clk clock.Clock, partRepo repository.PartRepository, movementRepo repository.StockMovementRepository, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, assetRepo repository.AssetRepository, slaPolicyRepo repository.SLAPolicyRepository, calendarRepo repository.BusinessCalendarRepository, outboxRepo repository.OutboxRepository, publisher events.Publisher) InventoryService {
+	return &inventoryService{
+		tx:           tx,
+		clock:        clk,
+		partRepo:     partRepo,
+		movementRepo: movementRepo,
+		stock:        newStockKeeper(clk, partRepo, movementRepo, ticketRepo, eventRepo, newSLAPlanner(slaPolicyRepo, assetRepo, calendarRepo)),
+		events:       newEventSink(outboxRepo, publisher),
+	}
+}
+
+func (s *inventoryService) CreatePart(actor *models.User, input *CreatePartInput) (*models.Part, error) {
+	if err := Authorize(actor, ActionManageParts); err != nil {
+		return nil, err
+	}
+	if input.Quantity < 0 || input.MinimumQuantity < 0 {
+		return nil, &ValidationError{Message: "quantities cannot be negative"}
+	}
+
+	part := &models.Part{
+		Name:            input.Name,
+		Description:     input.Description,
+		MinimumQuantity: input.MinimumQuantity,
+		Location:        input.Location,
+		LastRestocked:   s.clock.Now(),
+	}
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) (err error) {
+		if err := s.partRepo.WithTx(tx).Create(part); err != nil {
+			return err
+		}
+		if input.Quantity > 0 {
+			_, published, err = s.stock.move(tx, stockChange{
+				PartID:  part.ID,
+				Delta:   input.Quantity,
+				Reason:  models.StockMovementReasonInitial,
+				ActorID: actor.ID,
+			})
+		} else {
+			published, err = s.stock.checkReorder(tx, part, actor.ID)
+		}
+		if err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.partRepo.GetByID(part.ID)
+}
+
+func (s *inventoryService) UpdatePart(actor *models.User, id uuid.UUID, input *UpdatePartInput) (*models.Part, error) {
+	if err := Authorize(actor, ActionManageParts); err != nil {
+		return nil, err
+	}
+	part, err := s.getPart(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		part.Name = *input.Name
+	}
+	if input.Description != nil {
+		part.Description = *input.Description
+	}
+	if input.Location != nil {
+		part.Location = *input.Location
+	}
+	if input.MinimumQuantity != nil {
+		if *input.MinimumQuantity < 0 {
+			return nil, &ValidationError{Message: "quantities cannot be negative"}
+		}
+		part.MinimumQuantity = *input.MinimumQuantity
+	}
+
+	var published []events.Event
+	err = s.tx.Transaction(func(tx *gorm.DB) (err error) {
+		if err := s.partRepo.WithTx(tx).Update(part); err != nil {
+			return err
+		}
+		if published, err = s.stock.checkReorder(tx, part, actor.ID); err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.partRepo.GetByID(part.ID)
+}
+
+// RestockPart adds received stock to a part and records when it was restocked
+func (s *inventoryService) RestockPart(actor *models.User, id uuid.UUID, quantity int, note string) (*models.Part, error) {
+	if err := Authorize(actor, ActionRestockPart); err != nil {
+		return nil, err
+	}
+	if quantity < 1 {
+		return nil, &ValidationError{Message: "restock quantity must be at least 1"}
+	}
+	if _, err := s.getPart(id); err != nil {
+		return nil, err
+	}
+
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		part, movePublished, err := s.stock.move(tx, stockChange{
+			PartID:  id,
+			Delta:   quantity,
+			Reason:  models.StockMovementReasonRestock,
+			ActorID: actor.ID,
+			Note:    strings.TrimSpace(note),
+		})
+		if err != nil {
+			return err
+		}
+		published = movePublished
+		part.LastRestocked = s.clock.Now()
+		if err := s.partRepo.WithTx(tx).Update(part); err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.partRepo.GetByID(id)
+}
+
+// AdjustStock corrects a part's stock by change, e.g. after a stock count.
+// A note explaining the adjustment is required.
+func (s *inventoryService) AdjustStock(actor *models.User, id uuid.UUID, change int, note string) (*models.Part, error) {
+	if err := Authorize(actor, ActionManageParts); err != nil {
+		return nil, err
+	}
+	note = strings.TrimSpace(note)
+	if change == 0 {
+		return nil, &ValidationError{Message: "adjustment must change the quantity"}
+	}
+	if note == "" {
+		return nil, &ValidationError{Message: "a note is required to adjust stock"}
+	}
+	if _, err := s.getPart(id); err != nil {
+		return nil, err
+	}
+
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) (err error) {
+		_, published, err = s.stock.move(tx, stockChange{
+			PartID:  id,
+			Delta:   change,
+			Reason:  models.StockMovementReasonAdjustment,
+			ActorID: actor.ID,
+			Note:    note,
+		})
+		if err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.partRepo.GetByID(id)
+}
+
+func (s *inventoryService) GetPart(id uuid.UUID) (*models.Part, error) {
+	return s.partRepo.GetByID(id)
+}
+
+func (s *inventoryService) GetParts(filter *models.PartFilter, page *models.PageArgs) (*models.Page[*models.Part], error) {
+	return s.partRepo.GetAll(filter, page)
+}
+
+func (s *inventoryService) GetStockMovements(partID uuid.UUID) ([]*models.StockMovement, error) {
+	return s.movementRepo.GetByPartID(partID)
+}
+
+// getPart loads a part, reporting a NotFoundError when it does not exist
+func (s *inventoryService) getPart(id uuid.UUID) (*models.Part, error) {
+	part, err := s.partRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "part", ID: id}
+		}
+		return nil, err
+	}
+	return part, nil
+}
+
+// Input types for service layer
+type CreatePartInput struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Quantity        int    `json:"quantity"`
+	MinimumQuantity int    `json:"minimumQuantity"`
+	Location        string `json:"location"`
+}
+
+type UpdatePartInput struct {
+	Name            *string `json:"name,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	MinimumQuantity *int    `json:"minimumQuantity,omitempty"`
+	Location        *string `json:"location,omitempty"`
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type LocationService interface {
+	CreateLocation(actor *models.User, input *CreateLocationInput) (*models.Location, error)
+	UpdateLocation(actor *models.User, id uuid.UUID, input *UpdateLocationInput) (*models.Location, error)
+	DeleteLocation(actor *models.User, id uuid.UUID) error
+	GetLocation(id uuid.UUID) (*models.Location, error)
+	// GetLocations returns the locations directly under parentID, or the
+	// sites when it is nil
+	GetLocations(parentID *uuid.UUID) ([]*models.Location, error)
+	// GetLocationPath returns the location and its ancestors, root first
+	GetLocationPath(id uuid.UUID) ([]*models.Location, error)
+}
+
+type locationService struct {
+	locationRepo repository.LocationRepository
+}
+
+func NewLocationService(locationRepo repository.LocationRepository) LocationService {
+	return &locationService{locationRepo: locationRepo}
+}
+
+func (s *locationService) CreateLocation(actor *models.User, input *CreateLocationInput) (*models.Location, error) {
+	if err := Authorize(actor, ActionManageLocations); err != nil {
+		return nil, err
+	}
+	location := &models.Location{
+		Name:     strings.TrimSpace(input.Name),
+		Kind:     input.Kind,
+		ParentID: input.ParentID,
+	}
+	if err := s.validate(location); err != nil {
+		return nil, err
+	}
+	if err := s.locationRepo.Create(location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// UpdateLocation renames a location or moves it, with everything under it,
+// to another parent of the same kind
+func (s *locationService) UpdateLocation(actor *models.User, id uuid.UUID, input *UpdateLocationInput) (*models.Location, error) {
+	if err := Authorize(actor, ActionManageLocations); err != nil {
+		return nil, err
+	}
+	location, err := s.GetLocation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		location.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.ParentID != nil {
+		location.ParentID = input.ParentID
+		location.Parent = nil
+	}
+	if err := s.validate(location); err != nil {
+		return nil, err
+	}
+	if err := s.locationRepo.Update(location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// DeleteLocation deletes a location that nothing is placed in
+func (s *locationService) DeleteLocation(actor *models.User, id uuid.UUID) error {
+	if err := Authorize(actor, ActionManageLocations); err != nil {
+		return err
+	}
+	if _, err := s.GetLocation(id); err != nil {
+		return err
+	}
+	inUse, err := s.locationRepo.InUse(id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return &ValidationError{Message: "cannot delete a location that contains locations or assets"}
+	}
+	return s.locationRepo.Delete(id)
+}
+
+func (s *locationService) GetLocation(id uuid.UUID) (*models.Location, error) {
+	location, err := s.locationRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "location", ID: id}
+		}
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *locationService) GetLocations(parentID *uuid.UUID) ([]*models.Location, error) {
+	return s.locationRepo.GetChildren(parentID)
+}
+
+func (s *locationService) GetLocationPath(id uuid.UUID) ([]*models.Location, error) {
+	return s.locationRepo.GetAncestors(id)
+}
+
+// validate checks that the location sits directly under a location of the
+// kind above its own, sites being roots, and that no sibling has its name.
+// Kinds only nest downwards, so a move can never create a cycle.
+func (s *locationService) validate(location *models.Location) error {
+	if location.Name == "" {
+		return &ValidationError{Message: "location name cannot be empty"}
+	}
+	parentKind, nested := location.Kind.ParentKind()
+	switch {
+	case !nested && location.ParentID != nil:
+		return &ValidationError{Message: "a site cannot have a parent location"}
+	case nested && location.ParentID == nil:
+		return &ValidationError{Message: fmt.Sprintf("a %s must be placed in a %s", strings.ToLower(string(location.Kind)), strings.ToLower(string(parentKind)))}
+	case nested:
+		parent, err := s.GetLocation(*location.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.Kind != parentKind {
+			return &ValidationError{Message: fmt.Sprintf("a %s must be placed in a %s, not a %s", strings.ToLower(string(location.Kind)), strings.ToLower(string(parentKind)), strings.ToLower(string(parent.Kind)))}
+		}
+	}
+
+	sibling, err := s.locationRepo.FindChild(location.ParentID, location.Name)
+	if err != nil {
+		return err
+	}
+	if sibling != nil && sibling.ID != location.ID {
+		return &ValidationError{Message: fmt.Sprintf("a location named %q already exists there", sibling.Name)}
+	}
+	return nil
+}
+
+// Input types for service layer
+type CreateLocationInput struct {
+	Name     string              `json:"name"`
+	Kind     models.LocationKind `json:"kind"`
+	ParentID *uuid.UUID          `json:"parentId,omitempty"`
+}
+
+type UpdateLocationInput struct {
+	Name     *string    `json:"name,omitempty"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type MaintenanceService interface {
+	ProcessSchedules() (*MaintenanceRun, error)
+	RecordMaintenance(actor *models.User, input *RecordMaintenanceInput) (*models.MaintenanceRecord, error)
+	GetRecord(id uuid.UUID) (*models.MaintenanceRecord, error)
+	GetAssetHistory(assetID uuid.UUID) ([]*models.MaintenanceRecord, error)
+	NextDue(assetID uuid.UUID, frequency models.MaintenanceFrequency, from time.Time) (time.Time, error)
+}
+
+// MaintenanceRun summarizes one pass over the maintenance schedules
+type MaintenanceRun struct {
+	TicketsCreated int
+	MarkedOverdue  int
+	Completed      int
+	Skipped        int
+}
+
+type maintenanceService struct {
+	tx           repository.Transactor
+	clock        clock.Clock
+	leadTime     time.Duration
+	scheduleRepo repository.MaintenanceScheduleRepository
+	recordRepo   repository.MaintenanceRecordRepository
+	ticketRepo   repository.TicketRepository
+	eventRepo    repository.TicketEventRepository
+	assetRepo    repository.AssetRepository
+	stock        *stockKeeper
+	sla          *slaPlanner
+	calendars    *calendarLookup
+	coupler      *assetCoupler
+	events       eventSink
+}
+
+// NewMaintenanceService creates the service driving maintenance schedules.
+// Tickets are generated leadTime before an occurrence is due.
+func NewMaintenanceService(tx repository.Transactor, clk clock.Clock, leadTime time.Duration, scheduleRepo repository.MaintenanceScheduleRepository, recordRepo repository.MaintenanceRecordRepository, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, assetRepo repository.AssetRepository, partRepo repository.PartRepository, movementRepo repository.StockMovementRepository, slaPolicyRepo repository.SLAPolicyRepository, calendarRepo repository.BusinessCalendarRepository, statusRepo repository.AssetStatusChangeRepository, coupling AssetCouplingRules, outboxRepo repository.OutboxRepository, publisher events.Publisher) MaintenanceService {
+	sla := newSLAPlanner(slaPolicyRepo, assetRepo, calendarRepo)
+	return &maintenanceService{
+		tx:           tx,
+		clock:        clk,
+		leadTime:     leadTime,
+		scheduleRepo: scheduleRepo,
+		recordRepo:   recordRepo,
+		ticketRepo:   ticketRepo,
+		eventRepo:    eventRepo,
+		assetRepo:    assetRepo,
+		stock:        newStockKeeper(clk, partRepo, movementRepo, ticketRepo, eventRepo, sla),
+		sla:          sla,
+		calendars:    newCalendarLookup(calendarRepo, assetRepo),
+		coupler:      newAssetCoupler(coupling, ticketRepo, assetRepo, statusRepo),
+		events:       newEventSink(outboxRepo, publisher),
+	}
+}
+
+// ProcessSchedules advances every active schedule:
+//   - an occurrence whose ticket was resolved or closed is completed, writing a
+//     MaintenanceRecord and moving NextDue on by the schedule's frequency
+//   - an occurrence whose ticket was cancelled or deleted is skipped
+//   - a PREVENTIVE ticket is created for the assignee once NextDue is within
+//     the lead time
+//   - a SCHEDULED occurrence past NextDue is marked OVERDUE
+//
+// Each schedule is claimed with a row lock and decided on its locked row, so
+// schedulers running on several replicas never act on the same occurrence
+// twice. A failing schedule does not stop the others; all errors are
+// returned joined.
+func (s *maintenanceService) ProcessSchedules() (*MaintenanceRun, error) {
+	schedules, err := s.scheduleRepo.GetActive()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	run := &MaintenanceRun{}
+	var errs []error
+	for _, schedule := range schedules {
+		if err := s.process(schedule.ID, now, run); err != nil {
+			errs = append(errs, fmt.Errorf("maintenance schedule %s: %w", schedule.ID, err))
+		}
+	}
+	return run, errors.Join(errs...)
+}
+
+// process claims one schedule and advances it in a single transaction. It
+// does nothing when the schedule is held by another scheduler or is no longer
+// active.
+func (s *maintenanceService) process(id uuid.UUID, now time.Time, run *MaintenanceRun) error {
+	var step MaintenanceRun
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		schedule, err := s.scheduleRepo.WithTx(tx).Claim(id)
+		if err != nil || schedule == nil {
+			return err
+		}
+
+		if schedule.TicketID != nil {
+			switch {
+			case schedule.Ticket == nil, schedule.Ticket.Status == models.TicketStatusCancelled:
+				step.Skipped++
+				return s.skip(tx, schedule)
+			case schedule.Ticket.Status == models.TicketStatusResolved, schedule.Ticket.Status == models.TicketStatusClosed:
+				step.Completed++
+				return s.complete(tx, schedule, now)
+			}
+		}
+
+		if schedule.TicketID == nil && !now.Before(schedule.NextDue.Add(-s.leadTime)) {
+			created, err := s.createTicket(tx, schedule, now)
+			if err != nil {
+				return err
+			}
+			published = append(published, created...)
+			step.TicketsCreated++
+		}
+
+		if schedule.Status == models.MaintenanceStatusScheduled && now.After(schedule.NextDue) {
+			overdue, err := s.markOverdue(tx, schedule)
+			if err != nil {
+				return err
+			}
+			published = append(published, overdue)
+			step.MarkedOverdue++
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return err
+	}
+	s.events.publish(published...)
+	run.TicketsCreated += step.TicketsCreated
+	run.MarkedOverdue += step.MarkedOverdue
+	run.Completed += step.Completed
+	run.Skipped += step.Skipped
+	return nil
+}
+
+// markOverdue flags a schedule whose occurrence was missed
+func (s *maintenanceService) markOverdue(tx *gorm.DB, schedule *models.MaintenanceSchedule) (events.Event, error) {
+	schedule.Status = models.MaintenanceStatusOverdue
+	overdue := events.Event{
+		Type:       events.MaintenanceOverdue,
+		ScheduleID: &schedule.ID,
+		AssetID:    &schedule.AssetID,
+		AssigneeID: &schedule.AssignedToID,
+		TicketID:   schedule.TicketID,
+	}
+	return overdue, s.scheduleRepo.WithTx(tx).Update(schedule)
+}
+
+// createTicket opens the PREVENTIVE ticket for the schedule's current
+// occurrence and returns the events to publish once tx commits
+func (s *maintenanceService) createTicket(tx *gorm.DB, schedule *models.MaintenanceSchedule, now time.Time) ([]events.Event, error) {
+	description := fmt.Sprintf("%s preventive maintenance due %s.",
+		schedule.Frequency, schedule.NextDue.Format(time.RFC1123))
+	if schedule.Notes != "" {
+		description += "\n\n" + schedule.Notes
+	}
+	assignee := schedule.AssignedToID
+	ticket := &models.Ticket{
+		Title:        fmt.Sprintf("Preventive maintenance: %s", schedule.Asset.Name),
+		Description:  description,
+		Status:       models.TicketStatusOpen,
+		Priority:     models.TicketPriorityMedium,
+		AssignedToID: &assignee,
+		CreatedByID:  schedule.AssignedToID,
+		AssetID:      &schedule.AssetID,
+	}
+	if err := s.sla.plan(ticket, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.ticketRepo.WithTx(tx).Create(ticket); err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.WithTx(tx).Create(creationEvent(ticket, nil)); err != nil {
+		return nil, err
+	}
+	schedule.TicketID = &ticket.ID
+	if err := s.scheduleRepo.WithTx(tx).Update(schedule); err != nil {
+		return nil, err
+	}
+	coupled, err := s.coupler.apply(tx, ticket, nil, nil, now)
+	if err != nil {
+		return nil, err
+	}
+	return append(ticketCreatedEvents(ticket), coupled...), nil
+}
+
+// complete records the maintenance performed through the schedule's ticket and
+// schedules the next occurrence
+func (s *maintenanceService) complete(tx *gorm.DB, schedule *models.MaintenanceSchedule, now time.Time) error {
+	performedAt := now
+	if schedule.Ticket.ResolvedAt != nil {
+		performedAt = *schedule.Ticket.ResolvedAt
+	}
+	performedBy := schedule.AssignedToID
+	if schedule.Ticket.AssignedToID != nil {
+		performedBy = *schedule.Ticket.AssignedToID
+	}
+	record := &models.MaintenanceRecord{
+		AssetID:       schedule.AssetID,
+		PerformedByID: performedBy,
+		PerformedAt:   performedAt,
+		Type:          models.MaintenanceTypePreventive,
+		Notes:         schedule.Notes,
+	}
+
+	nextDue, err := s.NextDue(schedule.AssetID, schedule.Frequency, performedAt)
+	if err != nil {
+		return err
+	}
+	if err := s.recordRepo.WithTx(tx).Create(record); err != nil {
+		return err
+	}
+	schedule.LastPerformed = &performedAt
+	schedule.NextDue = nextDue
+	return s.advance(tx, schedule, &performedAt)
+}
+
+// skip drops the schedule's current occurrence without recording maintenance
+func (s *maintenanceService) skip(tx *gorm.DB, schedule *models.MaintenanceSchedule) error {
+	nextDue, err := s.NextDue(schedule.AssetID, schedule.Frequency, schedule.NextDue)
+	if err != nil {
+		return err
+	}
+	schedule.NextDue = nextDue
+	return s.advance(tx, schedule, nil)
+}
+
+// advance resets the schedule for its next occurrence and refreshes the
+// asset's maintenance dates, moving the last one forward to performedAt when
+// maintenance was performed
+func (s *maintenanceService) advance(tx *gorm.DB, schedule *models.MaintenanceSchedule, performedAt *time.Time) error {
+	asset, err := s.assetRepo.WithTx(tx).GetForUpdate(schedule.AssetID)
+	if err != nil {
+		return err
+	}
+	scheduleRepo := s.scheduleRepo.WithTx(tx)
+	schedule.Status = models.MaintenanceStatusScheduled
+	schedule.TicketID = nil
+	schedule.Ticket = nil
+	if err := scheduleRepo.Update(schedule); err != nil {
+		return err
+	}
+	next, err := scheduleRepo.NextDueForAsset(schedule.AssetID)
+	if err != nil {
+		return err
+	}
+	return s.assetRepo.WithTx(tx).UpdateMaintenanceDates(schedule.AssetID, latestDate(asset.LastMaintenanceDate, performedAt), next)
+}
+
+// latestDate returns the later of two optional dates
+func latestDate(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// RecordMaintenance records maintenance performed by actor on an asset. The
+// record, its part usages and the stock decrements are written in one
+// transaction, so the whole record is rejected if any part is short.
+func (s *maintenanceService) RecordMaintenance(actor *models.User, input *RecordMaintenanceInput) (*models.MaintenanceRecord, error) {
+	if err := Authorize(actor, ActionRecordMaintenance); err != nil {
+		return nil, err
+	}
+	for _, line := range input.Parts {
+		if line.Quantity < 1 {
+			return nil, &ValidationError{Message: "part quantity must be at least 1"}
+		}
+	}
+	now := s.clock.Now()
+	performedAt := now
+	if input.PerformedAt != nil {
+		if input.PerformedAt.After(now) {
+			return nil, &ValidationError{Message: "performedAt cannot be in the future"}
+		}
+		performedAt = *input.PerformedAt
+	}
+
+	record := &models.MaintenanceRecord{
+		AssetID:       input.AssetID,
+		PerformedByID: actor.ID,
+		PerformedAt:   performedAt,
+		Type:          input.Type,
+		Notes:         input.Notes,
+	}
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		// The asset is locked so its maintenance dates are updated from
+		// what they are now, not from a read a concurrent write superseded
+		asset, err := s.assetRepo.WithTx(tx).GetForUpdate(input.AssetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "asset", ID: input.AssetID}
+			}
+			return err
+		}
+		recordRepo := s.recordRepo.WithTx(tx)
+		if err := recordRepo.Create(record); err != nil {
+			return err
+		}
+
+		usages := make([]*models.PartUsage, 0, len(input.Parts))
+		for _, line := range input.Parts {
+			_, movePublished, err := s.stock.move(tx, stockChange{
+				PartID:              line.PartID,
+				Delta:               -line.Quantity,
+				Reason:              models.StockMovementReasonConsumption,
+				ActorID:             actor.ID,
+				MaintenanceRecordID: &record.ID,
+			})
+			if err != nil {
+				return err
+			}
+			published = append(published, movePublished...)
+			usages = append(usages, &models.PartUsage{
+				PartID:              line.PartID,
+				MaintenanceRecordID: record.ID,
+				Quantity:            line.Quantity,
+			})
+		}
+		if err := recordRepo.CreatePartUsages(usages); err != nil {
+			return err
+		}
+
+		last := latestDate(asset.LastMaintenanceDate, &performedAt)
+		if err := s.assetRepo.WithTx(tx).UpdateMaintenanceDates(asset.ID, last, asset.NextMaintenanceDate); err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.publish(published...)
+
+	return s.recordRepo.GetByID(record.ID)
+}
+
+func (s *maintenanceService) GetRecord(id uuid.UUID) (*models.MaintenanceRecord, error) {
+	return s.recordRepo.GetByID(id)
+}
+
+func (s *maintenanceService) GetAssetHistory(assetID uuid.UUID) ([]*models.MaintenanceRecord, error) {
+	return s.recordRepo.GetByAssetID(assetID)
+}
+
+// NextDue returns when maintenance at frequency is next due on the asset
+// after from, moved forward to working time in the asset's business calendar
+func (s *maintenanceService) NextDue(assetID uuid.UUID, frequency models.MaintenanceFrequency, from time.Time) (time.Time, error) {
+	cal, err := s.calendars.forAsset(&assetID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cal.Next(frequency.Next(from)), nil
+}
+
+// Input types for service layer
+type RecordMaintenanceInput struct {
+	AssetID     uuid.UUID              `json:"assetId"`
+	Type        models.MaintenanceType `json:"type"`
+	PerformedAt *time.Time             `json:"performedAt,omitempty"`
+	Notes       string                 `json:"notes"`
+	Parts       []PartUsageInput       `json:"parts"`
+}
+
+type PartUsageInput struct {
+	PartID   uuid.UUID `json:"partId"`
+	Quantity int       `json:"quantity"`
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// The fakes below keep rows in memory and implement only the repository
+// methods the tests use; the embedded interfaces are nil, so any other
+// call panics.
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) Transaction(fn func(tx *gorm.DB) error) error {
+	return fn(nil)
+}
+
+type fakeScheduleRepo struct {
+	repository.MaintenanceScheduleRepository
+	schedules map[uuid.UUID]*models.MaintenanceSchedule
+	tickets   *fakeTicketRepo
+	assets    *fakeAssetRepo
+	// locked holds the schedules another scheduler has claimed
+	locked map[uuid.UUID]bool
+}
+
+func (r *fakeScheduleRepo) WithTx(*gorm.DB) repository.MaintenanceScheduleRepository { return r }
+
+func (r *fakeScheduleRepo) GetActive() ([]*models.MaintenanceSchedule, error) {
+	var active []*models.MaintenanceSchedule
+	for _, schedule := range r.schedules {
+		copied := *schedule
+		active = append(active, &copied)
+	}
+	return active, nil
+}
+
+func (r *fakeScheduleRepo) Claim(id uuid.UUID) (*models.MaintenanceSchedule, error) {
+	schedule, ok := r.schedules[id]
+	if !ok || r.locked[id] {
+		return nil, nil
+	}
+	claimed := *schedule
+	claimed.Asset = *r.assets.assets[schedule.AssetID]
+	if claimed.TicketID != nil {
+		if ticket, ok := r.tickets.tickets[*claimed.TicketID]; ok {
+			copied := *ticket
+			claimed.Ticket = &copied
+		}
+	}
+	return &claimed, nil
+}
+
+func (r *fakeScheduleRepo) Update(schedule *models.MaintenanceSchedule) error {
+	stored := *schedule
+	stored.Asset, stored.Ticket = models.Asset{}, nil
+	r.schedules[schedule.ID] = &stored
+	return nil
+}
+
+func (r *fakeScheduleRepo) NextDueForAsset(assetID uuid.UUID) (*time.Time, error) {
+	var next *time.Time
+	for _, schedule := range r.schedules {
+		if schedule.AssetID == assetID && (next == nil || schedule.NextDue.Before(*next)) {
+			due := schedule.NextDue
+			next = &due
+		}
+	}
+	return next, nil
+}
+
+type fakeTicketRepo struct {
+	repository.TicketRepository
+	tickets map[uuid.UUID]*models.Ticket
+}
+
+func (r *fakeTicketRepo) WithTx(*gorm.DB) repository.TicketRepository { return r }
+
+func (r *fakeTicketRepo) Create(ticket *models.Ticket) error {
+	ticket.ID = uuid.New()
+	stored := *ticket
+	r.tickets[ticket.ID] = &stored
+	return nil
+}
+
+type fakeAssetRepo struct {
+	repository.AssetRepository
+	assets map[uuid.UUID]*models.Asset
+}
+
+func (r *fakeAssetRepo) WithTx(*gorm.DB) repository.AssetRepository { return r }
+
+func (r *fakeAssetRepo) GetByID(id uuid.UUID) (*models.Asset, error) {
+	asset, ok := r.assets[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *asset
+	return &copied, nil
+}
+
+func (r *fakeAssetRepo) UpdateMaintenanceDates(id uuid.UUID, last, next *time.Time) error {
+	r.assets[id].LastMaintenanceDate = last
+	r.assets[id].NextMaintenanceDate = next
+	return nil
+}
+
+type fakeRecordRepo struct {
+	repository.MaintenanceRecordRepository
+	records []*models.MaintenanceRecord
+}
+
+func (r *fakeRecordRepo) WithTx(*gorm.DB) repository.MaintenanceRecordRepository { return r }
+
+func (r *fakeRecordRepo) Create(record *models.MaintenanceRecord) error {
+	record.ID = uuid.New()
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *fakeRecordRepo) CreatePartUsages([]*models.PartUsage) error { return nil }
+
+func (r *fakeRecordRepo) GetByID(id uuid.UUID) (*models.MaintenanceRecord, error) {
+	for _, record := range r.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeTicketEventRepo struct {
+	repository.TicketEventRepository
+}
+
+func (r *fakeTicketEventRepo) WithTx(*gorm.DB) repository.TicketEventRepository { return r }
+
+func (r *fakeTicketEventRepo) Create(...*models.TicketEvent) error { return nil }
+
+type fakeSLAPolicyRepo struct {
+	repository.SLAPolicyRepository
+	policies map[models.TicketPriority]*models.SLAPolicy
+}
+
+func (r fakeSLAPolicyRepo) Match(priority models.TicketPriority, _ *models.AssetType) (*models.SLAPolicy, error) {
+	return r.policies[priority], nil
+}
+
+type fakeCalendarRepo struct {
+	repository.BusinessCalendarRepository
+	calendar *models.BusinessCalendar
+}
+
+func (r fakeCalendarRepo) ForLocation(string) (*models.BusinessCalendar, error) { return r.calendar, nil }
+
+type fakeOutbox struct {
+	repository.OutboxRepository
+	recorded []events.Event
+}
+
+func (o *fakeOutbox) WithTx(*gorm.DB) repository.OutboxRepository { return o }
+
+func (o *fakeOutbox) Add(published ...events.Event) error {
+	o.recorded = append(o.recorded, published...)
+	return nil
+}
+
+type fakePublisher struct {
+	published []events.Event
+}
+
+func (p *fakePublisher) Publish(published ...events.Event) {
+	p.published = append(p.published, published...)
+}
+
+// maintenanceFixture is a maintenance service over the fakes with one weekly
+// schedule for one asset
+type maintenanceFixture struct {
+	clock     *clock.Fake
+	service   MaintenanceService
+	schedules *fakeScheduleRepo
+	tickets   *fakeTicketRepo
+	assets    *fakeAssetRepo
+	records   *fakeRecordRepo
+	publisher *fakePublisher
+	schedule  uuid.UUID
+}
+
+const maintenanceLeadTime = 48 * time.Hour
+
+func newMaintenanceFixture(nextDue time.Time) *maintenanceFixture {
+	asset := &models.Asset{Base: models.Base{ID: uuid.New()}, Name: "Chiller 2", Status: models.AssetStatusOperational}
+	schedule := &models.MaintenanceSchedule{
+		Base:         models.Base{ID: uuid.New()},
+		AssetID:      asset.ID,
+		Frequency:    models.MaintenanceFrequencyWeekly,
+		NextDue:      nextDue,
+		AssignedToID: uuid.New(),
+		Status:       models.MaintenanceStatusScheduled,
+	}
+
+	f := &maintenanceFixture{
+		clock:     clock.NewFake(nextDue.Add(-72 * time.Hour)),
+		tickets:   &fakeTicketRepo{tickets: map[uuid.UUID]*models.Ticket{}},
+		assets:    &fakeAssetRepo{assets: map[uuid.UUID]*models.Asset{asset.ID: asset}},
+		records:   &fakeRecordRepo{},
+		publisher: &fakePublisher{},
+		schedule:  schedule.ID,
+	}
+	f.schedules = &fakeScheduleRepo{
+		schedules: map[uuid.UUID]*models.MaintenanceSchedule{schedule.ID: schedule},
+		tickets:   f.tickets,
+		assets:    f.assets,
+		locked:    map[uuid.UUID]bool{},
+	}
+	f.service = NewMaintenanceService(fakeTransactor{}, f.clock, maintenanceLeadTime,
+		f.schedules, f.records, f.tickets, &fakeTicketEventRepo{}, f.assets,
+		nil, nil, fakeSLAPolicyRepo{}, fakeCalendarRepo{}, nil, AssetCouplingRules{},
+		&fakeOutbox{}, f.publisher)
+	return f
+}
+
+func (f *maintenanceFixture) run(t *testing.T, want MaintenanceRun) {
+	t.Helper()
+	got, err := f.service.ProcessSchedules()
+	if err != nil {
+		t.Fatalf("ProcessSchedules at %s: %v", f.clock.Now(), err)
+	}
+	if *got != want {
+		t.Fatalf("ProcessSchedules at %s = %+v, want %+v", f.clock.Now(), *got, want)
+	}
+}
+
+func (f *maintenanceFixture) current() *models.MaintenanceSchedule {
+	return f.schedules.schedules[f.schedule]
+}
+
+func (f *maintenanceFixture) setTicketStatus(status models.TicketStatus) {
+	f.tickets.tickets[*f.current().TicketID].Status = status
+}
+
+func TestProcessSchedulesLeadTimeAndOverdue(t *testing.T) {
+	due := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newMaintenanceFixture(due)
+
+	f.run(t, MaintenanceRun{})
+
+	f.clock.Set(due.Add(-maintenanceLeadTime - time.Second))
+	f.run(t, MaintenanceRun{})
+	if f.current().TicketID != nil {
+		t.Fatal("ticket created before the lead time")
+	}
+
+	f.clock.Set(due.Add(-maintenanceLeadTime))
+	f.run(t, MaintenanceRun{TicketsCreated: 1})
+	ticketID := f.current().TicketID
+	if ticketID == nil {
+		t.Fatal("no ticket on the schedule once within the lead time")
+	}
+	ticket := f.tickets.tickets[*ticketID]
+	if ticket.AssetID == nil || *ticket.AssetID != f.current().AssetID || *ticket.AssignedToID != f.current().AssignedToID {
+		t.Errorf("ticket %+v is not for the schedule's asset and assignee", ticket)
+	}
+
+	f.clock.Advance(time.Hour)
+	f.run(t, MaintenanceRun{})
+	if len(f.tickets.tickets) != 1 {
+		t.Fatalf("%d tickets after a second run, want 1", len(f.tickets.tickets))
+	}
+
+	f.clock.Set(due)
+	f.run(t, MaintenanceRun{})
+	if f.current().Status != models.MaintenanceStatusScheduled {
+		t.Fatalf("status %s at the due time, want SCHEDULED", f.current().Status)
+	}
+
+	f.clock.Set(due.Add(time.Minute))
+	f.run(t, MaintenanceRun{MarkedOverdue: 1})
+	if f.current().Status != models.MaintenanceStatusOverdue {
+		t.Fatalf("status %s past the due time, want OVERDUE", f.current().Status)
+	}
+	if !hasEvent(f.publisher.published, events.MaintenanceOverdue) {
+		t.Error("no overdue event published")
+	}
+
+	f.clock.Advance(time.Hour)
+	f.run(t, MaintenanceRun{})
+}
+
+func TestProcessSchedulesSkipsCancelledOccurrence(t *testing.T) {
+	due := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newMaintenanceFixture(due)
+
+	f.clock.Set(due.Add(time.Hour))
+	f.run(t, MaintenanceRun{TicketsCreated: 1, MarkedOverdue: 1})
+
+	f.setTicketStatus(models.TicketStatusCancelled)
+	f.run(t, MaintenanceRun{Skipped: 1})
+
+	next := due.AddDate(0, 0, 7)
+	schedule := f.current()
+	if !schedule.NextDue.Equal(next) || schedule.Status != models.MaintenanceStatusScheduled || schedule.TicketID != nil {
+		t.Fatalf("after skipping: next due %s, status %s, ticket %v; want %s, SCHEDULED, none",
+			schedule.NextDue, schedule.Status, schedule.TicketID, next)
+	}
+	if asset := f.assets.assets[schedule.AssetID]; asset.NextMaintenanceDate == nil || !asset.NextMaintenanceDate.Equal(next) {
+		t.Errorf("asset next maintenance %v, want %s", asset.NextMaintenanceDate, next)
+	}
+	if len(f.records.records) != 0 {
+		t.Errorf("%d maintenance records for a skipped occurrence", len(f.records.records))
+	}
+
+	f.run(t, MaintenanceRun{})
+	f.clock.Set(next.Add(-maintenanceLeadTime))
+	f.run(t, MaintenanceRun{TicketsCreated: 1})
+}
+
+func TestProcessSchedulesCompletesResolvedOccurrence(t *testing.T) {
+	due := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newMaintenanceFixture(due)
+
+	f.clock.Set(due.Add(-maintenanceLeadTime))
+	f.run(t, MaintenanceRun{TicketsCreated: 1})
+
+	resolvedAt := due.Add(-time.Hour)
+	f.tickets.tickets[*f.current().TicketID].ResolvedAt = &resolvedAt
+	f.setTicketStatus(models.TicketStatusResolved)
+	f.clock.Set(due.Add(time.Hour))
+	f.run(t, MaintenanceRun{Completed: 1})
+
+	schedule := f.current()
+	if want := resolvedAt.AddDate(0, 0, 7); !schedule.NextDue.Equal(want) {
+		t.Errorf("next due %s, want a week after the resolution, %s", schedule.NextDue, want)
+	}
+	if schedule.Status != models.MaintenanceStatusScheduled || schedule.TicketID != nil {
+		t.Errorf("after completing: status %s, ticket %v; want SCHEDULED, none", schedule.Status, schedule.TicketID)
+	}
+	if len(f.records.records) != 1 || !f.records.records[0].PerformedAt.Equal(resolvedAt) {
+		t.Fatalf("records %+v, want one performed at %s", f.records.records, resolvedAt)
+	}
+}
+
+func TestProcessSchedulesLeavesClaimedSchedule(t *testing.T) {
+	due := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newMaintenanceFixture(due)
+	f.schedules.locked[f.schedule] = true
+
+	f.clock.Set(due.Add(time.Hour))
+	f.run(t, MaintenanceRun{})
+	if len(f.tickets.tickets) != 0 || f.current().Status != models.MaintenanceStatusScheduled {
+		t.Fatal("a schedule held by another scheduler was processed")
+	}
+}
+
+func TestRecordMaintenanceKeepsLatestDate(t *testing.T) {
+	due := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	f := newMaintenanceFixture(due)
+	f.clock.Set(due)
+	assetID := f.current().AssetID
+	asset := f.assets.assets[assetID]
+	last := due.Add(-24 * time.Hour)
+	asset.LastMaintenanceDate, asset.NextMaintenanceDate = &last, &due
+	actor := &models.User{Base: models.Base{ID: uuid.New()}, Role: technician}
+
+	// maintenance logged late, after a later visit was recorded
+	earlier := due.Add(-72 * time.Hour)
+	if _, err := f.service.RecordMaintenance(actor, &RecordMaintenanceInput{AssetID: assetID, Type: models.MaintenanceTypeCorrective, PerformedAt: &earlier}); err != nil {
+		t.Fatal(err)
+	}
+	if !asset.LastMaintenanceDate.Equal(last) || !asset.NextMaintenanceDate.Equal(due) {
+		t.Errorf("maintenance dates %s, %s; want %s, %s unchanged", asset.LastMaintenanceDate, asset.NextMaintenanceDate, last, due)
+	}
+
+	if _, err := f.service.RecordMaintenance(actor, &RecordMaintenanceInput{AssetID: assetID, Type: models.MaintenanceTypeCorrective}); err != nil {
+		t.Fatal(err)
+	}
+	if !asset.LastMaintenanceDate.Equal(due) || !asset.NextMaintenanceDate.Equal(due) {
+		t.Errorf("maintenance dates %s, %s; want the last one moved to %s", asset.LastMaintenanceDate, asset.NextMaintenanceDate, due)
+	}
+	if len(f.records.records) != 2 {
+		t.Errorf("%d records, want 2", len(f.records.records))
+	}
+}
+
+func hasEvent(published []events.Event, eventType events.Type) bool {
+	for _, event := range published {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @user@example.com, @Name and @"Full Name" mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@(?:"([^"]+)"|([\w.+\-]+@[\w\-]+(?:\.[\w\-]+)+|\w[\w.\-]*))`)
+
+// mention is a reference to a user found in comment content
+type mention struct {
+	Email string
+	Name  string
+}
+
+// parseMentions returns the distinct users mentioned in content
+func parseMentions(content string) []mention {
+	var mentions []mention
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		var m mention
+		switch {
+		case match[1] != "":
+			m.Name = strings.TrimSpace(match[1])
+		case strings.Contains(match[2], "@"):
+			m.Email = strings.ToLower(match[2])
+		default:
+			m.Name = strings.TrimRight(match[2], ".-")
+		}
+
+		key := strings.ToLower(m.Email + "|" + m.Name)
+		if m.Email == "" && m.Name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, m)
+	}
+	return mentions
+}
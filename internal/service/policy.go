@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+var (
+	// ErrForbidden is returned when the actor is not allowed to perform an action
+	ErrForbidden = errors.New("you do not have permission to perform this action")
+)
+
+// Action identifies an operation subject to authorization
+type Action string
+
+const (
+	ActionCreateTicket      Action = "ticket:create"
+	ActionUpdateTicket      Action = "ticket:update"
+	ActionAssignTicket      Action = "ticket:assign"
+	ActionDeleteTicket      Action = "ticket:delete"
+	ActionViewInternal      Action = "comment:view_internal"
+	ActionEditComment       Action = "comment:edit"
+	ActionDeleteComment     Action = "comment:delete"
+	ActionCreateUser        Action = "user:create"
+	ActionUpdateUser        Action = "user:update"
+	ActionChangeUserRole    Action = "user:change_role"
+	ActionDeleteUser        Action = "user:delete"
+	ActionRecordMaintenance Action = "maintenance:record"
+	ActionManageParts       Action = "part:manage"
+	ActionRestockPart       Action = "part:restock"
+	ActionManageAssets      Action = "asset:manage"
+	ActionUpdateAsset       Action = "asset:update"
+	ActionManageWebhooks    Action = "webhook:manage"
+	ActionManageSLA         Action = "sla:manage"
+	ActionManageCalendars   Action = "calendar:manage"
+	ActionManageEscalations Action = "escalation:manage"
+	ActionManageLocations   Action = "location:manage"
+	ActionManageSchemas     Action = "asset_schema:manage"
+)
+
+// permission lists the roles allowed to perform an action on any resource and
+// the roles allowed to perform it only on resources they own
+type permission struct {
+	any   []models.UserRole
+	owner []models.UserRole
+}
+
+var (
+	allRoles        = []models.UserRole{models.UserRoleAdmin, models.UserRoleManager, models.UserRoleTechnician, models.UserRoleStaff}
+	technicianRoles = []models.UserRole{models.UserRoleAdmin, models.UserRoleManager, models.UserRoleTechnician}
+	managerRoles    = []models.UserRole{models.UserRoleAdmin, models.UserRoleManager}
+	adminRoles      = []models.UserRole{models.UserRoleAdmin}
+)
+
+// permissions is the authorization policy of the service layer
+var permissions = map[Action]permission{
+	ActionCreateTicket:      {any: allRoles},
+	ActionUpdateTicket:      {any: managerRoles, owner: []models.UserRole{models.UserRoleTechnician, models.UserRoleStaff}},
+	ActionAssignTicket:      {any: managerRoles},
+	ActionDeleteTicket:      {any: managerRoles},
+	ActionViewInternal:      {any: technicianRoles},
+	ActionEditComment:       {owner: allRoles},
+	ActionDeleteComment:     {any: managerRoles, owner: allRoles},
+	ActionCreateUser:        {any: adminRoles},
+	ActionUpdateUser:        {any: adminRoles, owner: allRoles},
+	ActionChangeUserRole:    {any: adminRoles},
+	ActionDeleteUser:        {any: adminRoles},
+	ActionRecordMaintenance: {any: technicianRoles},
+	ActionManageParts:       {any: managerRoles},
+	ActionRestockPart:       {any: technicianRoles},
+	ActionManageAssets:      {any: managerRoles},
+	ActionUpdateAsset:       {any: technicianRoles},
+	ActionManageWebhooks:    {any: adminRoles},
+	ActionManageSLA:         {any: managerRoles},
+	ActionManageCalendars:   {any: managerRoles},
+	ActionManageEscalations: {any: managerRoles},
+	ActionManageLocations:   {any: managerRoles},
+	ActionManageSchemas:     {any: adminRoles},
+}
+
+// Authorize reports whether actor may perform action. owners are the users that
+// own the target resource (e.g. a ticket's creator and assignee); roles granted
+// only ownership access are allowed when the actor is one of them.
+func Authorize(actor *models.User, action Action, owners ...uuid.UUID) error {
+	if actor == nil {
+		return ErrForbidden
+	}
+	perm, ok := permissions[action]
+	if !ok {
+		return ErrForbidden
+	}
+	if HasRole(actor, perm.any...) {
+		return nil
+	}
+	if HasRole(actor, perm.owner...) {
+		for _, owner := range owners {
+			if owner == actor.ID {
+				return nil
+			}
+		}
+	}
+	return ErrForbidden
+}
+
+// HasRole reports whether the user has one of the given roles
+func HasRole(user *models.User, roles ...models.UserRole) bool {
+	if user == nil {
+		return false
+	}
+	for _, role := range roles {
+		if user.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ticketOwners returns the users considered owners of a ticket
+func ticketOwners(ticket *models.Ticket) []uuid.UUID {
+	owners := []uuid.UUID{ticket.CreatedByID}
+	if ticket.AssignedToID != nil {
+		owners = append(owners, *ticket.AssignedToID)
+	}
+	return owners
+}
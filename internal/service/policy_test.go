@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+const (
+	admin      = models.UserRoleAdmin
+	manager    = models.UserRoleManager
+	technician = models.UserRoleTechnician
+	staff      = models.UserRoleStaff
+)
+
+var everyRole = []models.UserRole{admin, manager, technician, staff}
+
+// policyCases spells out, independently of the permissions table, the roles
+// allowed to perform each action on a resource they do not own and on one
+// they own
+var policyCases = []struct {
+	action  Action
+	anyone  []models.UserRole
+	asOwner []models.UserRole
+}{
+	{ActionCreateTicket, everyRole, everyRole},
+	{ActionUpdateTicket, []models.UserRole{admin, manager}, everyRole},
+	{ActionAssignTicket, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionDeleteTicket, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionViewInternal, []models.UserRole{admin, manager, technician}, []models.UserRole{admin, manager, technician}},
+	{ActionEditComment, nil, everyRole},
+	{ActionDeleteComment, []models.UserRole{admin, manager}, everyRole},
+	{ActionCreateUser, []models.UserRole{admin}, []models.UserRole{admin}},
+	{ActionUpdateUser, []models.UserRole{admin}, everyRole},
+	{ActionChangeUserRole, []models.UserRole{admin}, []models.UserRole{admin}},
+	{ActionDeleteUser, []models.UserRole{admin}, []models.UserRole{admin}},
+	{ActionRecordMaintenance, []models.UserRole{admin, manager, technician}, []models.UserRole{admin, manager, technician}},
+	{ActionManageParts, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionRestockPart, []models.UserRole{admin, manager, technician}, []models.UserRole{admin, manager, technician}},
+	{ActionManageAssets, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionUpdateAsset, []models.UserRole{admin, manager, technician}, []models.UserRole{admin, manager, technician}},
+	{ActionManageWebhooks, []models.UserRole{admin}, []models.UserRole{admin}},
+	{ActionManageSLA, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionManageCalendars, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionManageEscalations, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionManageLocations, []models.UserRole{admin, manager}, []models.UserRole{admin, manager}},
+	{ActionManageSchemas, []models.UserRole{admin}, []models.UserRole{admin}},
+}
+
+func TestAuthorize(t *testing.T) {
+	for _, tc := range policyCases {
+		for _, role := range everyRole {
+			actor := &models.User{Base: models.Base{ID: uuid.New()}, Role: role}
+			other := uuid.New()
+
+			err := Authorize(actor, tc.action, other)
+			if want := roleIn(tc.anyone, role); (err == nil) != want {
+				t.Errorf("%s by %s on another user's resource: got %v, want allowed=%v", tc.action, role, err, want)
+			}
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				t.Errorf("%s by %s: got %v, want ErrForbidden", tc.action, role, err)
+			}
+
+			err = Authorize(actor, tc.action, other, actor.ID)
+			if want := roleIn(tc.asOwner, role); (err == nil) != want {
+				t.Errorf("%s by %s on their own resource: got %v, want allowed=%v", tc.action, role, err, want)
+			}
+		}
+	}
+}
+
+func TestAuthorizeCoversEveryAction(t *testing.T) {
+	covered := make(map[Action]bool, len(policyCases))
+	for _, tc := range policyCases {
+		covered[tc.action] = true
+	}
+	for action := range permissions {
+		if !covered[action] {
+			t.Errorf("%s has no policy case", action)
+		}
+	}
+}
+
+func TestAuthorizeRejects(t *testing.T) {
+	actor := &models.User{Base: models.Base{ID: uuid.New()}, Role: admin}
+	if err := Authorize(nil, ActionCreateTicket); !errors.Is(err, ErrForbidden) {
+		t.Errorf("nil actor: got %v, want ErrForbidden", err)
+	}
+	if err := Authorize(actor, Action("ticket:unknown")); !errors.Is(err, ErrForbidden) {
+		t.Errorf("unknown action: got %v, want ErrForbidden", err)
+	}
+	if err := Authorize(&models.User{Role: models.UserRole("GUEST")}, ActionCreateTicket); !errors.Is(err, ErrForbidden) {
+		t.Errorf("unknown role: got %v, want ErrForbidden", err)
+	}
+}
+
+func TestCanViewInternal(t *testing.T) {
+	tests := []struct {
+		user *models.User
+		want bool
+	}{
+		{&models.User{Role: admin}, true},
+		{&models.User{Role: manager}, true},
+		{&models.User{Role: technician}, true},
+		{&models.User{Role: staff}, false},
+		{nil, false},
+	}
+	for _, tc := range tests {
+		if got := CanViewInternal(tc.user); got != tc.want {
+			t.Errorf("CanViewInternal(%v) = %v, want %v", tc.user, got, tc.want)
+		}
+	}
+}
+
+func roleIn(roles []models.UserRole, role models.UserRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
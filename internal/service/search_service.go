@@ -0,0 +1,47 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+type SearchService interface {
+	Search(viewer *models.User, query string, types []models.SearchableType, limit *int) ([]*models.SearchHit, error)
+}
+
+type searchService struct {
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(searchRepo repository.SearchRepository) SearchService {
+	return &searchService{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search runs a full-text search. Internal comments are only searched for
+// users allowed to see them.
+func (s *searchService) Search(viewer *models.User, query string, types []models.SearchableType, limit *int) ([]*models.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &ValidationError{Message: "search query must not be empty"}
+	}
+	size := repository.DefaultPageSize
+	if limit != nil {
+		size = *limit
+	}
+	if size < 1 {
+		size = 1
+	}
+	if size > repository.MaxPageSize {
+		size = repository.MaxPageSize
+	}
+	return s.searchRepo.Search(&models.SearchQuery{
+		Query:           query,
+		Types:           types,
+		IncludeInternal: CanViewInternal(viewer),
+		Limit:           size,
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/calendar"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+// slaPlanner keeps the SLA due dates of tickets up to date. Due dates count
+// working time in the business calendar of the ticket's asset from the
+// ticket's creation, pushed back by the time it spent waiting on its
+// requester.
+type slaPlanner struct {
+	policyRepo repository.SLAPolicyRepository
+	assetRepo  repository.AssetRepository
+	calendars  *calendarLookup
+}
+
+func newSLAPlanner(policyRepo repository.SLAPolicyRepository, assetRepo repository.AssetRepository, calendarRepo repository.BusinessCalendarRepository) *slaPlanner {
+	return &slaPlanner{
+		policyRepo: policyRepo,
+		assetRepo:  assetRepo,
+		calendars:  newCalendarLookup(calendarRepo, assetRepo),
+	}
+}
+
+// plan sets the due dates of a new ticket, created at now
+func (p *slaPlanner) plan(ticket *models.Ticket, now time.Time) error {
+	ticket.CreatedAt = now
+	return p.replan(ticket)
+}
+
+// update follows a change to a ticket at now: the first move out of OPEN
+// counts as the first response, waiting on the requester pauses the SLA
+// clock, and a new priority or asset may bring a different policy
+func (p *slaPlanner) update(before, ticket *models.Ticket, now time.Time) error {
+	if ticket.RespondedAt == nil && before.Status == models.TicketStatusOpen && ticket.Status != models.TicketStatusOpen {
+		ticket.RespondedAt = &now
+	}
+	switch {
+	case ticket.Status == models.TicketStatusWaiting && ticket.SLAPausedAt == nil:
+		ticket.SLAPausedAt = &now
+	case ticket.Status != models.TicketStatusWaiting && ticket.SLAPausedAt != nil:
+		cal, err := p.calendars.forAsset(before.AssetID)
+		if err != nil {
+			return err
+		}
+		paused := cal.Between(*ticket.SLAPausedAt, now).Truncate(time.Second)
+		ticket.SLAPausedSeconds += int(paused / time.Second)
+		ticket.SLAPausedAt = nil
+		if ticket.RespondedAt == nil {
+			ticket.ResponseDueAt = shift(cal, ticket.ResponseDueAt, paused)
+		}
+		ticket.ResolutionDueAt = shift(cal, ticket.ResolutionDueAt, paused)
+	}
+	if before.Priority != ticket.Priority || !sameID(before.AssetID, ticket.AssetID) {
+		return p.replan(ticket)
+	}
+	return nil
+}
+
+// replan looks up the policy and calendar for the ticket and computes its due
+// dates. The response due date is kept once the ticket was responded to.
+func (p *slaPlanner) replan(ticket *models.Ticket) error {
+	var assetType *models.AssetType
+	var location string
+	if ticket.AssetID != nil {
+		asset, err := p.assetRepo.GetByID(*ticket.AssetID)
+		if err != nil {
+			return err
+		}
+		assetType = &asset.Type
+		location = asset.Location
+	}
+	policy, err := p.policyRepo.Match(ticket.Priority, assetType)
+	if err != nil {
+		return err
+	}
+
+	ticket.SLAPolicy = nil
+	if policy == nil {
+		ticket.SLAPolicyID = nil
+		ticket.ResolutionDueAt = nil
+		if ticket.RespondedAt == nil {
+			ticket.ResponseDueAt = nil
+		}
+		return nil
+	}
+
+	cal, err := p.calendars.forLocation(location)
+	if err != nil {
+		return err
+	}
+	paused := time.Duration(ticket.SLAPausedSeconds) * time.Second
+	ticket.SLAPolicyID = &policy.ID
+	if ticket.RespondedAt == nil {
+		due := cal.Add(ticket.CreatedAt, policy.ResponseTarget()+paused)
+		ticket.ResponseDueAt = &due
+	}
+	due := cal.Add(ticket.CreatedAt, policy.ResolutionTarget()+paused)
+	ticket.ResolutionDueAt = &due
+	return nil
+}
+
+// shift moves an optional due date later by d of working time
+func shift(cal *calendar.Calendar, due *time.Time, d time.Duration) *time.Time {
+	if due == nil {
+		return nil
+	}
+	shifted := cal.Add(*due, d)
+	return &shifted
+}
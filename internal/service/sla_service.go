@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type SLAService interface {
+	CreateSLAPolicy(actor *models.User, input *CreateSLAPolicyInput) (*models.SLAPolicy, error)
+	UpdateSLAPolicy(actor *models.User, id uuid.UUID, input *UpdateSLAPolicyInput) (*models.SLAPolicy, error)
+	DeleteSLAPolicy(actor *models.User, id uuid.UUID) error
+	GetSLAPolicy(id uuid.UUID) (*models.SLAPolicy, error)
+	GetSLAPolicies() ([]*models.SLAPolicy, error)
+}
+
+type slaService struct {
+	policyRepo repository.SLAPolicyRepository
+}
+
+func NewSLAService(policyRepo repository.SLAPolicyRepository) SLAService {
+	return &slaService{policyRepo: policyRepo}
+}
+
+// CreateSLAPolicy adds a policy. Tickets opened before it keep their due
+// dates until their priority or asset changes.
+func (s *slaService) CreateSLAPolicy(actor *models.User, input *CreateSLAPolicyInput) (*models.SLAPolicy, error) {
+	if err := Authorize(actor, ActionManageSLA); err != nil {
+		return nil, err
+	}
+	policy := &models.SLAPolicy{
+		Name:              strings.TrimSpace(input.Name),
+		Priority:          input.Priority,
+		AssetType:         input.AssetType,
+		ResponseMinutes:   input.ResponseMinutes,
+		ResolutionMinutes: input.ResolutionMinutes,
+	}
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+	if err := s.policyRepo.Create(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *slaService) UpdateSLAPolicy(actor *models.User, id uuid.UUID, input *UpdateSLAPolicyInput) (*models.SLAPolicy, error) {
+	if err := Authorize(actor, ActionManageSLA); err != nil {
+		return nil, err
+	}
+	policy, err := s.GetSLAPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		policy.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.ResponseMinutes != nil {
+		policy.ResponseMinutes = *input.ResponseMinutes
+	}
+	if input.ResolutionMinutes != nil {
+		policy.ResolutionMinutes = *input.ResolutionMinutes
+	}
+	if err := s.validate(policy); err != nil {
+		return nil, err
+	}
+	if err := s.policyRepo.Update(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *slaService) DeleteSLAPolicy(actor *models.User, id uuid.UUID) error {
+	if err := Authorize(actor, ActionManageSLA); err != nil {
+		return err
+	}
+	if _, err := s.GetSLAPolicy(id); err != nil {
+		return err
+	}
+	return s.policyRepo.Delete(id)
+}
+
+func (s *slaService) GetSLAPolicy(id uuid.UUID) (*models.SLAPolicy, error) {
+	policy, err := s.policyRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "SLA policy", ID: id}
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *slaService) GetSLAPolicies() ([]*models.SLAPolicy, error) {
+	return s.policyRepo.GetAll()
+}
+
+// validate checks a policy's targets and that no other policy covers the
+// same priority and asset type
+func (s *slaService) validate(policy *models.SLAPolicy) error {
+	if policy.Name == "" {
+		return &ValidationError{Message: "SLA policy name cannot be empty"}
+	}
+	if policy.ResponseMinutes < 1 || policy.ResolutionMinutes < 1 {
+		return &ValidationError{Message: "SLA targets must be at least one minute"}
+	}
+	if policy.ResolutionMinutes < policy.ResponseMinutes {
+		return &ValidationError{Message: "resolution target cannot be shorter than the response target"}
+	}
+
+	existing, err := s.policyRepo.Match(policy.Priority, policy.AssetType)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != policy.ID && sameAssetType(existing.AssetType, policy.AssetType) {
+		return &ValidationError{Message: "an SLA policy for this priority and asset type already exists"}
+	}
+	return nil
+}
+
+func sameAssetType(a, b *models.AssetType) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Input types for service layer
+type CreateSLAPolicyInput struct {
+	Name              string                `json:"name"`
+	Priority          models.TicketPriority `json:"priority"`
+	AssetType         *models.AssetType     `json:"assetType,omitempty"`
+	ResponseMinutes   int                   `json:"responseMinutes"`
+	ResolutionMinutes int                   `json:"resolutionMinutes"`
+}
+
+type UpdateSLAPolicyInput struct {
+	Name              *string `json:"name,omitempty"`
+	ResponseMinutes   *int    `json:"responseMinutes,omitempty"`
+	ResolutionMinutes *int    `json:"resolutionMinutes,omitempty"`
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"gorm.io/datatypes"
+)
+
+// slaPolicies are the policies of the SLA tests, for every asset type
+var slaPolicies = map[models.TicketPriority]*models.SLAPolicy{
+	models.TicketPriorityMedium:   {Base: models.Base{ID: uuid.New()}, Priority: models.TicketPriorityMedium, ResponseMinutes: 120, ResolutionMinutes: 480},
+	models.TicketPriorityHigh:     {Base: models.Base{ID: uuid.New()}, Priority: models.TicketPriorityHigh, ResponseMinutes: 60, ResolutionMinutes: 240},
+	models.TicketPriorityCritical: {Base: models.Base{ID: uuid.New()}, Priority: models.TicketPriorityCritical, ResponseMinutes: 30, ResolutionMinutes: 120},
+}
+
+// officeHours is a business calendar working 09:00-17:00 UTC on weekdays
+var officeHours = &models.BusinessCalendar{
+	Name:     "Office",
+	TimeZone: "UTC",
+	WorkingHours: datatypes.JSONSlice[models.WorkingPeriod]{
+		{Weekday: models.WeekdayMonday, Start: "09:00", End: "17:00"},
+		{Weekday: models.WeekdayTuesday, Start: "09:00", End: "17:00"},
+		{Weekday: models.WeekdayWednesday, Start: "09:00", End: "17:00"},
+		{Weekday: models.WeekdayThursday, Start: "09:00", End: "17:00"},
+		{Weekday: models.WeekdayFriday, Start: "09:00", End: "17:00"},
+	},
+}
+
+func newTestSLAPlanner(cal *models.BusinessCalendar) *slaPlanner {
+	return newSLAPlanner(fakeSLAPolicyRepo{policies: slaPolicies}, nil, fakeCalendarRepo{calendar: cal})
+}
+
+// slaStep changes a ticket as the planner sees it, at a point in time
+type slaStep struct {
+	at       time.Time
+	status   models.TicketStatus
+	priority models.TicketPriority
+}
+
+// follow plans a ticket of priority created at created and applies each step
+// to it through update
+func follow(t *testing.T, p *slaPlanner, priority models.TicketPriority, created time.Time, steps ...slaStep) *models.Ticket {
+	t.Helper()
+	ticket := &models.Ticket{Status: models.TicketStatusOpen, Priority: priority}
+	if err := p.plan(ticket, created); err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range steps {
+		before := *ticket
+		if step.status != "" {
+			ticket.Status = step.status
+		}
+		if step.priority != "" {
+			ticket.Priority = step.priority
+		}
+		if err := p.update(&before, ticket, step.at); err != nil {
+			t.Fatalf("update at %s: %v", step.at, err)
+		}
+	}
+	return ticket
+}
+
+func checkDue(t *testing.T, name string, got *time.Time, want time.Time) {
+	t.Helper()
+	if got == nil || !got.Equal(want) {
+		t.Errorf("%s due at %v, want %s", name, got, want)
+	}
+}
+
+func TestSLAPlan(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	ticket := follow(t, newTestSLAPlanner(nil), models.TicketPriorityHigh, created)
+	checkDue(t, "response", ticket.ResponseDueAt, created.Add(time.Hour))
+	checkDue(t, "resolution", ticket.ResolutionDueAt, created.Add(4*time.Hour))
+	if ticket.SLAPolicyID == nil || *ticket.SLAPolicyID != slaPolicies[models.TicketPriorityHigh].ID {
+		t.Errorf("policy %v, want the HIGH policy", ticket.SLAPolicyID)
+	}
+
+	ticket = follow(t, newTestSLAPlanner(nil), models.TicketPriorityLow, created)
+	if ticket.SLAPolicyID != nil || ticket.ResponseDueAt != nil || ticket.ResolutionDueAt != nil {
+		t.Errorf("ticket without a policy has policy %v and due dates %v, %v", ticket.SLAPolicyID, ticket.ResponseDueAt, ticket.ResolutionDueAt)
+	}
+}
+
+func TestSLAFirstResponse(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	started := created.Add(20 * time.Minute)
+	ticket := follow(t, newTestSLAPlanner(nil), models.TicketPriorityHigh, created,
+		slaStep{at: started, status: models.TicketStatusInProgress},
+		slaStep{at: started.Add(time.Hour), status: models.TicketStatusOpen},
+		slaStep{at: started.Add(2 * time.Hour), status: models.TicketStatusInProgress},
+	)
+	if ticket.RespondedAt == nil || !ticket.RespondedAt.Equal(started) {
+		t.Errorf("responded at %v, want the first move out of OPEN at %s", ticket.RespondedAt, started)
+	}
+}
+
+func TestSLAPause(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	p := newTestSLAPlanner(nil)
+	ticket := follow(t, p, models.TicketPriorityHigh, created,
+		slaStep{at: created.Add(20 * time.Minute), status: models.TicketStatusWaiting},
+	)
+	if ticket.SLAPausedAt == nil || !ticket.SLAPausedAt.Equal(created.Add(20*time.Minute)) {
+		t.Fatalf("paused at %v, want when the ticket started waiting", ticket.SLAPausedAt)
+	}
+	checkDue(t, "resolution while paused", ticket.ResolutionDueAt, created.Add(4*time.Hour))
+
+	// two hours waiting on the requester push the resolution back by as much;
+	// the response was already made when the ticket left OPEN
+	before := *ticket
+	ticket.Status = models.TicketStatusInProgress
+	if err := p.update(&before, ticket, created.Add(140*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if ticket.SLAPausedAt != nil || ticket.SLAPausedSeconds != 2*60*60 {
+		t.Errorf("paused at %v for %ds, want unpaused after 7200s", ticket.SLAPausedAt, ticket.SLAPausedSeconds)
+	}
+	checkDue(t, "response", ticket.ResponseDueAt, created.Add(time.Hour))
+	checkDue(t, "resolution", ticket.ResolutionDueAt, created.Add(6*time.Hour))
+
+	// moving between active statuses leaves the due dates alone
+	before = *ticket
+	ticket.Status = models.TicketStatusOpen
+	if err := p.update(&before, ticket, created.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	checkDue(t, "resolution after reopening", ticket.ResolutionDueAt, created.Add(6*time.Hour))
+}
+
+func TestSLAPauseCountsWorkingTime(t *testing.T) {
+	// Friday 13 March 2026, an hour before closing
+	created := time.Date(2026, 3, 13, 16, 0, 0, 0, time.UTC)
+	ticket := follow(t, newTestSLAPlanner(officeHours), models.TicketPriorityHigh, created,
+		slaStep{at: created.Add(30 * time.Minute), status: models.TicketStatusWaiting},
+		// Monday at 10:00: half an hour on Friday and an hour on Monday
+		// were working time
+		slaStep{at: time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC), status: models.TicketStatusInProgress},
+	)
+	if ticket.SLAPausedSeconds != 90*60 {
+		t.Errorf("paused for %ds, want 5400s of working time", ticket.SLAPausedSeconds)
+	}
+	// four working hours from Friday 16:00 is Monday 12:00, then the pause
+	checkDue(t, "resolution", ticket.ResolutionDueAt, time.Date(2026, 3, 16, 13, 30, 0, 0, time.UTC))
+}
+
+func TestSLAReplanOnPriorityChange(t *testing.T) {
+	created := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		steps []slaStep
+		// policy is the priority of the policy the ticket ends on, empty for
+		// none
+		policy               models.TicketPriority
+		response, resolution *time.Time
+	}{
+		{
+			name:       "before the response",
+			steps:      []slaStep{{at: created.Add(10 * time.Minute), priority: models.TicketPriorityCritical}},
+			policy:     models.TicketPriorityCritical,
+			response:   ptr(created.Add(30 * time.Minute)),
+			resolution: ptr(created.Add(2 * time.Hour)),
+		},
+		{
+			name: "after the response",
+			steps: []slaStep{
+				{at: created.Add(10 * time.Minute), status: models.TicketStatusInProgress},
+				{at: created.Add(20 * time.Minute), priority: models.TicketPriorityCritical},
+			},
+			policy:     models.TicketPriorityCritical,
+			response:   ptr(created.Add(2 * time.Hour)),
+			resolution: ptr(created.Add(2 * time.Hour)),
+		},
+		{
+			name: "after a pause",
+			steps: []slaStep{
+				{at: created.Add(30 * time.Minute), status: models.TicketStatusWaiting},
+				{at: created.Add(90 * time.Minute), status: models.TicketStatusInProgress},
+				{at: created.Add(100 * time.Minute), priority: models.TicketPriorityHigh},
+			},
+			policy:     models.TicketPriorityHigh,
+			response:   ptr(created.Add(2 * time.Hour)),
+			resolution: ptr(created.Add(5 * time.Hour)),
+		},
+		{
+			name:       "to a priority without a policy",
+			steps:      []slaStep{{at: created.Add(10 * time.Minute), priority: models.TicketPriorityLow}},
+			response:   nil,
+			resolution: nil,
+		},
+		{
+			name: "to a priority without a policy after the response",
+			steps: []slaStep{
+				{at: created.Add(10 * time.Minute), status: models.TicketStatusInProgress},
+				{at: created.Add(20 * time.Minute), priority: models.TicketPriorityLow},
+			},
+			response:   ptr(created.Add(2 * time.Hour)),
+			resolution: nil,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ticket := follow(t, newTestSLAPlanner(nil), models.TicketPriorityMedium, created, tc.steps...)
+			if tc.policy == "" {
+				if ticket.SLAPolicyID != nil {
+					t.Errorf("policy %s, want none", ticket.SLAPolicyID)
+				}
+			} else if ticket.SLAPolicyID == nil || *ticket.SLAPolicyID != slaPolicies[tc.policy].ID {
+				t.Errorf("policy %v, want the %s policy", ticket.SLAPolicyID, tc.policy)
+			}
+			for name, due := range map[string][2]*time.Time{
+				"response":   {ticket.ResponseDueAt, tc.response},
+				"resolution": {ticket.ResolutionDueAt, tc.resolution},
+			} {
+				if due[1] == nil {
+					if due[0] != nil {
+						t.Errorf("%s due at %s, want no due date", name, due[0])
+					}
+					continue
+				}
+				checkDue(t, name, due[0], *due[1])
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+// stockKeeper applies changes to part stock. Every change is written to the
+// stock ledger, and a reorder ticket is opened when a part is below its
+// minimum quantity without an active one.
+type stockKeeper struct {
+	clock        clock.Clock
+	partRepo     repository.PartRepository
+	movementRepo repository.StockMovementRepository
+	ticketRepo   repository.TicketRepository
+	eventRepo    repository.TicketEventRepository
+	sla          *slaPlanner
+}
+
+func newStockKeeper(clk clock.Clock, partRepo repository.PartRepository, movementRepo repository.StockMovementRepository, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, sla *slaPlanner) *stockKeeper {
+	return &stockKeeper{
+		clock:        clk,
+		partRepo:     partRepo,
+		movementRepo: movementRepo,
+		ticketRepo:   ticketRepo,
+		eventRepo:    eventRepo,
+		sla:          sla,
+	}
+}
+
+// stockChange describes one movement of stock
+type stockChange struct {
+	PartID              uuid.UUID
+	Delta               int
+	Reason              models.StockMovementReason
+	ActorID             uuid.UUID
+	MaintenanceRecordID *uuid.UUID
+	Note                string
+}
+
+// move applies change inside tx and returns the part with its new quantity and
+// the events to publish once tx commits. It fails without changing anything
+// when the part would go out of stock.
+func (k *stockKeeper) move(tx *gorm.DB, change stockChange) (*models.Part, []events.Event, error) {
+	partRepo := k.partRepo.WithTx(tx)
+	ok, err := partRepo.ChangeQuantity(change.PartID, change.Delta)
+	if err != nil {
+		return nil, nil, err
+	}
+	part, err := partRepo.GetByID(change.PartID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, &NotFoundError{Resource: "part", ID: change.PartID}
+		}
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, &ValidationError{Message: fmt.Sprintf("insufficient stock for part %q: %d requested, %d available",
+			part.Name, -change.Delta, part.Quantity)}
+	}
+
+	err = k.movementRepo.WithTx(tx).Create(&models.StockMovement{
+		PartID:              part.ID,
+		ActorID:             &change.ActorID,
+		Reason:              change.Reason,
+		Change:              change.Delta,
+		QuantityAfter:       part.Quantity,
+		MaintenanceRecordID: change.MaintenanceRecordID,
+		Note:                change.Note,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	published, err := k.checkReorder(tx, part, change.ActorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return part, published, nil
+}
+
+// checkReorder opens a reorder ticket for a part below its minimum quantity
+// unless its reorder ticket is still active, so a part whose ticket was
+// resolved or cancelled before it was restocked gets a new one. The ticket is
+// forgotten once the part is back at or above the minimum. It returns the
+// events to publish once tx commits.
+func (k *stockKeeper) checkReorder(tx *gorm.DB, part *models.Part, actorID uuid.UUID) ([]events.Event, error) {
+	var published []events.Event
+	switch {
+	case part.BelowMinimum() && !reordering(part):
+		priority := models.TicketPriorityMedium
+		if part.Quantity == 0 {
+			priority = models.TicketPriorityHigh
+		}
+		ticket := &models.Ticket{
+			Title: fmt.Sprintf("Reorder part: %s", part.Name),
+			Description: fmt.Sprintf("Stock of %s at %s is %d, below the minimum of %d.",
+				part.Name, part.Location, part.Quantity, part.MinimumQuantity),
+			Status:      models.TicketStatusOpen,
+			Priority:    priority,
+			CreatedByID: actorID,
+		}
+		if err := k.sla.plan(ticket, k.clock.Now()); err != nil {
+			return nil, err
+		}
+		if err := k.ticketRepo.WithTx(tx).Create(ticket); err != nil {
+			return nil, err
+		}
+		if err := k.eventRepo.WithTx(tx).Create(creationEvent(ticket, nil)); err != nil {
+			return nil, err
+		}
+		part.ReorderTicketID = &ticket.ID
+		part.ReorderTicket = ticket
+		published = append(ticketCreatedEvents(ticket), events.Event{
+			Type:     events.PartLowStock,
+			PartID:   &part.ID,
+			TicketID: &ticket.ID,
+		})
+	case !part.BelowMinimum() && part.ReorderTicketID != nil:
+		part.ReorderTicketID = nil
+		part.ReorderTicket = nil
+	default:
+		return nil, nil
+	}
+	return published, k.partRepo.WithTx(tx).Update(part)
+}
+
+// reordering reports whether part has a reorder ticket that is still being
+// worked on. The ticket must have been loaded with the part.
+func reordering(part *models.Part) bool {
+	return part.ReorderTicket != nil && part.ReorderTicket.Status.IsActive()
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
+)
+
+type fakePartRepo struct {
+	repository.PartRepository
+	part *models.Part
+}
+
+func (r *fakePartRepo) WithTx(*gorm.DB) repository.PartRepository { return r }
+
+func (r *fakePartRepo) ChangeQuantity(id uuid.UUID, delta int) (bool, error) {
+	if r.part.Quantity+delta < 0 {
+		return false, nil
+	}
+	r.part.Quantity += delta
+	return true, nil
+}
+
+func (r *fakePartRepo) GetByID(uuid.UUID) (*models.Part, error) {
+	part := *r.part
+	return &part, nil
+}
+
+func (r *fakePartRepo) Update(part *models.Part) error {
+	stored := *part
+	r.part = &stored
+	return nil
+}
+
+type fakeMovementRepo struct {
+	repository.StockMovementRepository
+}
+
+func (r *fakeMovementRepo) WithTx(*gorm.DB) repository.StockMovementRepository { return r }
+
+func (r *fakeMovementRepo) Create(*models.StockMovement) error { return nil }
+
+func TestStockReorder(t *testing.T) {
+	parts := &fakePartRepo{part: &models.Part{Base: models.Base{ID: uuid.New()}, Name: "Filter", Quantity: 5, MinimumQuantity: 3}}
+	tickets := &fakeTicketRepo{tickets: map[uuid.UUID]*models.Ticket{}}
+	keeper := newStockKeeper(clock.NewFake(time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)), parts, &fakeMovementRepo{}, tickets, &fakeTicketEventRepo{}, newTestSLAPlanner(nil))
+	take := func(n int) []events.Event {
+		t.Helper()
+		_, published, err := keeper.move(nil, stockChange{PartID: parts.part.ID, Delta: -n, Reason: models.StockMovementReasonConsumption})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return published
+	}
+
+	if published := take(1); len(tickets.tickets) != 0 || len(published) != 0 {
+		t.Fatalf("opened %d tickets above the minimum", len(tickets.tickets))
+	}
+	if published := take(2); len(tickets.tickets) != 1 || !hasEvent(published, events.PartLowStock) {
+		t.Fatalf("opened %d tickets and published %v below the minimum, want one ticket", len(tickets.tickets), published)
+	}
+	first := *parts.part.ReorderTicketID
+
+	// an active reorder ticket is not raised twice
+	if take(1); len(tickets.tickets) != 1 {
+		t.Fatalf("opened %d tickets while one is active", len(tickets.tickets))
+	}
+
+	// a reorder ticket resolved before the part was restocked is replaced
+	parts.part.ReorderTicket.Status = models.TicketStatusResolved
+	if published := take(1); len(tickets.tickets) != 2 || !hasEvent(published, events.PartLowStock) {
+		t.Fatalf("opened %d tickets after the reorder ticket was resolved, want a second one", len(tickets.tickets))
+	}
+	if *parts.part.ReorderTicketID == first {
+		t.Error("part still linked to the resolved reorder ticket")
+	}
+	if parts.part.ReorderTicket.Priority != models.TicketPriorityHigh {
+		t.Errorf("reorder ticket for an empty part has priority %s, want HIGH", parts.part.ReorderTicket.Priority)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+)
+
+// Ticket fields tracked in the ticket history
+const (
+	FieldTitle       = "title"
+	FieldDescription = "description"
+	FieldStatus      = "status"
+	FieldPriority    = "priority"
+	FieldAssignedTo  = "assignedTo"
+	FieldAsset       = "asset"
+	// Escalations are recorded under their own fields, with the rule's
+	// name as the new value
+	FieldEscalation   = "escalation"
+	FieldComment      = "comment"
+	FieldNotification = "notification"
+)
+
+// ticketChanges compares a ticket before and after an update and returns one
+// event per changed field. actorID is nil for changes made by the system.
+func ticketChanges(before, after *models.Ticket, actorID *uuid.UUID) []*models.TicketEvent {
+	var events []*models.TicketEvent
+	add := func(field string, oldValue, newValue *string) {
+		if equalValues(oldValue, newValue) {
+			return
+		}
+		events = append(events, &models.TicketEvent{
+			TicketID: after.ID,
+			ActorID:  actorID,
+			Field:    field,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+
+	add(FieldTitle, stringValue(before.Title), stringValue(after.Title))
+	add(FieldDescription, stringValue(before.Description), stringValue(after.Description))
+	add(FieldStatus, stringValue(string(before.Status)), stringValue(string(after.Status)))
+	add(FieldPriority, stringValue(string(before.Priority)), stringValue(string(after.Priority)))
+	add(FieldAssignedTo, idValue(before.AssignedToID), idValue(after.AssignedToID))
+	add(FieldAsset, idValue(before.AssetID), idValue(after.AssetID))
+	return events
+}
+
+// creationEvent records the initial status of a new ticket
+func creationEvent(ticket *models.Ticket, actorID *uuid.UUID) *models.TicketEvent {
+	return &models.TicketEvent{
+		TicketID: ticket.ID,
+		ActorID:  actorID,
+		Field:    FieldStatus,
+		NewValue: stringValue(string(ticket.Status)),
+	}
+}
+
+func stringValue(s string) *string {
+	return &s
+}
+
+func idValue(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	return stringValue(id.String())
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -1,40 +1,71 @@
 package service
 
 import (
+	"errors"
+	"sort"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
 	"github.com/rixtrayker/ticketing-system/internal/models"
 	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/gorm"
 )
 
 type TicketService interface {
-	CreateTicket(input *CreateTicketInput) (*models.Ticket, error)
-	UpdateTicket(id uuid.UUID, input *UpdateTicketInput) (*models.Ticket, error)
-	DeleteTicket(id uuid.UUID) error
+	CreateTicket(actor *models.User, input *CreateTicketInput) (*models.Ticket, error)
+	UpdateTicket(actor *models.User, id uuid.UUID, input *UpdateTicketInput) (*models.Ticket, error)
+	TransitionTicket(actor *models.User, id uuid.UUID, status models.TicketStatus) (*models.Ticket, error)
+	DeleteTicket(actor *models.User, id uuid.UUID) error
 	GetTicket(id uuid.UUID) (*models.Ticket, error)
-	GetTickets(filter *models.TicketFilter) ([]*models.Ticket, error)
+	GetTickets(filter *models.TicketFilter, page *models.PageArgs) (*models.Page[*models.Ticket], error)
+	GetTicketHistory(id uuid.UUID) ([]*models.TicketEvent, error)
+	GetTicketTimeline(viewer *models.User, id uuid.UUID) ([]models.TimelineEntry, error)
 }
 
 type ticketService struct {
+	tx         repository.Transactor
 	ticketRepo repository.TicketRepository
+	eventRepo  repository.TicketEventRepository
 	userRepo   repository.UserRepository
 	assetRepo  repository.AssetRepository
+	sla        *slaPlanner
+	coupler    *assetCoupler
+	events     eventSink
 }
 
-func NewTicketService(ticketRepo repository.TicketRepository, userRepo repository.UserRepository, assetRepo repository.AssetRepository) TicketService {
+func NewTicketService(tx repository.Transactor, ticketRepo repository.TicketRepository, eventRepo repository.TicketEventRepository, userRepo repository.UserRepository, assetRepo repository.AssetRepository, slaPolicyRepo repository.SLAPolicyRepository, calendarRepo repository.BusinessCalendarRepository, statusRepo repository.AssetStatusChangeRepository, coupling AssetCouplingRules, outboxRepo repository.OutboxRepository, publisher events.Publisher) TicketService {
 	return &ticketService{
+		tx:         tx,
 		ticketRepo: ticketRepo,
+		eventRepo:  eventRepo,
 		userRepo:   userRepo,
 		assetRepo:  assetRepo,
+		sla:        newSLAPlanner(slaPolicyRepo, assetRepo, calendarRepo),
+		coupler:    newAssetCoupler(coupling, ticketRepo, assetRepo, statusRepo),
+		events:     newEventSink(outboxRepo, publisher),
 	}
 }
 
-func (s *ticketService) CreateTicket(input *CreateTicketInput) (*models.Ticket, error) {
+func (s *ticketService) CreateTicket(actor *models.User, input *CreateTicketInput) (*models.Ticket, error) {
+	if err := Authorize(actor, ActionCreateTicket); err != nil {
+		return nil, err
+	}
+	if input.AssignedToID != nil {
+		if err := Authorize(actor, ActionAssignTicket); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkReferences(input.AssignedToID, input.AssetID); err != nil {
+		return nil, err
+	}
+
 	ticket := &models.Ticket{
 		Title:       input.Title,
 		Description: input.Description,
 		Status:      models.TicketStatusOpen,
 		Priority:    input.Priority,
-		CreatedByID: input.CreatedByID,
+		CreatedByID: actor.ID,
 	}
 
 	if input.AssignedToID != nil {
@@ -44,55 +75,274 @@ func (s *ticketService) CreateTicket(input *CreateTicketInput) (*models.Ticket,
 	if input.AssetID != nil {
 		ticket.AssetID = input.AssetID
 	}
+	if err := s.sla.plan(ticket, time.Now()); err != nil {
+		return nil, err
+	}
 
-	err := s.ticketRepo.Create(ticket)
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.ticketRepo.WithTx(tx).Create(ticket); err != nil {
+			return err
+		}
+		if err := s.eventRepo.WithTx(tx).Create(creationEvent(ticket, &actor.ID)); err != nil {
+			return err
+		}
+		coupled, err := s.coupler.apply(tx, ticket, nil, &actor.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		published = append(ticketCreatedEvents(ticket), coupled...)
+		return s.events.record(tx, published...)
+	})
 	if err != nil {
 		return nil, err
 	}
+	s.events.publish(published...)
 
 	return s.ticketRepo.GetByID(ticket.ID)
 }
 
-func (s *ticketService) UpdateTicket(id uuid.UUID, input *UpdateTicketInput) (*models.Ticket, error) {
-	ticket, err := s.ticketRepo.GetByID(id)
+func (s *ticketService) UpdateTicket(actor *models.User, id uuid.UUID, input *UpdateTicketInput) (*models.Ticket, error) {
+	err := s.change(id, &actor.ID, func(ticket *models.Ticket) error {
+		if err := Authorize(actor, ActionUpdateTicket, ticketOwners(ticket)...); err != nil {
+			return err
+		}
+		if input.AssignedToID != nil && !sameID(ticket.AssignedToID, input.AssignedToID) {
+			if err := Authorize(actor, ActionAssignTicket); err != nil {
+				return err
+			}
+		}
+		assetID := input.AssetID
+		if sameID(ticket.AssetID, assetID) {
+			// a ticket stays on its asset even once that is decommissioned
+			assetID = nil
+		}
+		if err := s.checkReferences(input.AssignedToID, assetID); err != nil {
+			return err
+		}
+
+		if input.Title != nil {
+			ticket.Title = *input.Title
+		}
+		if input.Description != nil {
+			ticket.Description = *input.Description
+		}
+		if input.Status != nil && *input.Status != ticket.Status {
+			if err := applyStatus(ticket, *input.Status, time.Now()); err != nil {
+				return err
+			}
+		}
+		if input.Priority != nil {
+			ticket.Priority = *input.Priority
+		}
+		if input.AssignedToID != nil {
+			ticket.AssignedToID = input.AssignedToID
+		}
+		if input.AssetID != nil {
+			ticket.AssetID = input.AssetID
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if input.Title != nil {
-		ticket.Title = *input.Title
+	return s.ticketRepo.GetByID(id)
+}
+
+func (s *ticketService) TransitionTicket(actor *models.User, id uuid.UUID, status models.TicketStatus) (*models.Ticket, error) {
+	err := s.change(id, &actor.ID, func(ticket *models.Ticket) error {
+		if err := Authorize(actor, ActionUpdateTicket, ticketOwners(ticket)...); err != nil {
+			return err
+		}
+		return applyStatus(ticket, status, time.Now())
+	})
+	if err != nil {
+		return nil, err
 	}
-	if input.Description != nil {
-		ticket.Description = *input.Description
+
+	return s.ticketRepo.GetByID(id)
+}
+
+func (s *ticketService) DeleteTicket(actor *models.User, id uuid.UUID) error {
+	ticket, err := s.ticketRepo.GetByID(id)
+	if err != nil {
+		return err
 	}
-	if input.Status != nil {
-		ticket.Status = *input.Status
+	if err := Authorize(actor, ActionDeleteTicket); err != nil {
+		return err
 	}
-	if input.Priority != nil {
-		ticket.Priority = *input.Priority
+
+	var published []events.Event
+	err = s.tx.Transaction(func(tx *gorm.DB) error {
+		if err := s.ticketRepo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+		published, err = s.coupler.apply(tx, ticket, nil, &actor.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return err
 	}
-	if input.AssignedToID != nil {
-		ticket.AssignedToID = input.AssignedToID
+	s.events.publish(published...)
+	return nil
+}
+
+func (s *ticketService) GetTicket(id uuid.UUID) (*models.Ticket, error) {
+	return s.ticketRepo.GetByID(id)
+}
+
+func (s *ticketService) GetTickets(filter *models.TicketFilter, page *models.PageArgs) (*models.Page[*models.Ticket], error) {
+	if filter != nil && filter.SLA != nil {
+		filter.SLAAsOf = time.Now()
+	}
+	if filter != nil {
+		if err := checkMetadataFilters(filter.Metadata); err != nil {
+			return nil, err
+		}
 	}
+	return s.ticketRepo.GetAll(filter, page)
+}
+
+func (s *ticketService) GetTicketHistory(id uuid.UUID) ([]*models.TicketEvent, error) {
+	return s.eventRepo.GetByTicketID(id)
+}
 
-	err = s.ticketRepo.Update(ticket)
+// GetTicketTimeline returns the ticket's history events and comments
+// interleaved in chronological order. Internal comments are left out unless
+// the viewer may see them.
+func (s *ticketService) GetTicketTimeline(viewer *models.User, id uuid.UUID) ([]models.TimelineEntry, error) {
+	ticket, err := s.ticketRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.GetByTicketID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.ticketRepo.GetByID(id)
+	entries := make([]models.TimelineEntry, 0, len(events)+len(ticket.Comments))
+	for _, event := range events {
+		entries = append(entries, event)
+	}
+	includeInternal := CanViewInternal(viewer)
+	for i := range ticket.Comments {
+		if ticket.Comments[i].Internal && !includeInternal {
+			continue
+		}
+		entries = append(entries, &ticket.Comments[i])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].OccurredAt().Before(entries[j].OccurredAt())
+	})
+	return entries, nil
+}
+
+// change applies edit to the ticket read with its row locked, then persists
+// it together with the history events describing how it differs, in a single
+// transaction. Edits are checked and diffed against the current row, so
+// concurrent changes are neither overwritten nor misreported in the history.
+func (s *ticketService) change(id uuid.UUID, actorID *uuid.UUID, edit func(ticket *models.Ticket) error) error {
+	var published []events.Event
+	err := s.tx.Transaction(func(tx *gorm.DB) error {
+		ticket, err := s.ticketRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		before := *ticket
+		if err := edit(ticket); err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := s.sla.update(&before, ticket, now); err != nil {
+			return err
+		}
+
+		changes := ticketChanges(&before, ticket, actorID)
+		if len(changes) == 0 {
+			return nil
+		}
+		published = ticketUpdatedEvents(&before, ticket)
+		if err := s.ticketRepo.WithTx(tx).Update(ticket); err != nil {
+			return err
+		}
+		if err := s.eventRepo.WithTx(tx).Create(changes...); err != nil {
+			return err
+		}
+		coupled, err := s.coupler.apply(tx, ticket, before.AssetID, actorID, now)
+		if err != nil {
+			return err
+		}
+		published = append(published, coupled...)
+		return s.events.record(tx, published...)
+	})
+	if err != nil {
+		return err
+	}
+	s.events.publish(published...)
+	return nil
 }
 
-func (s *ticketService) DeleteTicket(id uuid.UUID) error {
-	return s.ticketRepo.Delete(id)
+// checkUser verifies that the referenced user exists
+func (s *ticketService) checkUser(id uuid.UUID) error {
+	if _, err := s.userRepo.GetByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &NotFoundError{Resource: "user", ID: id}
+		}
+		return err
+	}
+	return nil
 }
 
-func (s *ticketService) GetTicket(id uuid.UUID) (*models.Ticket, error) {
-	return s.ticketRepo.GetByID(id)
+// checkReferences verifies the optional assignee and asset of a ticket exist
+// and that the asset is still in service
+func (s *ticketService) checkReferences(assignedToID, assetID *uuid.UUID) error {
+	if assignedToID != nil {
+		if err := s.checkUser(*assignedToID); err != nil {
+			return err
+		}
+	}
+	if assetID != nil {
+		asset, err := s.assetRepo.GetByID(*assetID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &NotFoundError{Resource: "asset", ID: *assetID}
+			}
+			return err
+		}
+		if asset.Status == models.AssetStatusDecommissioned {
+			return &ValidationError{Message: "a ticket cannot be about a decommissioned asset"}
+		}
+	}
+	return nil
 }
 
-func (s *ticketService) GetTickets(filter *models.TicketFilter) ([]*models.Ticket, error) {
-	return s.ticketRepo.GetAll(filter)
+// applyStatus moves the ticket to a new status following the workflow and
+// maintains ResolvedAt: it is stamped on resolution and cleared on reopen
+func applyStatus(ticket *models.Ticket, status models.TicketStatus, now time.Time) error {
+	if !ticket.Status.CanTransitionTo(status) {
+		return &InvalidTransitionError{From: ticket.Status, To: status}
+	}
+
+	switch status {
+	case models.TicketStatusResolved:
+		ticket.ResolvedAt = &now
+	case models.TicketStatusOpen, models.TicketStatusInProgress, models.TicketStatusWaiting:
+		ticket.ResolvedAt = nil
+	}
+	ticket.Status = status
+	return nil
+}
+
+// sameID reports whether two optional IDs are equal
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
 // Input types for service layer
@@ -100,7 +350,6 @@ type CreateTicketInput struct {
 	Title        string                `json:"title"`
 	Description  string                `json:"description"`
 	Priority     models.TicketPriority `json:"priority"`
-	CreatedByID  uuid.UUID             `json:"createdById"`
 	AssignedToID *uuid.UUID            `json:"assignedToId,omitempty"`
 	AssetID      *uuid.UUID            `json:"assetId,omitempty"`
 }
@@ -111,4 +360,5 @@ type UpdateTicketInput struct {
 	Status       *models.TicketStatus   `json:"status,omitempty"`
 	Priority     *models.TicketPriority `json:"priority,omitempty"`
 	AssignedToID *uuid.UUID             `json:"assignedToId,omitempty"`
+	AssetID      *uuid.UUID             `json:"assetId,omitempty"`
 } 
\ No newline at end of file
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/auth"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+// minPasswordLength is the shortest password accepted for a user account
+const minPasswordLength = 8
+
+type UserService interface {
+	CreateUser(actor *models.User, input *CreateUserInput) (*models.User, error)
+	UpdateUser(actor *models.User, id uuid.UUID, input *UpdateUserInput) (*models.User, error)
+	DeleteUser(actor *models.User, id uuid.UUID) error
+	GetUser(id uuid.UUID) (*models.User, error)
+	GetUsers(filter *models.UserFilter, page *models.PageArgs) (*models.Page[*models.User], error)
+	UpdateNotificationPreferences(actor *models.User, input *UpdateNotificationPreferencesInput) (*models.User, error)
+}
+
+type userService struct {
+	userRepo repository.UserRepository
+}
+
+func NewUserService(userRepo repository.UserRepository) UserService {
+	return &userService{userRepo: userRepo}
+}
+
+func (s *userService) CreateUser(actor *models.User, input *CreateUserInput) (*models.User, error) {
+	if err := Authorize(actor, ActionCreateUser); err != nil {
+		return nil, err
+	}
+	passwordHash, err := hashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:                   input.Email,
+		Name:                    input.Name,
+		Role:                    input.Role,
+		PasswordHash:            passwordHash,
+		NotificationPreferences: models.DefaultNotificationPreferences(),
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) UpdateUser(actor *models.User, id uuid.UUID, input *UpdateUserInput) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := Authorize(actor, ActionUpdateUser, user.ID); err != nil {
+		return nil, err
+	}
+
+	if input.Email != nil {
+		user.Email = *input.Email
+	}
+	if input.Name != nil {
+		user.Name = *input.Name
+	}
+	if input.Role != nil && *input.Role != user.Role {
+		if err := Authorize(actor, ActionChangeUserRole); err != nil {
+			return nil, err
+		}
+		user.Role = *input.Role
+	}
+	if input.Password != nil {
+		passwordHash, err := hashPassword(*input.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.PasswordHash = passwordHash
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) DeleteUser(actor *models.User, id uuid.UUID) error {
+	if _, err := s.userRepo.GetByID(id); err != nil {
+		return err
+	}
+	if err := Authorize(actor, ActionDeleteUser); err != nil {
+		return err
+	}
+	if actor.ID == id {
+		return &ValidationError{Message: "you cannot delete your own account"}
+	}
+	return s.userRepo.Delete(id)
+}
+
+func (s *userService) GetUser(id uuid.UUID) (*models.User, error) {
+	return s.userRepo.GetByID(id)
+}
+
+func (s *userService) GetUsers(filter *models.UserFilter, page *models.PageArgs) (*models.Page[*models.User], error) {
+	return s.userRepo.GetAll(filter, page)
+}
+
+// UpdateNotificationPreferences changes which email notifications the actor
+// receives. Omitted fields keep their current value.
+func (s *userService) UpdateNotificationPreferences(actor *models.User, input *UpdateNotificationPreferencesInput) (*models.User, error) {
+	if actor == nil {
+		return nil, ErrForbidden
+	}
+	user, err := s.userRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := &user.NotificationPreferences
+	if input.OnAssignment != nil {
+		prefs.OnAssignment = *input.OnAssignment
+	}
+	if input.OnResolution != nil {
+		prefs.OnResolution = *input.OnResolution
+	}
+	if input.OnMaintenanceOverdue != nil {
+		prefs.OnMaintenanceOverdue = *input.OnMaintenanceOverdue
+	}
+	if input.OnEscalation != nil {
+		prefs.OnEscalation = *input.OnEscalation
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// hashPassword validates and hashes a new password
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", &ValidationError{Message: fmt.Sprintf("password must be at least %d characters", minPasswordLength)}
+	}
+	return auth.HashPassword(password)
+}
+
+// Input types for service layer
+type CreateUserInput struct {
+	Email    string          `json:"email"`
+	Name     string          `json:"name"`
+	Role     models.UserRole `json:"role"`
+	Password string          `json:"-"`
+}
+
+type UpdateUserInput struct {
+	Email    *string          `json:"email,omitempty"`
+	Name     *string          `json:"name,omitempty"`
+	Role     *models.UserRole `json:"role,omitempty"`
+	Password *string          `json:"-"`
+}
+
+type UpdateNotificationPreferencesInput struct {
+	OnAssignment         *bool `json:"onAssignment,omitempty"`
+	OnResolution         *bool `json:"onResolution,omitempty"`
+	OnMaintenanceOverdue *bool `json:"onMaintenanceOverdue,omitempty"`
+	OnEscalation         *bool `json:"onEscalation,omitempty"`
+}
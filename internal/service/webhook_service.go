@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// minSecretLength is the shortest signing secret accepted for a webhook
+const minSecretLength = 16
+
+type WebhookService interface {
+	CreateWebhook(actor *models.User, input *CreateWebhookInput) (*models.Webhook, error)
+	UpdateWebhook(actor *models.User, id uuid.UUID, input *UpdateWebhookInput) (*models.Webhook, error)
+	DeleteWebhook(actor *models.User, id uuid.UUID) error
+	GetWebhook(actor *models.User, id uuid.UUID) (*models.Webhook, error)
+	GetWebhooks(actor *models.User) ([]*models.Webhook, error)
+	GetDeliveries(actor *models.User, webhookID uuid.UUID, filter *models.WebhookDeliveryFilter, page *models.PageArgs) (*models.Page[*models.WebhookDelivery], error)
+	Redeliver(actor *models.User, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	clock        clock.Clock
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+}
+
+func NewWebhookService(clk clock.Clock, webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository) WebhookService {
+	return &webhookService{
+		clock:        clk,
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+	}
+}
+
+func (s *webhookService) CreateWebhook(actor *models.User, input *CreateWebhookInput) (*models.Webhook, error) {
+	if err := Authorize(actor, ActionManageWebhooks); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookSecret(input.Secret); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateEventTypes(input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		URL:         input.URL,
+		Secret:      input.Secret,
+		Description: input.Description,
+		EventTypes:  eventTypes,
+		Active:      input.Active == nil || *input.Active,
+		CreatedByID: actor.ID,
+	}
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetByID(webhook.ID)
+}
+
+func (s *webhookService) UpdateWebhook(actor *models.User, id uuid.UUID, input *UpdateWebhookInput) (*models.Webhook, error) {
+	webhook, err := s.GetWebhook(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if err := validateWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *input.URL
+	}
+	if input.Secret != nil {
+		if err := validateWebhookSecret(*input.Secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = *input.Secret
+	}
+	if input.Description != nil {
+		webhook.Description = *input.Description
+	}
+	if input.EventTypes != nil {
+		eventTypes, err := validateEventTypes(input.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		webhook.EventTypes = eventTypes
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetByID(id)
+}
+
+func (s *webhookService) DeleteWebhook(actor *models.User, id uuid.UUID) error {
+	if _, err := s.GetWebhook(actor, id); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(id)
+}
+
+func (s *webhookService) GetWebhook(actor *models.User, id uuid.UUID) (*models.Webhook, error) {
+	if err := Authorize(actor, ActionManageWebhooks); err != nil {
+		return nil, err
+	}
+	webhook, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "webhook", ID: id}
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *webhookService) GetWebhooks(actor *models.User) ([]*models.Webhook, error) {
+	if err := Authorize(actor, ActionManageWebhooks); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.GetAll()
+}
+
+func (s *webhookService) GetDeliveries(actor *models.User, webhookID uuid.UUID, filter *models.WebhookDeliveryFilter, page *models.PageArgs) (*models.Page[*models.WebhookDelivery], error) {
+	if _, err := s.GetWebhook(actor, webhookID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.GetByWebhookID(webhookID, filter, page)
+}
+
+// Redeliver queues the payload of a past delivery to be sent again. The new
+// delivery keeps the event ID, so receivers can recognise the repeat.
+func (s *webhookService) Redeliver(actor *models.User, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	if err := Authorize(actor, ActionManageWebhooks); err != nil {
+		return nil, err
+	}
+	original, err := s.deliveryRepo.GetByID(deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &NotFoundError{Resource: "webhook delivery", ID: deliveryID}
+		}
+		return nil, err
+	}
+	if original.Status == models.WebhookDeliveryStatusPending {
+		return nil, &ValidationError{Message: "delivery is still pending"}
+	}
+
+	now := s.clock.Now()
+	delivery := &models.WebhookDelivery{
+		WebhookID:      original.WebhookID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryStatusPending,
+		NextAttemptAt:  &now,
+		RedeliveryOfID: &original.ID,
+	}
+	if err := s.deliveryRepo.Enqueue(delivery); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.GetByID(delivery.ID)
+}
+
+// validateWebhookURL accepts absolute http and https URLs
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return &ValidationError{Message: fmt.Sprintf("invalid webhook URL %q: must be an absolute http or https URL", raw)}
+	}
+	return nil
+}
+
+func validateWebhookSecret(secret string) error {
+	if len(strings.TrimSpace(secret)) < minSecretLength {
+		return &ValidationError{Message: fmt.Sprintf("webhook secret must be at least %d characters", minSecretLength)}
+	}
+	return nil
+}
+
+// validateEventTypes checks a subscription list and removes duplicates
+func validateEventTypes(types []events.Type) (datatypes.JSONSlice[string], error) {
+	if len(types) == 0 {
+		return nil, &ValidationError{Message: "a webhook must subscribe to at least one event type"}
+	}
+	seen := make(map[events.Type]bool, len(types))
+	subscribed := make(datatypes.JSONSlice[string], 0, len(types))
+	for _, t := range types {
+		if !t.IsPublic() {
+			return nil, &ValidationError{Message: fmt.Sprintf("unknown webhook event type %q", t)}
+		}
+		if !seen[t] {
+			seen[t] = true
+			subscribed = append(subscribed, string(t))
+		}
+	}
+	return subscribed, nil
+}
+
+// Input types for service layer
+type CreateWebhookInput struct {
+	URL         string        `json:"url"`
+	Secret      string        `json:"secret"`
+	Description string        `json:"description"`
+	EventTypes  []events.Type `json:"eventTypes"`
+	Active      *bool         `json:"active,omitempty"`
+}
+
+type UpdateWebhookInput struct {
+	URL         *string       `json:"url,omitempty"`
+	Secret      *string       `json:"secret,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	EventTypes  []events.Type `json:"eventTypes,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rixtrayker/ticketing-system/internal/events"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/outbox"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	// ID identifies the event; redeliveries repeat it
+	ID         uuid.UUID    `json:"id"`
+	Event      events.Type  `json:"event"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Data       events.Event `json:"data"`
+}
+
+// Handler queues a delivery of each public outbox event for every webhook
+// subscribed to it
+type Handler struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	sender       *Sender
+}
+
+// NewHandler creates a Handler queueing deliveries for sender
+func NewHandler(webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository, sender *Sender) *Handler {
+	return &Handler{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+	}
+}
+
+var _ outbox.Handler = (*Handler)(nil)
+
+func (h *Handler) HandleEvent(ctx context.Context, msg outbox.Message) error {
+	if !msg.Event.Type.IsPublic() {
+		return nil
+	}
+	webhooks, err := h.webhookRepo.GetSubscribed(string(msg.Event.Type))
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         msg.ID,
+		Event:      msg.Event.Type,
+		OccurredAt: msg.RecordedAt,
+		Data:       msg.Event,
+	})
+	if err != nil {
+		return err
+	}
+	now := h.sender.clock.Now()
+	deliveries := make([]*models.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       msg.ID,
+			EventType:     string(msg.Event.Type),
+			Payload:       body,
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := h.deliveryRepo.Enqueue(deliveries...); err != nil {
+		return err
+	}
+	h.sender.Wake()
+	return nil
+}
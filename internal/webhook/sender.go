@@ -0,0 +1,192 @@
+// Package webhook delivers public domain events to the HTTP endpoints
+// registered by administrators, signing each request with the endpoint's
+// secret and retrying failures with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rixtrayker/ticketing-system/internal/clock"
+	"github.com/rixtrayker/ticketing-system/internal/models"
+	"github.com/rixtrayker/ticketing-system/internal/repository"
+)
+
+const (
+	batchSize      = 20
+	requestTimeout = 10 * time.Second
+	// lease is how long a claimed delivery is reserved for one sender; it
+	// outlasts the request timeout
+	lease = time.Minute
+	// MaxAttempts is how often a delivery is tried before it is marked FAILED
+	MaxAttempts = 8
+	minBackoff  = 30 * time.Second
+	maxBackoff  = time.Hour
+	// maxResponseBody is how much of a response is kept in the delivery log
+	maxResponseBody = 2048
+)
+
+// Sender POSTs pending deliveries to their webhooks
+type Sender struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	clock        clock.Clock
+	client       *http.Client
+	interval     time.Duration
+	logger       *log.Logger
+	wake         chan struct{}
+}
+
+// NewSender creates a Sender checking for due deliveries every interval
+func NewSender(webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository, clk clock.Clock, interval time.Duration, logger *log.Logger) *Sender {
+	return &Sender{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		clock:        clk,
+		client:       &http.Client{Timeout: requestTimeout},
+		interval:     interval,
+		logger:       logger,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Wake makes Run check for due deliveries without waiting for the next tick
+func (s *Sender) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends due deliveries immediately, then on every tick and wake-up,
+// until ctx is done
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// sendDue sends batches of due deliveries until none are left
+func (s *Sender) sendDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := s.deliveryRepo.ClaimDue(s.clock.Now(), lease, batchSize)
+		if err != nil {
+			s.logger.Printf("Webhooks: failed to claim deliveries: %v", err)
+			return
+		}
+		for _, delivery := range claimed {
+			s.send(ctx, delivery)
+			if err := s.deliveryRepo.Update(delivery); err != nil {
+				s.logger.Printf("Webhooks: failed to record delivery %s: %v", delivery.ID, err)
+			}
+		}
+		if len(claimed) < batchSize {
+			return
+		}
+	}
+}
+
+// send makes one attempt at a delivery and records its outcome on it
+func (s *Sender) send(ctx context.Context, delivery *models.WebhookDelivery) {
+	now := s.clock.Now()
+	delivery.LastAttemptAt = &now
+
+	webhook, err := s.webhookRepo.GetByID(delivery.WebhookID)
+	if err != nil {
+		s.finish(delivery, models.WebhookDeliveryStatusFailed, fmt.Sprintf("webhook unavailable: %v", err))
+		return
+	}
+	if !webhook.Active {
+		s.finish(delivery, models.WebhookDeliveryStatusFailed, "webhook is disabled")
+		return
+	}
+
+	status, body, err := s.post(ctx, webhook, delivery)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+		delivery.ResponseBody = body
+	}
+	switch {
+	case err == nil && status >= 200 && status < 300:
+		delivery.DeliveredAt = &now
+		s.finish(delivery, models.WebhookDeliveryStatusSucceeded, "")
+	case err == nil:
+		s.retry(delivery, fmt.Sprintf("endpoint responded with status %d", status))
+	default:
+		s.retry(delivery, err.Error())
+	}
+}
+
+// post sends the signed payload and returns the response status and body
+func (s *Sender) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	timestamp := s.clock.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ticketing-system-webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// retry schedules the next attempt, or gives up after MaxAttempts
+func (s *Sender) retry(delivery *models.WebhookDelivery, reason string) {
+	if delivery.Attempts >= MaxAttempts {
+		s.finish(delivery, models.WebhookDeliveryStatusFailed, reason)
+		return
+	}
+	next := s.clock.Now().Add(backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+	delivery.Error = reason
+}
+
+func (s *Sender) finish(delivery *models.WebhookDelivery, status models.WebhookDeliveryStatus, reason string) {
+	delivery.Status = status
+	delivery.NextAttemptAt = nil
+	delivery.Error = reason
+	if status == models.WebhookDeliveryStatusFailed {
+		s.logger.Printf("Webhooks: delivery %s of %s to webhook %s failed after %d attempts: %s",
+			delivery.ID, delivery.EventType, delivery.WebhookID, delivery.Attempts, reason)
+	}
+}
+
+// backoff returns the delay before retrying a delivery that failed attempts times
+func backoff(attempts int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,2 @@
+-- Store bcrypt password hashes for login
+ALTER TABLE users ADD COLUMN password_hash VARCHAR(255) NOT NULL DEFAULT '';
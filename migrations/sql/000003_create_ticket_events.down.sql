@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_ticket_events_updated_at ON ticket_events;
+DROP TABLE IF EXISTS ticket_events;
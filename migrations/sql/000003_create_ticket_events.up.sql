@@ -0,0 +1,20 @@
+-- Create ticket_events table recording every change to a ticket field
+CREATE TABLE ticket_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id),
+    actor_id UUID REFERENCES users(id),
+    field VARCHAR(64) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_ticket_events_ticket ON ticket_events(ticket_id, created_at);
+CREATE INDEX idx_ticket_events_actor ON ticket_events(actor_id);
+
+CREATE TRIGGER update_ticket_events_updated_at
+    BEFORE UPDATE ON ticket_events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
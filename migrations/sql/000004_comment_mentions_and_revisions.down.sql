@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_comment_revisions_updated_at ON comment_revisions;
+DROP TABLE IF EXISTS comment_mentions;
+DROP TABLE IF EXISTS comment_revisions;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS edited;
+ALTER TABLE comments DROP COLUMN IF EXISTS internal;
@@ -0,0 +1,30 @@
+-- Internal visibility and edit tracking for comments
+ALTER TABLE comments ADD COLUMN internal BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN edited BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMP;
+
+-- Create comment_revisions table keeping the previous content of edited comments
+CREATE TABLE comment_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id),
+    edited_by_id UUID NOT NULL REFERENCES users(id),
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create comment_mentions join table linking comments to mentioned users
+CREATE TABLE comment_mentions (
+    comment_id UUID NOT NULL REFERENCES comments(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_comment_revisions_comment ON comment_revisions(comment_id);
+CREATE INDEX idx_comment_mentions_user ON comment_mentions(user_id);
+
+CREATE TRIGGER update_comment_revisions_updated_at
+    BEFORE UPDATE ON comment_revisions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
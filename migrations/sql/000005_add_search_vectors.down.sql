@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_assets_search_vector;
+DROP INDEX IF EXISTS idx_comments_search_vector;
+DROP INDEX IF EXISTS idx_tickets_search_vector;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE comments DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tickets DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,19 @@
+-- Full-text search vectors maintained by PostgreSQL from the searchable columns
+ALTER TABLE tickets ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english'::regconfig, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE comments ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector('english'::regconfig, coalesce(content, ''))
+) STORED;
+
+ALTER TABLE assets ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english'::regconfig, coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple'::regconfig, coalesce(qr_code, '')), 'A') ||
+    setweight(to_tsvector('english'::regconfig, coalesce(location, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_tickets_search_vector ON tickets USING GIN (search_vector);
+CREATE INDEX idx_comments_search_vector ON comments USING GIN (search_vector);
+CREATE INDEX idx_assets_search_vector ON assets USING GIN (search_vector);
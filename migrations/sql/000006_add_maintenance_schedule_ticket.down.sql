@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_maintenance_schedules_ticket;
+
+ALTER TABLE maintenance_schedules DROP COLUMN IF EXISTS ticket_id;
@@ -0,0 +1,4 @@
+-- Link maintenance schedules to the ticket generated for their current occurrence
+ALTER TABLE maintenance_schedules ADD COLUMN ticket_id UUID REFERENCES tickets(id);
+
+CREATE INDEX idx_maintenance_schedules_ticket ON maintenance_schedules(ticket_id);
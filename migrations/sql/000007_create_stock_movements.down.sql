@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_stock_movements_updated_at ON stock_movements;
+DROP INDEX IF EXISTS idx_parts_location;
+
+ALTER TABLE parts DROP COLUMN IF EXISTS reorder_ticket_id;
+
+DROP TABLE IF EXISTS stock_movements;
+DROP TYPE IF EXISTS stock_movement_reason;
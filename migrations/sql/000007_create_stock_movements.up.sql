@@ -0,0 +1,33 @@
+-- Create stock movement reason enum
+CREATE TYPE stock_movement_reason AS ENUM (
+    'INITIAL',
+    'RESTOCK',
+    'ADJUSTMENT',
+    'CONSUMPTION'
+);
+
+-- Create stock_movements table recording every change to a part's quantity
+CREATE TABLE stock_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    part_id UUID NOT NULL REFERENCES parts(id),
+    actor_id UUID REFERENCES users(id),
+    reason stock_movement_reason NOT NULL,
+    change INTEGER NOT NULL,
+    quantity_after INTEGER NOT NULL,
+    maintenance_record_id UUID REFERENCES maintenance_records(id),
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Link parts to the open ticket asking for them to be reordered
+ALTER TABLE parts ADD COLUMN reorder_ticket_id UUID REFERENCES tickets(id);
+
+CREATE INDEX idx_stock_movements_part ON stock_movements(part_id, created_at);
+CREATE INDEX idx_parts_location ON parts(location);
+
+CREATE TRIGGER update_stock_movements_updated_at
+    BEFORE UPDATE ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
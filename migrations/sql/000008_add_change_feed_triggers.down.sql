@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS notify_maintenance_schedules_change ON maintenance_schedules;
+DROP TRIGGER IF EXISTS notify_assets_change ON assets;
+DROP TRIGGER IF EXISTS notify_comments_change ON comments;
+DROP TRIGGER IF EXISTS notify_tickets_change ON tickets;
+
+DROP FUNCTION IF EXISTS notify_row_change();
@@ -0,0 +1,62 @@
+-- Announce committed row changes on the row_changes channel so every server
+-- instance can react to writes made by the others. NOTIFY is delivered on
+-- commit, and payloads carry IDs only to stay well below the 8000 byte limit.
+-- Trigger arguments name reference columns to include in the payload; on
+-- UPDATE the ones whose value changed are listed in "changed".
+CREATE OR REPLACE FUNCTION notify_row_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+    old_data JSONB;
+    refs JSONB := '{}'::JSONB;
+    changed TEXT[] := '{}';
+    col TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_data := to_jsonb(OLD);
+    ELSE
+        row_data := to_jsonb(NEW);
+    END IF;
+    IF TG_OP = 'UPDATE' THEN
+        old_data := to_jsonb(OLD);
+    END IF;
+
+    FOR i IN 0 .. TG_NARGS - 1 LOOP
+        col := TG_ARGV[i];
+        refs := refs || jsonb_build_object(col, row_data -> col);
+        IF TG_OP = 'UPDATE' AND (old_data -> col) IS DISTINCT FROM (row_data -> col) THEN
+            changed := array_append(changed, col);
+        END IF;
+    END LOOP;
+
+    PERFORM pg_notify('row_changes', jsonb_build_object(
+        'table', TG_TABLE_NAME,
+        'op', TG_OP,
+        'id', row_data -> 'id',
+        'refs', refs,
+        'changed', to_jsonb(changed),
+        'origin', current_setting('application_name', true)
+    )::TEXT);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER notify_tickets_change
+    AFTER INSERT OR UPDATE OR DELETE ON tickets
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_row_change('assigned_to_id', 'asset_id');
+
+CREATE TRIGGER notify_comments_change
+    AFTER INSERT OR UPDATE OR DELETE ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_row_change('ticket_id');
+
+CREATE TRIGGER notify_assets_change
+    AFTER INSERT OR UPDATE OR DELETE ON assets
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_row_change();
+
+CREATE TRIGGER notify_maintenance_schedules_change
+    AFTER INSERT OR UPDATE OR DELETE ON maintenance_schedules
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_row_change('asset_id', 'ticket_id');
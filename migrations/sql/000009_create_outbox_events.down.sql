@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS notify_outbox_events_change ON outbox_events;
+DROP TRIGGER IF EXISTS update_outbox_events_updated_at ON outbox_events;
+
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,27 @@
+-- Create outbox_events table holding domain events written in the same
+-- transaction as the change they describe, until they have been dispatched
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE sent_at IS NULL;
+
+CREATE TRIGGER update_outbox_events_updated_at
+    BEFORE UPDATE ON outbox_events
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Wake the dispatchers as soon as new events commit
+CREATE TRIGGER notify_outbox_events_change
+    AFTER INSERT ON outbox_events
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_row_change();
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS notify_on_maintenance_overdue;
+ALTER TABLE users DROP COLUMN IF EXISTS notify_on_resolution;
+ALTER TABLE users DROP COLUMN IF EXISTS notify_on_assignment;
@@ -0,0 +1,5 @@
+-- Let users choose which email notifications they receive; all are enabled
+-- for existing users
+ALTER TABLE users ADD COLUMN notify_on_assignment BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN notify_on_resolution BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN notify_on_maintenance_overdue BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,23 @@
+-- Create notifications table logging the emails sent to users
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id),
+    kind VARCHAR(64) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
+-- A user is emailed about an outbox event once, however often the outbox
+-- hands it over
+CREATE UNIQUE INDEX idx_notifications_event ON notifications(event_id, user_id);
+
+CREATE TRIGGER update_notifications_updated_at
+    BEFORE UPDATE ON notifications
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,24 @@
+-- Create inbound_emails table logging the emails turned into tickets and
+-- comments by the inbound mail gateway
+CREATE TABLE inbound_emails (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id VARCHAR(998) NOT NULL,
+    from_address VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id),
+    ticket_id UUID NOT NULL REFERENCES tickets(id),
+    comment_id UUID REFERENCES comments(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Each email is processed once, and replies are threaded by the Message-ID
+-- they answer
+CREATE UNIQUE INDEX idx_inbound_emails_message_id ON inbound_emails(message_id);
+CREATE INDEX idx_inbound_emails_ticket ON inbound_emails(ticket_id);
+
+CREATE TRIGGER update_inbound_emails_updated_at
+    BEFORE UPDATE ON inbound_emails
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+-- Enum values cannot be dropped; move waiting tickets back to IN_PROGRESS so
+-- the value is unused
+UPDATE tickets SET status = 'IN_PROGRESS' WHERE status = 'WAITING';
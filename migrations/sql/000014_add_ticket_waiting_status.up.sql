@@ -0,0 +1,4 @@
+-- Tickets waiting on their requester; the SLA clock is paused meanwhile.
+-- Kept in its own migration because a new enum value cannot be used in the
+-- transaction that adds it.
+ALTER TYPE ticket_status ADD VALUE IF NOT EXISTS 'WAITING' AFTER 'IN_PROGRESS';
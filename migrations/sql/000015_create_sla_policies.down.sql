@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_tickets_resolution_due_at;
+DROP INDEX IF EXISTS idx_tickets_response_due_at;
+
+ALTER TABLE tickets DROP COLUMN IF EXISTS sla_paused_seconds;
+ALTER TABLE tickets DROP COLUMN IF EXISTS sla_paused_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS responded_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS resolution_due_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS response_due_at;
+ALTER TABLE tickets DROP COLUMN IF EXISTS sla_policy_id;
+
+DROP TABLE IF EXISTS sla_policies;
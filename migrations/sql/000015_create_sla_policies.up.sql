@@ -0,0 +1,33 @@
+-- Create sla_policies table holding response and resolution targets per
+-- priority, optionally narrowed to an asset type
+CREATE TABLE sla_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    priority ticket_priority NOT NULL,
+    asset_type asset_type,
+    response_minutes INTEGER NOT NULL CHECK (response_minutes > 0),
+    resolution_minutes INTEGER NOT NULL CHECK (resolution_minutes > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- One policy per priority and asset type, plus one for every asset type
+CREATE UNIQUE INDEX idx_sla_policies_scope ON sla_policies(priority, COALESCE(asset_type::TEXT, ''))
+    WHERE deleted_at IS NULL;
+
+-- Track SLA due dates on tickets
+ALTER TABLE tickets ADD COLUMN sla_policy_id UUID REFERENCES sla_policies(id);
+ALTER TABLE tickets ADD COLUMN response_due_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN resolution_due_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN responded_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN sla_paused_at TIMESTAMP;
+ALTER TABLE tickets ADD COLUMN sla_paused_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_tickets_response_due_at ON tickets(response_due_at) WHERE responded_at IS NULL;
+CREATE INDEX idx_tickets_resolution_due_at ON tickets(resolution_due_at) WHERE resolved_at IS NULL;
+
+CREATE TRIGGER update_sla_policies_updated_at
+    BEFORE UPDATE ON sla_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
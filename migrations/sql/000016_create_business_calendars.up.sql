@@ -0,0 +1,26 @@
+-- Create business_calendars table holding the working hours and holidays
+-- that SLA and maintenance due dates count in
+CREATE TABLE business_calendars (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    working_hours JSONB NOT NULL DEFAULT '[]',
+    holidays JSONB NOT NULL DEFAULT '[]',
+    locations JSONB NOT NULL DEFAULT '[]',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_business_calendars_name ON business_calendars(name) WHERE deleted_at IS NULL;
+-- At most one default calendar
+CREATE UNIQUE INDEX idx_business_calendars_is_default ON business_calendars(is_default)
+    WHERE is_default AND deleted_at IS NULL;
+-- Calendars are looked up by the locations they cover
+CREATE INDEX idx_business_calendars_locations ON business_calendars USING GIN (locations);
+
+CREATE TRIGGER update_business_calendars_updated_at
+    BEFORE UPDATE ON business_calendars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
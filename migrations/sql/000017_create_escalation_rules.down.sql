@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS notify_on_escalation;
+ALTER TABLE comments DROP COLUMN IF EXISTS system;
+
+DROP TABLE IF EXISTS ticket_escalations;
+DROP TABLE IF EXISTS escalation_rules;
+DROP TYPE IF EXISTS escalation_condition;
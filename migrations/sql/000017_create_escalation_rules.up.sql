@@ -0,0 +1,56 @@
+-- Create escalation condition enum
+CREATE TYPE escalation_condition AS ENUM (
+    'UNASSIGNED',
+    'NO_UPDATE',
+    'SLA_AT_RISK',
+    'SLA_BREACHED'
+);
+
+-- Create escalation_rules table holding the conditions tickets are escalated
+-- on and the actions taken
+CREATE TABLE escalation_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    condition escalation_condition NOT NULL,
+    priority ticket_priority,
+    after_minutes INTEGER NOT NULL DEFAULT 0 CHECK (after_minutes >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    bump_priority BOOLEAN NOT NULL DEFAULT FALSE,
+    escalate_to_id UUID REFERENCES users(id),
+    comment TEXT NOT NULL DEFAULT '',
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by_id UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- Create ticket_escalations table; a rule escalates a ticket at most once
+CREATE TABLE ticket_escalations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id),
+    rule_id UUID NOT NULL REFERENCES escalation_rules(id),
+    escalated_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_ticket_escalations_ticket_rule ON ticket_escalations(ticket_id, rule_id);
+CREATE INDEX idx_ticket_escalations_rule_id ON ticket_escalations(rule_id);
+
+-- Comments added by escalation rules
+ALTER TABLE comments ADD COLUMN system BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Escalation emails are enabled for existing users
+ALTER TABLE users ADD COLUMN notify_on_escalation BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TRIGGER update_escalation_rules_updated_at
+    BEFORE UPDATE ON escalation_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_ticket_escalations_updated_at
+    BEFORE UPDATE ON ticket_escalations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
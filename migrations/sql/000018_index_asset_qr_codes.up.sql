@@ -0,0 +1,2 @@
+-- QR codes are looked up case-insensitively when typed in from a label
+CREATE INDEX idx_assets_qr_code_upper ON assets (UPPER(qr_code));
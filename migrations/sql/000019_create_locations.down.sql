@@ -0,0 +1,5 @@
+ALTER TABLE assets DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE assets DROP COLUMN IF EXISTS location_id;
+
+DROP TABLE IF EXISTS locations;
+DROP TYPE IF EXISTS location_kind;
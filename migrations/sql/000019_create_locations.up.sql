@@ -0,0 +1,34 @@
+-- Locations form the site → building → floor → room hierarchy assets are
+-- installed at
+CREATE TYPE location_kind AS ENUM ('SITE', 'BUILDING', 'FLOOR', 'ROOM');
+
+CREATE TABLE locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    kind location_kind NOT NULL,
+    parent_id UUID REFERENCES locations(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    -- Only sites are roots
+    CONSTRAINT chk_locations_parent CHECK ((kind = 'SITE') = (parent_id IS NULL))
+);
+
+CREATE INDEX idx_locations_parent_id ON locations(parent_id);
+-- Names are unique among the children of a location, and among sites
+CREATE UNIQUE INDEX idx_locations_parent_name
+    ON locations(COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), LOWER(name))
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_locations_updated_at
+    BEFORE UPDATE ON locations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Assets are placed in the hierarchy and may be components of another asset
+ALTER TABLE assets
+    ADD COLUMN location_id UUID REFERENCES locations(id),
+    ADD COLUMN parent_id UUID REFERENCES assets(id);
+
+CREATE INDEX idx_assets_location_id ON assets(location_id);
+CREATE INDEX idx_assets_parent_id ON assets(parent_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS asset_status_changes;
@@ -0,0 +1,30 @@
+-- Create asset_status_changes table recording every asset status change and
+-- its cause, from which downtime is computed
+CREATE TABLE asset_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    asset_id UUID NOT NULL REFERENCES assets(id),
+    from_status asset_status,
+    to_status asset_status NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    changed_by_id UUID REFERENCES users(id),
+    ticket_id UUID REFERENCES tickets(id),
+    maintenance_record_id UUID REFERENCES maintenance_records(id),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_asset_status_changes_asset_changed_at ON asset_status_changes(asset_id, changed_at);
+
+-- Existing assets start their history in their current status; earlier
+-- changes were not recorded
+INSERT INTO asset_status_changes (asset_id, to_status, changed_at, reason)
+SELECT id, status, created_at, 'status before history was recorded'
+FROM assets
+WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_asset_status_changes_updated_at
+    BEFORE UPDATE ON asset_status_changes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1 @@
+ALTER TABLE asset_status_changes DROP COLUMN IF EXISTS automatic;
@@ -0,0 +1,3 @@
+-- Mark asset status changes made by ticket coupling rules, which the
+-- coupling may undo once the tickets behind them are resolved
+ALTER TABLE asset_status_changes ADD COLUMN automatic BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_assets_metadata;
+DROP TABLE IF EXISTS asset_type_schemas;
@@ -0,0 +1,23 @@
+-- Create asset_type_schemas table holding the JSON Schema the metadata of
+-- each asset type must satisfy
+CREATE TABLE asset_type_schemas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    type asset_type NOT NULL,
+    schema JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- One schema per asset type
+CREATE UNIQUE INDEX idx_asset_type_schemas_type ON asset_type_schemas(type)
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER update_asset_type_schemas_updated_at
+    BEFORE UPDATE ON asset_type_schemas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Index asset metadata for the containment (@>) and JSON path (@@) operators
+-- metadata filters use
+CREATE INDEX idx_assets_metadata ON assets USING GIN (metadata jsonb_path_ops);
@@ -0,0 +1,10 @@
+-- Track which handlers an outbox event was delivered to, so a retry only
+-- calls the handlers that failed, and park events that keep failing
+ALTER TABLE outbox_events
+    ADD COLUMN delivered_to JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN dead_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at)
+    WHERE sent_at IS NULL AND dead_at IS NULL;
+